@@ -1,17 +1,26 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/kukwuka/queue/internal/domain"
 	"github.com/kukwuka/queue/internal/domain/queue"
 	"github.com/kukwuka/queue/internal/domain/queues"
+	"github.com/kukwuka/queue/internal/domain/topics"
+	"github.com/kukwuka/queue/internal/infrastructure/wal"
 	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
 )
 
@@ -20,20 +29,191 @@ func makeConfig() config {
 	const (
 		timeOutFlag           = "timeout"
 		portFlag              = "port"
-		queueMaxSizeFlag      = "queueMaxSize"
 		queuesMaxCountFlag    = "queuesMaxCount"
-		defaultQueueMaxSize   = 2
+		dataDirFlag           = "dataDir"
+		fsyncFlag             = "fsync"
+		fsyncIntervalFlag     = "fsyncInterval"
+		segmentSizeFlag       = "segmentSize"
+		idleQueueTTLFlag      = "idleQueueTTL"
+		deadLetterSuffixFlag  = "deadLetterSuffix"
+		explicitCreateFlag    = "explicitCreate"
+		shutdownTimeoutFlag   = "shutdownTimeout"
 		defaultQueuesMaxCount = 2
+		defaultSegmentSize    = 64 << 20
+		defaultShutdown       = 10 * time.Second
 	)
 	var configInstance config
 	flag.DurationVar(&configInstance.TimeOut, timeOutFlag, time.Second, "timeout for handlers")
 	flag.StringVar(&configInstance.Port, portFlag, "8080", "port for server")
-	flag.IntVar(&configInstance.QueueMaxSize, queueMaxSizeFlag, defaultQueueMaxSize, "queue max size")
 	flag.IntVar(&configInstance.QueuesMaxCount, queuesMaxCountFlag, defaultQueuesMaxCount, "max count of queues")
+	flag.StringVar(&configInstance.DataDir, dataDirFlag, "", "dir for write-ahead log, empty keeps queues in memory")
+	flag.StringVar(&configInstance.Fsync, fsyncFlag, string(wal.SyncAlways), "fsync policy: always, interval, never")
+	flag.DurationVar(&configInstance.FsyncInterval, fsyncIntervalFlag, time.Second, "fsync period for interval policy")
+	flag.Int64Var(&configInstance.SegmentSize, segmentSizeFlag, defaultSegmentSize,
+		"write-ahead log segment size in bytes")
+	flag.DurationVar(&configInstance.IdleQueueTTL, idleQueueTTLFlag, 0,
+		"delete queues that stay empty without consumers this long, 0 disables")
+	flag.StringVar(&configInstance.DeadLetterSuffix, deadLetterSuffixFlag, "",
+		"undelivered messages of queue go to queue with this suffix, empty drops them")
+	flag.BoolVar(&configInstance.ExplicitCreate, explicitCreateFlag, false,
+		"queues must be created with PUT /queues/{queue}, unknown queues return 404")
+	flag.DurationVar(&configInstance.ShutdownTimeout, shutdownTimeoutFlag, defaultShutdown,
+		"how long to wait for started requests on SIGTERM before closing connections")
+	bindQueueFlags(&configInstance)
 	flag.Parse()
 	return configInstance
 }
 
+// bindQueueFlags Настройки отдельной очереди: общие и переопределения в формате queue:value,queue:value.
+func bindQueueFlags(configInstance *config) {
+	const (
+		queueMaxSizeFlag     = "queueMaxSize"
+		overflowFlag         = "overflowPolicy"
+		queueOverflowFlag    = "queueOverflowPolicies"
+		messageTTLFlag       = "messageTTL"
+		queueMessageTTLFlag  = "queueMessageTTLs"
+		maxReceivesFlag      = "maxReceives"
+		queueMaxReceivesFlag = "queueMaxReceives"
+		queueTypeFlag        = "queueType"
+		queueTypesFlag       = "queueTypes"
+		maxMessageBytesFlag  = "maxMessageBytes"
+		dedupWindowFlag      = "dedupWindow"
+		queueDedupWindowFlag = "queueDedupWindows"
+		defaultQueueMaxSize  = 2
+		defaultDedupWindow   = 5 * time.Minute
+	)
+	flag.IntVar(&configInstance.QueueMaxSize, queueMaxSizeFlag, defaultQueueMaxSize, "queue max size")
+	flag.StringVar(&configInstance.Overflow, overflowFlag, string(domain.OverflowBlock),
+		"what to do when queue is full: block, reject, dropOldest, dropNewest")
+	flag.StringVar(&configInstance.QueueOverflow, queueOverflowFlag, "",
+		"overflow policy for specific queues, like orders:reject,logs:dropOldest")
+	flag.DurationVar(&configInstance.MessageTTL, messageTTLFlag, 0, "default message time to live, 0 keeps forever")
+	flag.StringVar(&configInstance.QueueMessageTTL, queueMessageTTLFlag, "",
+		"default message time to live for specific queues, like orders:1m,logs:10s")
+	flag.IntVar(&configInstance.MaxReceives, maxReceivesFlag, 0,
+		"lease attempts before message is undelivered, 0 retries forever")
+	flag.StringVar(&configInstance.QueueMaxReceives, queueMaxReceivesFlag, "",
+		"lease attempts for specific queues, like orders:5,logs:1")
+	flag.StringVar(&configInstance.QueueType, queueTypeFlag, string(domain.QueueTypeFIFO),
+		"delivery order: fifo, priority, log")
+	flag.StringVar(&configInstance.QueueTypes, queueTypesFlag, "",
+		"delivery order for specific queues, like jobs:priority,events:log")
+	flag.IntVar(&configInstance.MaxMessageBytes, maxMessageBytesFlag, 0, "max message size in bytes, 0 is unlimited")
+	flag.DurationVar(&configInstance.DedupWindow, dedupWindowFlag, defaultDedupWindow,
+		"how long to remember Idempotency-Key of put, 0 disables deduplication")
+	flag.StringVar(&configInstance.QueueDedupWindow, queueDedupWindowFlag, "",
+		"deduplication window for specific queues, like orders:1h,logs:1m")
+}
+
+// parseQueueConfigs Общие настройки плюс переопределения в формате queue:value,queue:value.
+func parseQueueConfigs(configInstance config) (domain.QueueConfigs, error) {
+	defaultConfig, err := parseDefaultQueueConfig(configInstance)
+	if err != nil {
+		return domain.QueueConfigs{}, err
+	}
+	configs := domain.QueueConfigs{
+		Default: defaultConfig,
+		ByQueue: make(map[string]domain.QueueConfig),
+	}
+	for _, override := range queueConfigOverrides(configInstance) {
+		err = parseByQueue(override.values, configs, override.apply)
+		if err != nil {
+			return configs, err
+		}
+	}
+	return configs, validateQueueConfigs(configs)
+}
+
+// validateQueueConfigs Настройки из флагов проверяем так же, как созданные через PUT /queues/:queue.
+func validateQueueConfigs(configs domain.QueueConfigs) error {
+	err := configs.Default.Validate()
+	if err != nil {
+		return fmt.Errorf("default queue config: %w", err)
+	}
+	for queueName, config := range configs.ByQueue {
+		err = config.Validate()
+		if err != nil {
+			return fmt.Errorf("queue %s: %w", queueName, err)
+		}
+	}
+	return nil
+}
+
+func parseDefaultQueueConfig(configInstance config) (domain.QueueConfig, error) {
+	overflow, err := domain.ParseOverflowPolicy(configInstance.Overflow)
+	if err != nil {
+		return domain.QueueConfig{}, err //nolint:wrapcheck
+	}
+	queueType, err := domain.ParseQueueType(configInstance.QueueType)
+	if err != nil {
+		return domain.QueueConfig{}, err //nolint:wrapcheck
+	}
+	return domain.QueueConfig{
+		Type:            queueType,
+		MaxLen:          configInstance.QueueMaxSize,
+		Overflow:        overflow,
+		MessageTTL:      configInstance.MessageTTL,
+		MaxReceives:     configInstance.MaxReceives,
+		MaxMessageBytes: configInstance.MaxMessageBytes,
+		DedupWindow:     configInstance.DedupWindow,
+	}, nil
+}
+
+type queueConfigOverride struct {
+	values string
+	apply  func(value string, config *domain.QueueConfig) error
+}
+
+func queueConfigOverrides(configInstance config) []queueConfigOverride {
+	return []queueConfigOverride{
+		{configInstance.QueueOverflow, func(value string, config *domain.QueueConfig) (err error) {
+			config.Overflow, err = domain.ParseOverflowPolicy(value)
+			return err //nolint:wrapcheck
+		}},
+		{configInstance.QueueMessageTTL, func(value string, config *domain.QueueConfig) (err error) {
+			config.MessageTTL, err = time.ParseDuration(value)
+			return err //nolint:wrapcheck
+		}},
+		{configInstance.QueueMaxReceives, func(value string, config *domain.QueueConfig) (err error) {
+			config.MaxReceives, err = strconv.Atoi(value)
+			return err //nolint:wrapcheck
+		}},
+		{configInstance.QueueTypes, func(value string, config *domain.QueueConfig) (err error) {
+			config.Type, err = domain.ParseQueueType(value)
+			return err //nolint:wrapcheck
+		}},
+		{configInstance.QueueDedupWindow, func(value string, config *domain.QueueConfig) (err error) {
+			config.DedupWindow, err = time.ParseDuration(value)
+			return err //nolint:wrapcheck
+		}},
+	}
+}
+
+// parseByQueue Разбирает queue:value,queue:value и применяет значения поверх настроек очереди.
+// Пара без двоеточия - ошибка, иначе опечатка во флаге молча оставила бы настройки по умолчанию.
+func parseByQueue(
+	values string,
+	configs domain.QueueConfigs,
+	apply func(value string, config *domain.QueueConfig) error,
+) error {
+	if values == "" {
+		return nil
+	}
+	for _, pair := range strings.Split(values, ",") {
+		queueName, value, found := strings.Cut(pair, ":")
+		if !found {
+			return fmt.Errorf("invalid queue:value pair %q", pair)
+		}
+		config := configs.For(queueName)
+		err := apply(value, &config)
+		if err != nil {
+			return fmt.Errorf("queue %s: %w", queueName, err)
+		}
+		configs.ByQueue[queueName] = config
+	}
+	return nil
+}
+
 func main() {
 	configInstance := makeConfig()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
@@ -43,31 +223,231 @@ func main() {
 	}
 	logger.Info("start with", "config", string(configPayload))
 
-	// По слоенной архитектуре еще должны быть юзкейсы, ну стал из делать
-	// Т.к. В данном случае они бесполезны и буду просто вызывать доменный сервис.
-	queuesInstance := queues.NewQueues(newQ, configInstance.QueueMaxSize, configInstance.QueuesMaxCount)
+	storageInstance, err := makeStorage(configInstance)
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	defer storageInstance.close()
+
+	queuesInstance, err := makeQueues(configInstance, storageInstance)
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
 	defer queuesInstance.Close()
 
-	mux := appHTTP.NewRouter(queuesInstance, logger)
+	stopJanitor := startJanitor(queuesInstance, configInstance.IdleQueueTTL)
+	defer stopJanitor()
+
+	mux := appHTTP.NewRouter(
+		queuesInstance,
+		topics.NewTopics(queuesInstance),
+		topics.NewExchanges(queuesInstance),
+		logger,
+	)
 
 	var handler http.Handler = mux
 	// Эта мидлвара должна быть в pkg нашей команды, но ладно, пока так.
 	handler = appHTTP.NewTimeoutMiddleware(handler, configInstance.TimeOut)
 
-	err = http.ListenAndServe(":"+configInstance.Port, handler) //nolint:gosec
+	server := &http.Server{
+		Addr:              ":" + configInstance.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: configInstance.TimeOut,
+	}
+	serve(server, queuesInstance, configInstance.ShutdownTimeout, logger)
+}
+
+// serve Работает до SIGTERM или SIGINT, потом останавливается в два этапа:
+// сначала Drain - новые сообщения получают 503, ждущие получатели отпускаются,
+// затем ждем начатые запросы не дольше shutdownTimeout и рвем оставшиеся соединения.
+// Очереди и журнал закрываются уже после, в defer у main.
+func serve(server *http.Server, queuesInstance *queues.Queues, shutdownTimeout time.Duration, logger *slog.Logger) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-serveErr:
+		logger.Error(err.Error())
+		return
+	case <-ctx.Done():
+	}
+	logger.Info("shutting down", "timeout", shutdownTimeout.String())
+	queuesInstance.Drain()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		logger.Error(fmt.Errorf("shutdown: %w", err).Error())
+		_ = server.Close()
+	}
+}
+
+// По слоенной архитектуре еще должны быть юзкейсы, ну стал из делать
+// Т.к. В данном случае они бесполезны и буду просто вызывать доменный сервис.
+func makeQueues(configInstance config, storageInstance storage) (*queues.Queues, error) {
+	queueConfigs, err := parseQueueConfigs(configInstance)
+	if err != nil {
+		return nil, err
+	}
+	err = checkJournaled(configInstance, queueConfigs)
+	if err != nil {
+		return nil, err
+	}
+	queuesInstance := queues.NewQueues(
+		storageInstance.factory,
+		configInstance.QueuesMaxCount,
+		queueConfigs,
+		configInstance.DeadLetterSuffix,
+	)
+	if configInstance.ExplicitCreate {
+		queuesInstance.RequireExplicitCreate()
+	}
+	if storageInstance.configStore != nil {
+		queuesInstance.RejectQueueTypes(domain.QueueTypeLog)
+		queuesInstance.StoreConfigs(storageInstance.configStore)
+	}
+	err = restore(queuesInstance, storageInstance)
+	if err != nil {
+		queuesInstance.Close()
+		return nil, err
+	}
+	return queuesInstance, nil
+}
+
+// restore Сначала очереди с их настройками, потом остальные очереди с сообщениями.
+func restore(queuesInstance *queues.Queues, storageInstance storage) error {
+	err := queuesInstance.RestoreConfigs(storageInstance.configs)
 	if err != nil {
-		log.Println(err.Error())
+		return err //nolint:wrapcheck
+	}
+	return queuesInstance.Restore(storageInstance.restored) //nolint:wrapcheck
+}
+
+// checkJournaled Лог в журнал не пишется, с dataDir он молча терял бы сообщения и смещения групп при рестарте.
+func checkJournaled(configInstance config, queueConfigs domain.QueueConfigs) error {
+	if configInstance.DataDir == "" {
+		return nil
+	}
+	if queueConfigs.Default.Type == domain.QueueTypeLog {
+		return errors.New("log queues are not journaled, -queueType=log can't be used with -dataDir")
+	}
+	for queueName, queueConfig := range queueConfigs.ByQueue {
+		if queueConfig.Type == domain.QueueTypeLog {
+			return fmt.Errorf("log queues are not journaled, queue %s can't be log with -dataDir", queueName)
+		}
 	}
+	return nil
 }
 
+// storage Где живут очереди и что из них восстановить после рестарта.
+// ConfigStore nil, если очереди живут только в памяти.
+type storage struct {
+	factory     domain.QueueFactory
+	configStore domain.QueueConfigStore
+	configs     map[string]domain.QueueConfig
+	restored    []string
+	close       func()
+}
+
+// makeStorage Без dataDir очереди живут только в памяти, иначе поверх журнала.
+func makeStorage(configInstance config) (storage, error) {
+	if configInstance.DataDir == "" {
+		return storage{factory: newQ, close: noop}, nil
+	}
+	syncPolicy, err := parseSyncPolicy(configInstance)
+	if err != nil {
+		return storage{}, err
+	}
+	store, err := wal.NewStore(wal.Config{
+		Dir:          configInstance.DataDir,
+		SyncPolicy:   syncPolicy,
+		SyncInterval: configInstance.FsyncInterval,
+		SegmentSize:  configInstance.SegmentSize,
+	})
+	if err != nil {
+		return storage{}, err //nolint:wrapcheck
+	}
+	closeStore := func() {
+		err := store.Close()
+		if err != nil {
+			log.Println(err.Error())
+		}
+	}
+	return storage{
+		factory:     store.Factory,
+		configStore: store,
+		configs:     store.Configs(),
+		restored:    store.Names(),
+		close:       closeStore,
+	}, nil
+}
+
+// parseSyncPolicy Нулевой или отрицательный -fsyncInterval не период, а ошибка в флагах.
+func parseSyncPolicy(configInstance config) (wal.SyncPolicy, error) {
+	syncPolicy, err := wal.ParseSyncPolicy(configInstance.Fsync)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+	if syncPolicy == wal.SyncInterval && configInstance.FsyncInterval <= 0 {
+		return "", fmt.Errorf("-fsyncInterval must be positive with -fsync=interval, got %s", configInstance.FsyncInterval)
+	}
+	return syncPolicy, nil
+}
+
+// startJanitor Чистим чаще ttl, иначе пустая очередь может прожить почти два ttl.
+func startJanitor(queuesInstance *queues.Queues, idleQueueTTL time.Duration) func() {
+	const sweepsPerTTL = 4
+	if idleQueueTTL <= 0 {
+		return noop
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	janitor := queues.NewJanitor(queuesInstance, idleQueueTTL, time.Now)
+	go janitor.Run(ctx, idleQueueTTL/sweepsPerTTL)
+	return cancel
+}
+
+func noop() {}
+
 // Обертка над дженериками, компилятор все еще не понимает.
-func newQ(maxLen int) domain.Queue { //nolint:ireturn
-	return queue.NewQueue[string](maxLen)
+func newQ(_ string, queueConfig domain.QueueConfig, deadLetter domain.DeadLetter) domain.Queue { //nolint:ireturn
+	if queueConfig.Type == domain.QueueTypeLog {
+		return queue.NewLog[domain.Message](queueConfig)
+	}
+	q := queue.NewQueue[domain.Message](queueConfig)
+	q.OnDeliver(domain.Message.WithDeliveryCount)
+	if deadLetter != nil {
+		q.OnUndelivered(deadLetter)
+	}
+	return q
 }
 
 type config struct {
-	Port           string        `json:"port"`
-	TimeOut        time.Duration `json:"timeOut"`
-	QueueMaxSize   int           `json:"queueMaxSize"`
-	QueuesMaxCount int           `json:"queuesMaxCount"`
+	Port             string        `json:"port"`
+	TimeOut          time.Duration `json:"timeOut"`
+	QueueMaxSize     int           `json:"queueMaxSize"`
+	QueuesMaxCount   int           `json:"queuesMaxCount"`
+	DataDir          string        `json:"dataDir"`
+	Fsync            string        `json:"fsync"`
+	FsyncInterval    time.Duration `json:"fsyncInterval"`
+	SegmentSize      int64         `json:"segmentSize"`
+	Overflow         string        `json:"overflowPolicy"`
+	QueueOverflow    string        `json:"queueOverflowPolicies"`
+	IdleQueueTTL     time.Duration `json:"idleQueueTTL"`
+	MessageTTL       time.Duration `json:"messageTTL"`
+	QueueMessageTTL  string        `json:"queueMessageTTLs"`
+	DeadLetterSuffix string        `json:"deadLetterSuffix"`
+	ExplicitCreate   bool          `json:"explicitCreate"`
+	MaxReceives      int           `json:"maxReceives"`
+	QueueMaxReceives string        `json:"queueMaxReceives"`
+	QueueType        string        `json:"queueType"`
+	QueueTypes       string        `json:"queueTypes"`
+	MaxMessageBytes  int           `json:"maxMessageBytes"`
+	DedupWindow      time.Duration `json:"dedupWindow"`
+	QueueDedupWindow string        `json:"queueDedupWindows"`
+	ShutdownTimeout  time.Duration `json:"shutdownTimeout"`
 }
@@ -1,28 +1,462 @@
 package domain
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 var (
-	ErrMessageWaitTimeOut  = errors.New("didn't wait for the message")
-	ErrMaxCountQueuesCount = errors.New("maximum of count queues")
+	ErrMessageWaitTimeOut    = errors.New("didn't wait for the message")
+	ErrMaxCountQueuesCount   = errors.New("maximum of count queues")
+	ErrReceiptNotFound       = errors.New("receipt not found")
+	ErrQueueFull             = errors.New("queue is full")
+	ErrQueueNotFound         = errors.New("queue not found")
+	ErrQueueClosed           = errors.New("queue is closed")
+	ErrUnknownOverflowPolicy = errors.New("unknown overflow policy")
+	ErrNoDeadLetterQueue     = errors.New("dead letter queue is not configured")
+	ErrUnknownQueueType      = errors.New("unknown queue type")
+	ErrQueueExists           = errors.New("queue already exists")
+	ErrInvalidQueueConfig    = errors.New("invalid queue config")
+	ErrMessageTooLarge       = errors.New("message is too large")
+	ErrShuttingDown          = errors.New("shutting down")
+	ErrSubscriptionNotFound  = errors.New("subscription not found")
+	ErrBindingNotFound       = errors.New("binding not found")
+	ErrBadPattern            = errors.New("bad binding pattern")
+	ErrGroupRequired         = errors.New("log queue is read by consumer group")
+	ErrNotALog               = errors.New("queue is not a log")
+	ErrInvalidOffset         = errors.New("invalid offset")
+	ErrNotSupported          = errors.New("not supported by queue type")
+	ErrIdempotencyKeyInUse   = errors.New("message with this idempotency key is still being put")
 )
 
 // Queue -абстракция отвечающая за логику работы внутри 1 очереди.
 type Queue interface {
-	GetMessage(ctx context.Context) (string, error)
-	PutMessage(ctx context.Context, message string) error
+	Consumer
+	Producer
+	Leaser
+	Managed
+	Lifecycle
+}
+
+// Lifecycle - остановка: Drain отпускает ждущих с ErrShuttingDown и больше ждать не дает,
+// но то что уже лежит, можно забрать. Close закрывает совсем, дальше ErrQueueClosed.
+type Lifecycle interface {
+	Drain()
 	Close()
 }
 
+// LifecycleState Состояние очереди и оркестратора, меняется только вперед: open -> draining -> closed,
+// из open можно сразу в closed.
+type LifecycleState string
+
+const (
+	StateOpen     LifecycleState = "open"
+	StateDraining LifecycleState = "draining"
+	StateClosed   LifecycleState = "closed"
+)
+
+// Advance Следующее состояние, если переход разрешен, назад и на месте - нет.
+func (state LifecycleState) Advance(to LifecycleState) (LifecycleState, bool) {
+	switch {
+	case state == StateOpen && to != StateOpen, state == StateDraining && to == StateClosed:
+		return to, true
+	default:
+		return state, false
+	}
+}
+
+// Consumer - получение сообщений, GetMessages отдает до limit сообщений за раз.
+type Consumer interface {
+	GetMessage(ctx context.Context) (Message, error)
+	GetMessages(ctx context.Context, limit int) ([]Message, error)
+}
+
+// Producer - отправка сообщений, PutMessages кладет пачку по порядку: либо всю, либо ничего.
+type Producer interface {
+	PutMessage(ctx context.Context, message Message, options PutOptions) error
+	PutMessages(ctx context.Context, messages []Message, options PutOptions) error
+}
+
+// Message Сообщение с метаданными. ID и EnqueuedAt проставляет оркестратор при приеме,
+// DeliveryCount - очередь при выдаче. Body сервер не разбирает: это может быть и текст, и protobuf.
+type Message struct {
+	ID          string
+	Body        []byte
+	Headers     map[string]string
+	ContentType string
+	// Raw Тело положили как есть, а не в JSON: GET отдает его так же, байт в байт и с ContentType.
+	Raw        bool
+	EnqueuedAt time.Time
+	// DeliveryCount Какая это по счету выдача сообщения, включая текущую. В Peek - сколько раз уже выдавали.
+	DeliveryCount int
+}
+
+// Stamp Проставляет ID и время приема, если их еще нет: перенесенное между очередями сообщение их сохраняет.
+func (message Message) Stamp(now time.Time) Message {
+	if message.ID == "" {
+		message.ID = uuid.NewString()
+	}
+	if message.EnqueuedAt.IsZero() {
+		message.EnqueuedAt = now
+	}
+	return message
+}
+
+// WithDeliveryCount Для очередей, которые сами считают выдачи.
+func (message Message) WithDeliveryCount(count int) Message {
+	message.DeliveryCount = count
+	return message
+}
+
+// PutOptions Параметры отправки, для пачки общие.
+type PutOptions struct {
+	// DeliverAt Сообщение станет доступно получателям не раньше этого времени, нулевое - сразу.
+	DeliverAt time.Time
+	// ExpiresAt После этого времени сообщение не доставляется, нулевое - по умолчанию для очереди.
+	ExpiresAt time.Time
+	// Priority Чем больше, тем раньше выдается, учитывается только в очереди QueueTypePriority.
+	Priority int
+	// IdempotencyKey Повторная отправка с тем же ключом в пределах QueueConfig.DedupWindow не кладет сообщения
+	// еще раз, а отдает id первой. Учитывает оркестратор, сами очереди ключ не смотрят.
+	IdempotencyKey string
+}
+
+// WithDefaultTTL Проставляет срок жизни по умолчанию, если он не задан, отсчитываем от момента доставки.
+func (options PutOptions) WithDefaultTTL(ttl time.Duration, now time.Time) PutOptions {
+	if ttl <= 0 || !options.ExpiresAt.IsZero() {
+		return options
+	}
+	if options.DeliverAt.After(now) {
+		now = options.DeliverAt
+	}
+	options.ExpiresAt = now.Add(ttl)
+	return options
+}
+
+// Managed - обслуживание очереди: статистика, просмотр и очистка.
+// Peek отдает до limit первых сообщений, не забирая их.
+// Purge удаляет и готовые, и арендованные сообщения, возвращает сколько удалили.
+type Managed interface {
+	Stats() QueueStats
+	Peek(limit int) []Message
+	Purge() int
+}
+
+type QueueStats struct {
+	// Depth Сообщения, готовые к выдаче.
+	Depth int
+	// Waiting Получатели, ждущие сообщения.
+	Waiting int
+	// InFlight Сообщения в аренде.
+	InFlight int
+	// Delayed Отложенные сообщения, время доставки которых еще не пришло.
+	Delayed int
+	// Expired Сколько сообщений выкинули по истечении срока жизни за все время.
+	Expired int
+	// Enqueued Сколько сообщений приняли за все время, без возвращенных из аренды.
+	Enqueued int
+	// Dequeued Сколько раз отдали сообщения получателям за все время, повторная выдача считается заново.
+	Dequeued int
+}
+
+// GroupReader - чтение очереди QueueTypeLog группами получателей, сообщения при чтении не удаляются.
+// ReadGroup отдает до limit сообщений со смещения группы, если новых нет - ждет.
+// Смещение двигает только CommitOffset: offset - с какого сообщения группа читает дальше.
+// ResetOffset ставит смещение на начало или конец хранимого.
+// Обычные очереди его не реализуют, Consumer и Leaser лога отвечают ErrGroupRequired.
+type GroupReader interface {
+	ReadGroup(ctx context.Context, group string, limit int) ([]LogRecord[Message], error)
+	CommitOffset(group string, offset int64) error
+	ResetOffset(group string, position OffsetPosition) error
+}
+
+// LogRecord - сообщение лога вместе с его смещением.
+type LogRecord[T any] struct {
+	Offset  int64
+	Message T
+}
+
+// OffsetPosition Куда ставить смещение группы при сбросе.
+type OffsetPosition string
+
+const (
+	// OffsetEarliest Самое старое хранимое сообщение, группа перечитает все.
+	OffsetEarliest OffsetPosition = "earliest"
+	// OffsetLatest Конец лога, группа получит только новые сообщения.
+	OffsetLatest OffsetPosition = "latest"
+)
+
+func ParseOffsetPosition(value string) (OffsetPosition, error) {
+	position := OffsetPosition(value)
+	switch position {
+	case OffsetEarliest, OffsetLatest:
+		return position, nil
+	default:
+		return "", fmt.Errorf("%w: unknown position %s", ErrInvalidOffset, value)
+	}
+}
+
+// Leaser - выдача сообщений в аренду (at-least-once).
+// Сообщение скрыто от остальных, пока его не подтвердят (Ack), не вернут (Nack) или не истечет таймаут видимости.
+// Каждая выдача в аренду считается попыткой доставки, после MaxReceives неудачных сообщение считается недоставленным.
+type Leaser interface {
+	LeaseMessage(ctx context.Context, visibilityTimeout time.Duration) (Lease[Message], error)
+	Ack(receipt string) error
+	Nack(receipt string) error
+}
+
 // Queues -абстракция отвечающая оркестрацию всех очередей.
 type Queues interface {
-	GetMessageFromQueue(ctx context.Context, queueName string) (string, error)
-	PutMessageToQueue(ctx context.Context, queueName string, message string) error
-	Close()
+	QueuesConsumer
+	QueuesProducer
+	QueuesLeaser
+	QueuesManager
+	Lifecycle
+}
+
+// QueuesConsumer - то же что и Consumer, только с указанием очереди.
+type QueuesConsumer interface {
+	QueuesGroupReader
+	GetMessageFromQueue(ctx context.Context, queueName string) (Message, error)
+	GetMessagesFromQueue(ctx context.Context, queueName string, limit int) ([]Message, error)
+}
+
+// QueuesGroupReader - то же что и GroupReader, только с указанием очереди.
+// Для очереди другого типа ErrNotALog.
+type QueuesGroupReader interface {
+	ReadGroupFromQueue(ctx context.Context, queueName string, group string, limit int) ([]LogRecord[Message], error)
+	CommitGroupOffset(queueName string, group string, offset int64) error
+	ResetGroupOffset(queueName string, group string, position OffsetPosition) error
 }
 
-// QueueFactory Фабрика для очередей нужной для оркестрации, длину как параметр вынес в домен.
-type QueueFactory func(maxLen int) Queue
+// QueuesProducer - то же что и Producer, только с указанием очереди. Возвращают id положенных сообщений,
+// для повтора по PutOptions.IdempotencyKey - id первой отправки.
+type QueuesProducer interface {
+	PutMessageToQueue(ctx context.Context, queueName string, message Message, options PutOptions) (string, error)
+	PutMessagesToQueue(ctx context.Context, queueName string, messages []Message, options PutOptions) ([]string, error)
+}
+
+// QueuesManager - управление очередями, к несуществующей очереди ErrQueueNotFound, новую не создаем.
+// CreateQueue создает очередь со своими настройками, незаданные берутся по умолчанию, существующую не трогает.
+// RedriveQueue переносит сообщения из очереди недоставленных обратно в очередь queueName,
+// возвращает сколько перенесли.
+type QueuesManager interface {
+	QueuesInspector
+	CreateQueue(queueName string, config QueueConfig) error
+	DeleteQueue(queueName string) error
+	PurgeQueue(queueName string) (int, error)
+	RedriveQueue(ctx context.Context, queueName string) (int, error)
+}
+
+// QueuesInspector - просмотр очередей без изменений.
+type QueuesInspector interface {
+	ListQueues() []QueueInfo
+	QueueInfo(queueName string) (QueueInfo, error)
+	PeekQueue(queueName string, limit int) ([]Message, error)
+}
+
+type QueueInfo struct {
+	Name      string
+	CreatedAt time.Time
+	Config    QueueConfig
+	QueueStats
+}
+
+// QueuesLeaser - то же что и Leaser, только с указанием очереди.
+type QueuesLeaser interface {
+	LeaseMessageFromQueue(ctx context.Context, queueName string, visibilityTimeout time.Duration) (Lease[Message], error)
+	AckMessage(queueName string, receipt string) error
+	NackMessage(queueName string, receipt string) error
+}
+
+// Lease - арендованное сообщение, Receipt нужен для подтверждения или возврата.
+type Lease[T any] struct {
+	Message  T
+	Receipt  string
+	Deadline time.Time
+}
+
+// Topics - рассылка: сообщение топика копируется в каждую подписанную на него очередь.
+// Subscribe повторно ничего не меняет, Unsubscribe без подписки - ErrSubscriptionNotFound.
+// Subscriptions отдает подписанные очереди по имени.
+type Topics interface {
+	TopicsPublisher
+	Subscribe(topic string, queueName string) error
+	Unsubscribe(topic string, queueName string) error
+	Subscriptions(topic string) []string
+}
+
+// TopicsPublisher - публикация в топик, в каждую очередь кладем как обычный PUT, с ее политикой переполнения.
+// Возвращает, что и куда положили, не положенное в остальные - в ошибке.
+type TopicsPublisher interface {
+	Publish(ctx context.Context, topic string, message Message, options PutOptions) (Published, error)
+}
+
+// Exchanges - рассылка по ключу: очередь привязывается к обменнику шаблоном, сообщение получают очереди,
+// шаблон которых подходит под ключ. Ключ и шаблон - слова через точку, в шаблоне * - ровно одно слово,
+// # - любое число слов, в том числе ни одного. Очередь, подходящая по нескольким шаблонам, получает одну копию.
+type Exchanges interface {
+	ExchangesPublisher
+	Bind(exchange string, binding Binding) error
+	Unbind(exchange string, binding Binding) error
+	Bindings(exchange string) []Binding
+}
+
+// ExchangesPublisher - публикация по ключу, в очереди кладем как в Topics.
+type ExchangesPublisher interface {
+	Publish(
+		ctx context.Context, exchange string, routingKey string, message Message, options PutOptions,
+	) (Published, error)
+}
+
+// Published Итог публикации. ID общий для всех копий, при повторе по PutOptions.IdempotencyKey - id первой
+// отправки. Delivered - в сколько очередей положили.
+type Published struct {
+	ID        string
+	Delivered int
+}
+
+type Binding struct {
+	Pattern string
+	Queue   string
+}
+
+// OverflowPolicy Что делать с новым сообщением, если очередь заполнена.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock Ждем пока освободится место, по истечении контекста ErrQueueFull.
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowReject Сразу ErrQueueFull.
+	OverflowReject OverflowPolicy = "reject"
+	// OverflowDropOldest Выкидываем самое старое сообщение из очереди.
+	OverflowDropOldest OverflowPolicy = "dropOldest"
+	// OverflowDropNewest Выкидываем новое сообщение, для отправителя это выглядит как успех.
+	OverflowDropNewest OverflowPolicy = "dropNewest"
+)
+
+func ParseOverflowPolicy(value string) (OverflowPolicy, error) {
+	policy := OverflowPolicy(value)
+	switch policy {
+	case OverflowBlock, OverflowReject, OverflowDropOldest, OverflowDropNewest:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownOverflowPolicy, value)
+	}
+}
+
+// QueueType Порядок выдачи сообщений.
+type QueueType string
+
+const (
+	// QueueTypeFIFO По порядку поступления, пустой тип - тоже FIFO.
+	QueueTypeFIFO QueueType = "fifo"
+	// QueueTypePriority Сначала с большим приоритетом, при равном - по порядку поступления.
+	QueueTypePriority QueueType = "priority"
+	// QueueTypeLog Сообщения не удаляются при чтении, а хранятся, пока их не вытеснят MaxLen и MessageTTL.
+	// Читают группами, см. GroupReader, политика переполнения не действует - всегда выкидываем старые.
+	QueueTypeLog QueueType = "log"
+)
+
+func ParseQueueType(value string) (QueueType, error) {
+	queueType := QueueType(value)
+	switch queueType {
+	case QueueTypeFIFO, QueueTypePriority, QueueTypeLog:
+		return queueType, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownQueueType, value)
+	}
+}
+
+// QueueConfig Настройки отдельной очереди.
+type QueueConfig struct {
+	Type     QueueType
+	MaxLen   int
+	Overflow OverflowPolicy
+	// MessageTTL Срок жизни сообщений без своего срока, 0 - живут пока не заберут.
+	MessageTTL time.Duration
+	// MaxReceives Сколько раз можно выдать сообщение в аренду, 0 - без ограничений.
+	MaxReceives int
+	// MaxMessageBytes Максимальный размер сообщения, 0 - без ограничений.
+	MaxMessageBytes int
+	// DedupWindow Сколько помнить ключи идемпотентности, 0 - не помнить.
+	DedupWindow time.Duration
+}
+
+// WithDefaults Незаданные (нулевые) настройки берет из defaults.
+func (config QueueConfig) WithDefaults(defaults QueueConfig) QueueConfig {
+	config.Type = cmp.Or(config.Type, defaults.Type)
+	config.MaxLen = cmp.Or(config.MaxLen, defaults.MaxLen)
+	config.Overflow = cmp.Or(config.Overflow, defaults.Overflow)
+	config.MessageTTL = cmp.Or(config.MessageTTL, defaults.MessageTTL)
+	config.MaxReceives = cmp.Or(config.MaxReceives, defaults.MaxReceives)
+	config.MaxMessageBytes = cmp.Or(config.MaxMessageBytes, defaults.MaxMessageBytes)
+	config.DedupWindow = cmp.Or(config.DedupWindow, defaults.DedupWindow)
+	return config
+}
+
+func (config QueueConfig) Validate() error {
+	err := config.validateLimits()
+	if err != nil {
+		return err
+	}
+	_, err = ParseOverflowPolicy(string(config.Overflow))
+	if err != nil {
+		return err
+	}
+	_, err = ParseQueueType(string(cmp.Or(config.Type, QueueTypeFIFO)))
+	return err
+}
+
+// validateLimits Сроки наружу отдаем в секундах, поэтому дробные не принимаем, иначе 500ms показали бы как 0.
+func (config QueueConfig) validateLimits() error {
+	switch {
+	case config.MaxLen <= 0:
+		return fmt.Errorf("%w: max size must be positive", ErrInvalidQueueConfig)
+	case config.MessageTTL < 0, config.MaxReceives < 0, config.MaxMessageBytes < 0, config.DedupWindow < 0:
+		return fmt.Errorf("%w: limits must not be negative", ErrInvalidQueueConfig)
+	case config.MessageTTL%time.Second != 0, config.DedupWindow%time.Second != 0:
+		return fmt.Errorf("%w: message TTL and dedup window must be whole seconds", ErrInvalidQueueConfig)
+	default:
+		return nil
+	}
+}
+
+// MessageFits Проверка размера сообщения по настройкам очереди, считаем только тело.
+func (config QueueConfig) MessageFits(message Message) bool {
+	return config.MaxMessageBytes == 0 || len(message.Body) <= config.MaxMessageBytes
+}
+
+// QueueConfigs Настройки по умолчанию и переопределения для отдельных очередей.
+type QueueConfigs struct {
+	Default QueueConfig
+	ByQueue map[string]QueueConfig
+}
+
+func (configs QueueConfigs) For(queueName string) QueueConfig {
+	config, exist := configs.ByQueue[queueName]
+	if !exist {
+		return configs.Default
+	}
+	return config
+}
+
+// DeadLetter Куда отдавать сообщения, которые очередь не смогла доставить: протухшие и исчерпавшие попытки.
+type DeadLetter func(message Message)
+
+// QueueFactory Фабрика для очередей нужной для оркестрации, настройки как параметр вынес в домен.
+// Имя нужно реализациям, которые хранят сообщения вне памяти и восстанавливают их по имени очереди.
+// DeadLetter может быть nil, тогда недоставленные сообщения просто выкидываются.
+type QueueFactory func(name string, config QueueConfig, deadLetter DeadLetter) Queue
+
+// QueueConfigStore Где хранить настройки очередей, созданных через CreateQueue, чтобы после рестарта
+// очередь вернулась с ними, а не с настройками по умолчанию.
+type QueueConfigStore interface {
+	SaveQueueConfig(name string, config QueueConfig) error
+	DeleteQueueConfig(name string) error
+}
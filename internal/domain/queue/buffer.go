@@ -0,0 +1,42 @@
+package queue
+
+// buffer Готовые к выдаче сообщения, порядок выдачи задает реализация.
+// Как и basicFifo не потокобезопасен, все обращения идут под мьютексом Queue.
+type buffer[T any] interface {
+	bufferReader[T]
+	bufferWriter[T]
+}
+
+type bufferReader[T any] interface {
+	len() int
+	// getFirstN Забирает до n сообщений в порядке выдачи.
+	getFirstN(n int) []T
+	peek(n int) []T
+	getAll() []T
+}
+
+type bufferWriter[T any] interface {
+	add(elements ...T)
+	// addFirst Возвращает недоставленные сообщения так, чтобы они ушли раньше остальных.
+	addFirst(elements ...T)
+	// evictOldest и evictNewest Выкидывают сообщение при переполнении.
+	evictOldest() (T, bool)
+	evictNewest() (T, bool)
+}
+
+// fifoBuffer Сообщения в порядке поступления, выкидываем с головы или с хвоста.
+type fifoBuffer[T any] struct {
+	*basicFifo[T]
+}
+
+func newFifoBuffer[T any]() fifoBuffer[T] {
+	return fifoBuffer[T]{basicFifo: newBasicFifo[T]()}
+}
+
+func (fifo fifoBuffer[T]) evictOldest() (T, bool) {
+	return fifo.getFirst()
+}
+
+func (fifo fifoBuffer[T]) evictNewest() (T, bool) {
+	return fifo.getLast()
+}
@@ -1,72 +1,89 @@
 // nolint:ireturn
 package queue
 
-import "sync"
+import "slices"
 
 // Базовая реализации FIFO очереди
-// Взял бы канал, если бы не надо было удалять отвалившиеся запросы (получилось бы проще).
-// Привык писать на дженериках, как-то так начал и так пошло
-// В данном примере используется как хранилище запросов.
+// Используется и как хранилище сообщений, и как хранилище ждущих запросов.
+// Сама по себе не потокобезопасна, все обращения идут под мьютексом Queue,
+// так выдача сообщения ждущему и его отмена не могут произойти одновременно.
+// Привык писать на дженериках, как-то так начал и так пошло.
 type basicFifo[T any] struct {
-	// Listener Нужен чтобы дождаться если в очереди нет ничего.
-	listener chan T
 	messages []T
-	mu       *sync.RWMutex
 }
 
 func newBasicFifo[T any]() *basicFifo[T] {
 	return &basicFifo[T]{
-		listener: make(chan T),
 		messages: make([]T, 0),
-		mu:       &sync.RWMutex{},
 	}
 }
 
-func (fifo *basicFifo[T]) add(element T) {
-	select {
-	case fifo.listener <- element:
-	default:
-		fifo.mu.Lock()
-		fifo.messages = append(fifo.messages, element)
-		fifo.mu.Unlock()
-	}
+func (fifo *basicFifo[T]) add(elements ...T) {
+	fifo.messages = append(fifo.messages, elements...)
 }
 
-func (fifo *basicFifo[T]) get() T {
-	length := fifo.len()
-	if length == 0 {
-		elementToReturn := <-fifo.listener
-		return elementToReturn
-	}
-	return fifo.getLast()
+// addFirst Кладет элементы в голову, нужно для возврата сообщений, которые не смогли доставить.
+func (fifo *basicFifo[T]) addFirst(elements ...T) {
+	fifo.messages = append(slices.Clone(elements), fifo.messages...)
 }
 
 func (fifo *basicFifo[T]) len() int {
-	fifo.mu.Lock()
-	length := len(fifo.messages)
-	fifo.mu.Unlock()
-	return length
+	return len(fifo.messages)
 }
 
-func (fifo *basicFifo[T]) getLast() T {
-	fifo.mu.Lock()
-	defer fifo.mu.Unlock()
-
-	elementToReturn := fifo.messages[0]
+func (fifo *basicFifo[T]) getFirst() (T, bool) {
+	var elementToReturn T
+	if len(fifo.messages) == 0 {
+		return elementToReturn, false
+	}
+	elementToReturn = fifo.messages[0]
 	fifo.messages = fifo.messages[1:]
+	return elementToReturn, true
+}
+
+// getFirstN Забирает до n первых элементов.
+func (fifo *basicFifo[T]) getFirstN(n int) []T {
+	n = min(n, len(fifo.messages))
+	elements := slices.Clone(fifo.messages[:n])
+	fifo.messages = fifo.messages[n:]
+	return elements
+}
+
+func (fifo *basicFifo[T]) getLast() (T, bool) {
+	var elementToReturn T
+	if len(fifo.messages) == 0 {
+		return elementToReturn, false
+	}
+	elementToReturn = fifo.messages[len(fifo.messages)-1]
+	fifo.messages = fifo.messages[:len(fifo.messages)-1]
+	return elementToReturn, true
+}
+
+// peek Копия до n первых элементов, сами элементы остаются на месте.
+func (fifo *basicFifo[T]) peek(n int) []T {
+	return slices.Clone(fifo.messages[:min(n, len(fifo.messages))])
+}
+
+// values Элементы без извлечения, только для чтения.
+func (fifo *basicFifo[T]) values() []T {
+	return fifo.messages
+}
 
-	return elementToReturn
+// getAll Забирает все элементы, очередь остается пустой.
+func (fifo *basicFifo[T]) getAll() []T {
+	elements := fifo.messages
+	fifo.messages = make([]T, 0)
+	return elements
 }
 
-func (fifo *basicFifo[T]) removeByFilter(input filter[T]) {
+func (fifo *basicFifo[T]) removeByFilter(input filter[T]) bool {
 	for i, messages := range fifo.messages {
 		if input(messages) {
-			fifo.mu.Lock()
 			fifo.messages = removeIndex(fifo.messages, i)
-			fifo.mu.Unlock()
-			return
+			return true
 		}
 	}
+	return false
 }
 
 type filter[T any] func(input T) bool
@@ -0,0 +1,92 @@
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Трекер арендованных сообщений, сообщение лежит тут пока его не подтвердят или не вернут.
+// Если таймаут видимости истек - сообщение отдается в expired, чтобы очередь вернула его обратно.
+type leaseTracker[T any] struct {
+	leases  map[string]*lease[T]
+	expired func(message T)
+	mu      *sync.Mutex
+}
+
+type lease[T any] struct {
+	message T
+	timer   *time.Timer
+}
+
+func newLeaseTracker[T any](expired func(message T)) *leaseTracker[T] {
+	return &leaseTracker[T]{
+		leases:  make(map[string]*lease[T]),
+		expired: expired,
+		mu:      &sync.Mutex{},
+	}
+}
+
+func (tracker *leaseTracker[T]) add(message T, timeout time.Duration) domain.Lease[T] {
+	receipt := uuid.NewString()
+	// Таймер заводим под локом, иначе при маленьком таймауте он сработает раньше чем аренда попадет в мапу.
+	tracker.mu.Lock()
+	tracker.leases[receipt] = &lease[T]{
+		message: message,
+		timer: time.AfterFunc(timeout, func() {
+			if message, ok := tracker.remove(receipt); ok {
+				tracker.expired(message)
+			}
+		}),
+	}
+	tracker.mu.Unlock()
+	return domain.Lease[T]{
+		Message:  message,
+		Receipt:  receipt,
+		Deadline: time.Now().Add(timeout),
+	}
+}
+
+func (tracker *leaseTracker[T]) remove(receipt string) (T, bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	leased, exist := tracker.leases[receipt]
+	if !exist {
+		var empty T
+		return empty, false
+	}
+	leased.timer.Stop()
+	delete(tracker.leases, receipt)
+	return leased.message, true
+}
+
+func (tracker *leaseTracker[T]) len() int {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	return len(tracker.leases)
+}
+
+// stop Останавливает таймеры, сообщения остаются в аренде.
+func (tracker *leaseTracker[T]) stop() {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	for _, leased := range tracker.leases {
+		leased.timer.Stop()
+	}
+}
+
+// removeAll Снимает все аренды и возвращает арендованные сообщения.
+func (tracker *leaseTracker[T]) removeAll() []T {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	messages := make([]T, 0, len(tracker.leases))
+	for receipt, leased := range tracker.leases {
+		leased.timer.Stop()
+		delete(tracker.leases, receipt)
+		messages = append(messages, leased.message)
+	}
+	return messages
+}
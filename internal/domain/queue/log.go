@@ -0,0 +1,289 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// NewLog Очередь-лог, config.Overflow и config.MaxReceives не действуют.
+func NewLog[T any](config domain.QueueConfig) *Log[T] {
+	return &Log[T]{
+		offsets:    make(map[string]int64),
+		maxLen:     config.MaxLen,
+		messageTTL: config.MessageTTL,
+		appended:   make(chan struct{}),
+		waiting:    &atomic.Int64{},
+		state:      domain.StateOpen,
+		draining:   make(chan struct{}),
+		closed:     make(chan struct{}),
+		mu:         &sync.Mutex{},
+	}
+}
+
+// Log Сообщения не удаляются при чтении, а хранятся, пока их не вытеснят maxLen или messageTTL.
+// Каждая группа читает со своего смещения и сама двигает его через CommitOffset,
+// поэтому группы не мешают друг другу, а получатели одной группы читают одно и то же.
+// Смещения сквозные и не переиспользуются, в том числе после Purge.
+type Log[T any] struct {
+	records []logRecord[T]
+	// First Смещение records[0], следующее сообщение получит first+len(records).
+	first int64
+	// Offsets Зафиксированные смещения групп, группа без смещения читает с начала хранимого.
+	offsets    map[string]int64
+	maxLen     int
+	messageTTL time.Duration
+	// Appended Закрывается при добавлении сообщений и заменяется новым, на нем ждут читатели всех групп.
+	appended      chan struct{}
+	waiting       *atomic.Int64
+	expiredCount  int
+	enqueuedCount int
+	dequeuedCount int
+	// State Как у Queue: каналы ниже закрываются при переходе и отпускают ждущих.
+	state    domain.LifecycleState
+	draining chan struct{}
+	closed   chan struct{}
+	mu       *sync.Mutex
+}
+
+type logRecord[T any] struct {
+	message    T
+	appendedAt time.Time
+}
+
+// ReadGroup Сообщения со смещения группы, смещение при этом не двигается.
+func (log *Log[T]) ReadGroup(ctx context.Context, group string, limit int) ([]domain.LogRecord[T], error) {
+	for {
+		records, appended, err := log.readOrWait(group, max(limit, 1))
+		if err != nil || len(records) > 0 {
+			return records, err
+		}
+		err = log.wait(ctx, appended)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readOrWait Если читать нечего, отдает канал, который закроется при добавлении сообщений.
+func (log *Log[T]) readOrWait(group string, limit int) ([]domain.LogRecord[T], <-chan struct{}, error) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if log.state == domain.StateClosed {
+		return nil, nil, domain.ErrQueueClosed
+	}
+	log.trim(time.Now())
+	offset := log.offset(group)
+	from := int(offset - log.first)
+	to := min(from+limit, len(log.records))
+	if from < to {
+		records := make([]domain.LogRecord[T], 0, to-from)
+		for i, record := range log.records[from:to] {
+			records = append(records, domain.LogRecord[T]{Offset: offset + int64(i), Message: record.message})
+		}
+		log.dequeuedCount += len(records)
+		return records, nil, nil
+	}
+	if log.state == domain.StateDraining {
+		return nil, nil, domain.ErrShuttingDown
+	}
+	return nil, log.appended, nil
+}
+
+func (log *Log[T]) wait(ctx context.Context, appended <-chan struct{}) error {
+	log.waiting.Add(1)
+	defer log.waiting.Add(-1)
+	select {
+	case <-appended:
+		return nil
+	case <-ctx.Done():
+		return domain.ErrMessageWaitTimeOut
+	case <-log.closed:
+		return domain.ErrQueueClosed
+	case <-log.draining:
+		return domain.ErrShuttingDown
+	}
+}
+
+// CommitOffset Смещение не дальше конца лога, смещение до начала хранимого означает читать с начала.
+func (log *Log[T]) CommitOffset(group string, offset int64) error {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if log.state == domain.StateClosed {
+		return domain.ErrQueueClosed
+	}
+	if offset < 0 || offset > log.next() {
+		return fmt.Errorf("%w: %d is out of log range 0-%d", domain.ErrInvalidOffset, offset, log.next())
+	}
+	log.offsets[group] = offset
+	return nil
+}
+
+func (log *Log[T]) ResetOffset(group string, position domain.OffsetPosition) error {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if log.state == domain.StateClosed {
+		return domain.ErrQueueClosed
+	}
+	log.trim(time.Now())
+	switch position {
+	case domain.OffsetEarliest:
+		log.offsets[group] = log.first
+	case domain.OffsetLatest:
+		log.offsets[group] = log.next()
+	default:
+		return fmt.Errorf("%w: unknown position %s", domain.ErrInvalidOffset, position)
+	}
+	return nil
+}
+
+func (log *Log[T]) PutMessage(ctx context.Context, message T, options domain.PutOptions) error {
+	return log.PutMessages(ctx, []T{message}, options)
+}
+
+// PutMessages Места всегда хватает: лишнее вытесняется с начала лога. Приоритет и срок жизни сообщения
+// не учитываются, хранение общее для всего лога, а отложить сообщение в логе нельзя.
+func (log *Log[T]) PutMessages(_ context.Context, messages []T, options domain.PutOptions) error {
+	now := time.Now()
+	if options.DeliverAt.After(now) {
+		return fmt.Errorf("%w: delayed messages", domain.ErrNotSupported)
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if log.state == domain.StateClosed {
+		return domain.ErrQueueClosed
+	}
+	for _, message := range messages {
+		log.records = append(log.records, logRecord[T]{message: message, appendedAt: now})
+	}
+	log.enqueuedCount += len(messages)
+	log.trim(now)
+	close(log.appended)
+	log.appended = make(chan struct{})
+	return nil
+}
+
+// GetMessage, GetMessages и LeaseMessage Без группы непонятно, с какого места читать.
+func (log *Log[T]) GetMessage(context.Context) (T, error) {
+	var empty T
+	return empty, domain.ErrGroupRequired
+}
+
+func (log *Log[T]) GetMessages(context.Context, int) ([]T, error) {
+	return nil, domain.ErrGroupRequired
+}
+
+func (log *Log[T]) LeaseMessage(context.Context, time.Duration) (domain.Lease[T], error) {
+	return domain.Lease[T]{}, domain.ErrGroupRequired
+}
+
+func (log *Log[T]) Ack(string) error {
+	return domain.ErrReceiptNotFound
+}
+
+func (log *Log[T]) Nack(string) error {
+	return domain.ErrReceiptNotFound
+}
+
+// Stats Depth - сколько хранится, Dequeued - сколько сообщений прочитали все группы вместе.
+func (log *Log[T]) Stats() domain.QueueStats {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.trim(time.Now())
+	return domain.QueueStats{
+		Depth:    len(log.records),
+		Waiting:  int(log.waiting.Load()),
+		Expired:  log.expiredCount,
+		Enqueued: log.enqueuedCount,
+		Dequeued: log.dequeuedCount,
+	}
+}
+
+func (log *Log[T]) Peek(limit int) []T {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.trim(time.Now())
+	messages := make([]T, 0, min(limit, len(log.records)))
+	for _, record := range log.records[:min(limit, len(log.records))] {
+		messages = append(messages, record.message)
+	}
+	return messages
+}
+
+// Purge Выкидывает все хранимое, смещения групп остаются, новые сообщения продолжат нумерацию.
+func (log *Log[T]) Purge() int {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	purged := len(log.records)
+	log.first = log.next()
+	log.records = nil
+	return purged
+}
+
+func (log *Log[T]) Close() {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.advance(domain.StateClosed)
+}
+
+// Drain Ждущих отпускаем, но уже записанное группы могут дочитать.
+func (log *Log[T]) Drain() {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.advance(domain.StateDraining)
+}
+
+// advance Вызывать под мьютексом.
+func (log *Log[T]) advance(to domain.LifecycleState) {
+	next, moved := log.state.Advance(to)
+	if !moved {
+		return
+	}
+	log.state = next
+	if next == domain.StateDraining {
+		close(log.draining)
+	} else {
+		close(log.closed)
+	}
+}
+
+// trim Вытесняет лишнее сверх maxLen и старше messageTTL, вызывать под мьютексом.
+// Сообщения лежат по времени добавления, поэтому протухшие всегда в начале.
+func (log *Log[T]) trim(now time.Time) {
+	drop := 0
+	if log.maxLen > 0 {
+		drop = max(len(log.records)-log.maxLen, 0)
+	}
+	for drop < len(log.records) && log.expired(log.records[drop], now) {
+		drop++
+		log.expiredCount++
+	}
+	if drop == 0 {
+		return
+	}
+	// Без копирования: вытесненное освободится, когда append переложит лог в новый массив.
+	log.records = log.records[drop:]
+	log.first += int64(drop)
+}
+
+func (log *Log[T]) expired(record logRecord[T], now time.Time) bool {
+	return log.messageTTL > 0 && now.Sub(record.appendedAt) >= log.messageTTL
+}
+
+// offset Откуда читает группа, вызывать под мьютексом.
+func (log *Log[T]) offset(group string) int64 {
+	offset, exist := log.offsets[group]
+	if !exist || offset < log.first {
+		return log.first
+	}
+	return offset
+}
+
+// next Смещение следующего сообщения, вызывать под мьютексом.
+func (log *Log[T]) next() int64 {
+	return log.first + int64(len(log.records))
+}
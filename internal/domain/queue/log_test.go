@@ -0,0 +1,150 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/domain/queue"
+)
+
+type logTestSuite struct {
+	suite.Suite
+}
+
+func (s *logTestSuite) TestReadGroup_IndependentOffsets() {
+	ctx := context.Background()
+	logInstance := queue.NewLog[string](domain.QueueConfig{MaxLen: 10, Type: domain.QueueTypeLog})
+	defer logInstance.Close()
+	s.Require().NoError(logInstance.PutMessages(ctx, []string{"a", "b", "c"}, domain.PutOptions{}))
+
+	records, err := logInstance.ReadGroup(ctx, "billing", 2)
+	s.Require().NoError(err)
+	s.Equal([]domain.LogRecord[string]{{Offset: 0, Message: "a"}, {Offset: 1, Message: "b"}}, records)
+
+	// Без commit группа читает то же самое.
+	records, err = logInstance.ReadGroup(ctx, "billing", 2)
+	s.Require().NoError(err)
+	s.Equal(int64(0), records[0].Offset)
+
+	s.Require().NoError(logInstance.CommitOffset("billing", 2))
+	records, err = logInstance.ReadGroup(ctx, "billing", 10)
+	s.Require().NoError(err)
+	s.Equal([]domain.LogRecord[string]{{Offset: 2, Message: "c"}}, records)
+
+	records, err = logInstance.ReadGroup(ctx, "mail", 10)
+	s.Require().NoError(err)
+	s.Len(records, 3)
+	s.Equal(3, logInstance.Stats().Depth)
+}
+
+func (s *logTestSuite) TestReadGroup_WaitsForAppend() {
+	ctx := context.Background()
+	logInstance := queue.NewLog[string](domain.QueueConfig{MaxLen: 10, Type: domain.QueueTypeLog})
+	defer logInstance.Close()
+
+	result := make(chan []domain.LogRecord[string])
+	go func() {
+		records, err := logInstance.ReadGroup(ctx, "billing", 10)
+		s.NoError(err)
+		result <- records
+	}()
+	time.Sleep(50 * time.Millisecond)
+	s.Equal(1, logInstance.Stats().Waiting)
+	s.Require().NoError(logInstance.PutMessage(ctx, "a", domain.PutOptions{}))
+	s.Equal([]domain.LogRecord[string]{{Offset: 0, Message: "a"}}, <-result)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	s.Require().NoError(logInstance.CommitOffset("billing", 1))
+	_, err := logInstance.ReadGroup(timeoutCtx, "billing", 10)
+	s.Require().ErrorIs(err, domain.ErrMessageWaitTimeOut)
+}
+
+func (s *logTestSuite) TestRetention_ByCountAndAge() {
+	ctx := context.Background()
+	logInstance := queue.NewLog[string](domain.QueueConfig{
+		MaxLen:     2,
+		Type:       domain.QueueTypeLog,
+		MessageTTL: 100 * time.Millisecond,
+	})
+	defer logInstance.Close()
+	s.Require().NoError(logInstance.PutMessages(ctx, []string{"a", "b", "c"}, domain.PutOptions{}))
+
+	records, err := logInstance.ReadGroup(ctx, "billing", 10)
+	s.Require().NoError(err)
+	s.Equal([]domain.LogRecord[string]{{Offset: 1, Message: "b"}, {Offset: 2, Message: "c"}}, records)
+
+	time.Sleep(150 * time.Millisecond)
+	s.Equal(domain.QueueStats{Expired: 2, Enqueued: 3, Dequeued: 2}, logInstance.Stats())
+	s.Require().NoError(logInstance.PutMessage(ctx, "d", domain.PutOptions{}))
+	records, err = logInstance.ReadGroup(ctx, "billing", 10)
+	s.Require().NoError(err)
+	s.Equal([]domain.LogRecord[string]{{Offset: 3, Message: "d"}}, records)
+}
+
+func (s *logTestSuite) TestOffsets_CommitAndReset() {
+	ctx := context.Background()
+	logInstance := queue.NewLog[string](domain.QueueConfig{MaxLen: 10, Type: domain.QueueTypeLog})
+	defer logInstance.Close()
+	s.Require().NoError(logInstance.PutMessages(ctx, []string{"a", "b"}, domain.PutOptions{}))
+
+	s.Require().ErrorIs(logInstance.CommitOffset("billing", 3), domain.ErrInvalidOffset)
+	s.Require().ErrorIs(logInstance.CommitOffset("billing", -1), domain.ErrInvalidOffset)
+
+	s.Require().NoError(logInstance.ResetOffset("billing", domain.OffsetLatest))
+	s.Require().NoError(logInstance.PutMessage(ctx, "c", domain.PutOptions{}))
+	records, err := logInstance.ReadGroup(ctx, "billing", 10)
+	s.Require().NoError(err)
+	s.Equal([]domain.LogRecord[string]{{Offset: 2, Message: "c"}}, records)
+
+	s.Require().NoError(logInstance.ResetOffset("billing", domain.OffsetEarliest))
+	records, err = logInstance.ReadGroup(ctx, "billing", 10)
+	s.Require().NoError(err)
+	s.Len(records, 3)
+}
+
+func (s *logTestSuite) TestWithoutGroup() {
+	ctx := context.Background()
+	logInstance := queue.NewLog[string](domain.QueueConfig{MaxLen: 10, Type: domain.QueueTypeLog})
+	defer logInstance.Close()
+	s.Require().NoError(logInstance.PutMessage(ctx, "a", domain.PutOptions{}))
+
+	_, err := logInstance.GetMessage(ctx)
+	s.Require().ErrorIs(err, domain.ErrGroupRequired)
+	_, err = logInstance.LeaseMessage(ctx, time.Second)
+	s.Require().ErrorIs(err, domain.ErrGroupRequired)
+	err = logInstance.PutMessage(ctx, "b", domain.PutOptions{DeliverAt: time.Now().Add(time.Minute)})
+	s.Require().ErrorIs(err, domain.ErrNotSupported)
+	s.Equal([]string{"a"}, logInstance.Peek(10))
+}
+
+func (s *logTestSuite) TestDrain_ReleasesReaders() {
+	ctx := context.Background()
+	logInstance := queue.NewLog[string](domain.QueueConfig{MaxLen: 10, Type: domain.QueueTypeLog})
+	defer logInstance.Close()
+	s.Require().NoError(logInstance.PutMessage(ctx, "a", domain.PutOptions{}))
+
+	s.Require().NoError(logInstance.CommitOffset("billing", 1))
+	result := make(chan error)
+	go func() {
+		_, err := logInstance.ReadGroup(ctx, "billing", 10)
+		result <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	logInstance.Drain()
+	s.Require().ErrorIs(<-result, domain.ErrShuttingDown)
+	// Записанное до остановки дочитать можно.
+	records, err := logInstance.ReadGroup(ctx, "audit", 10)
+	s.Require().NoError(err)
+	s.Len(records, 1)
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(logTestSuite))
+}
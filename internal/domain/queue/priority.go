@@ -0,0 +1,114 @@
+package queue
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Сообщения по приоритету: сначала самый высокий, внутри одного приоритета - по порядку поступления.
+// Каждому приоритету своя basicFifo, уровни отсортированы по убыванию, пустые сразу убираем.
+// При переполнении выкидываем из самого низкого приоритета, срочные сообщения терять хуже всего.
+type priorityBuffer[T any] struct {
+	levels []*priorityLevel[T]
+	size   int
+}
+
+type priorityLevel[T any] struct {
+	priority int
+	messages *basicFifo[item[T]]
+}
+
+func newPriorityBuffer[T any]() *priorityBuffer[T] {
+	return &priorityBuffer[T]{}
+}
+
+func (buffer *priorityBuffer[T]) add(elements ...item[T]) {
+	for _, element := range elements {
+		buffer.level(element.priority).add(element)
+	}
+	buffer.size += len(elements)
+}
+
+// addFirst Кладем с конца, чтобы внутри приоритета сообщения остались в исходном порядке.
+func (buffer *priorityBuffer[T]) addFirst(elements ...item[T]) {
+	for i := len(elements) - 1; i >= 0; i-- {
+		buffer.level(elements[i].priority).addFirst(elements[i])
+	}
+	buffer.size += len(elements)
+}
+
+func (buffer *priorityBuffer[T]) len() int {
+	return buffer.size
+}
+
+func (buffer *priorityBuffer[T]) getFirstN(n int) []item[T] {
+	var elements []item[T]
+	for len(elements) < n && len(buffer.levels) > 0 {
+		elements = append(elements, buffer.levels[0].messages.getFirstN(n-len(elements))...)
+		buffer.removeEmpty(0)
+	}
+	buffer.size -= len(elements)
+	return elements
+}
+
+func (buffer *priorityBuffer[T]) peek(n int) []item[T] {
+	elements := make([]item[T], 0, min(n, buffer.size))
+	for _, level := range buffer.levels {
+		if len(elements) == n {
+			break
+		}
+		elements = append(elements, level.messages.peek(n-len(elements))...)
+	}
+	return elements
+}
+
+func (buffer *priorityBuffer[T]) getAll() []item[T] {
+	elements := make([]item[T], 0, buffer.size)
+	for _, level := range buffer.levels {
+		elements = append(elements, level.messages.getAll()...)
+	}
+	buffer.levels = nil
+	buffer.size = 0
+	return elements
+}
+
+func (buffer *priorityBuffer[T]) evictOldest() (item[T], bool) {
+	return buffer.evict((*basicFifo[item[T]]).getFirst)
+}
+
+func (buffer *priorityBuffer[T]) evictNewest() (item[T], bool) {
+	return buffer.evict((*basicFifo[item[T]]).getLast)
+}
+
+// evict Выкидывает сообщение из самого низкого приоритета.
+func (buffer *priorityBuffer[T]) evict(take func(*basicFifo[item[T]]) (item[T], bool)) (item[T], bool) {
+	if len(buffer.levels) == 0 {
+		var empty item[T]
+		return empty, false
+	}
+	lowest := len(buffer.levels) - 1
+	element, exist := take(buffer.levels[lowest].messages)
+	buffer.removeEmpty(lowest)
+	buffer.size--
+	return element, exist
+}
+
+// level Очередь нужного приоритета, если ее нет - создает.
+func (buffer *priorityBuffer[T]) level(priority int) *basicFifo[item[T]] {
+	i, exist := slices.BinarySearchFunc(buffer.levels, priority, func(level *priorityLevel[T], priority int) int {
+		return cmp.Compare(priority, level.priority)
+	})
+	if !exist {
+		buffer.levels = slices.Insert(buffer.levels, i, &priorityLevel[T]{
+			priority: priority,
+			messages: newBasicFifo[item[T]](),
+		})
+	}
+	return buffer.levels[i].messages
+}
+
+func (buffer *priorityBuffer[T]) removeEmpty(i int) {
+	if buffer.levels[i].messages.len() == 0 {
+		buffer.levels = slices.Delete(buffer.levels, i, i+1)
+	}
+}
@@ -3,70 +3,540 @@ package queue
 
 import (
 	"context"
-
-	"github.com/google/uuid"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/kukwuka/queue/internal/domain"
 )
 
-func NewQueue[T any](maxLen int) *Queue[T] {
+// NewQueue Порядок выдачи задает config.Type: по порядку поступления или по приоритету.
+func NewQueue[T any](config domain.QueueConfig) *Queue[T] {
+	var messages buffer[item[T]] = newFifoBuffer[item[T]]()
+	if config.Type == domain.QueueTypePriority {
+		messages = newPriorityBuffer[T]()
+	}
 	q := &Queue[T]{
-		messages: make(chan T, maxLen),
-		requests: newBasicFifo[*request[T]](),
+		messages:      messages,
+		requests:      newBasicFifo[*request[T]](),
+		maxLen:        config.MaxLen,
+		overflow:      config.Overflow,
+		messageTTL:    config.MessageTTL,
+		maxReceives:   config.MaxReceives,
+		dropped:       func(T) {},
+		undelivered:   func(T) {},
+		delivered:     func(message T, _ int) T { return message },
+		dequeuedCount: &atomic.Int64{},
+		notFull:       make(chan struct{}),
+		state:         domain.StateOpen,
+		draining:      make(chan struct{}),
+		closed:        make(chan struct{}),
+		mu:            &sync.Mutex{},
 	}
-	go q.handle()
+	q.leases = newLeaseTracker[item[T]](q.returnLeased)
+	q.scheduled = newScheduler[item[T]](q.deliverDue)
 	return q
 }
 
 // Queue Реализация Самой очереди сообщений.
+// Сообщения и ждущие запросы лежат под одним мьютексом,
+// поэтому в любой момент времени хотя бы одна из двух очередей пустая.
+// Отложенные сообщения ждут своего времени в scheduled и занимают место в очереди наравне с готовыми.
+// Протухшие сообщения выкидываются перед выдачей, сами по себе место не освобождают.
+// Сообщения, которые maxReceives раз выдали в аренду и так и не подтвердили, тоже не возвращаются.
 type Queue[T any] struct {
-	messages chan T
-	requests *basicFifo[*request[T]]
+	messages    buffer[item[T]]
+	requests    *basicFifo[*request[T]]
+	leases      *leaseTracker[item[T]]
+	scheduled   *scheduler[item[T]]
+	maxLen      int
+	overflow    domain.OverflowPolicy
+	messageTTL  time.Duration
+	maxReceives int
+	// Dropped Вызывается для сообщений, выкинутых по политике переполнения.
+	dropped func(message T)
+	// Undelivered Вызывается для протухших и исчерпавших попытки сообщений уже после мьютекса,
+	// пока не вызвали - протухшие копятся в expiredBuf.
+	undelivered func(message T)
+	// Delivered Проставляет сообщению номер выдачи перед тем, как отдать его получателю.
+	delivered    func(message T, count int) T
+	expiredBuf   []T
+	expiredCount int
+	// EnqueuedCount и dequeuedCount Накопительные счетчики для статистики,
+	// dequeued меняется без мьютекса, поэтому атомарный.
+	enqueuedCount int
+	dequeuedCount *atomic.Int64
+	// NotFull Закрывается когда в очереди освобождается место, на нем ждут писатели.
+	notFull chan struct{}
+	// State Меняется только через advance, каналы ниже закрываются при переходе и будят ждущих.
+	state domain.LifecycleState
+	// Draining Закрывается при переходе в draining, на нем отпускаем всех ждущих, сообщения при этом остаются.
+	draining chan struct{}
+	// Closed Закрывается при переходе в closed, на нем отпускаем всех ждущих.
+	closed chan struct{}
+	mu     *sync.Mutex
 }
 
 func (queue *Queue[T]) GetMessage(ctx context.Context) (T, error) {
-	r := &request[T]{
-		id:     uuid.NewString(),
-		result: make(chan T, 1),
+	messages, err := queue.GetMessages(ctx, 1)
+	if err != nil {
+		var empty T
+		return empty, err
 	}
-	defer close(r.result)
-	queue.requests.add(r)
-	var result T
+	return messages[0], nil
+}
+
+// GetMessages Отдает до limit сообщений, если их нет - ждет первую порцию.
+// Ждущие обслуживаются по очереди, каждый получает сообщения один раз.
+func (queue *Queue[T]) GetMessages(ctx context.Context, limit int) ([]T, error) {
+	items, err := queue.getItems(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return queue.deliveries(items, 1), nil
+}
+
+func (queue *Queue[T]) getItems(ctx context.Context, limit int) ([]item[T], error) {
+	items, r, err := queue.takeOrWait(max(limit, 1))
+	if err == nil && r != nil {
+		items, err = queue.wait(ctx, r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	queue.dequeuedCount.Add(int64(len(items)))
+	return items, nil
+}
+
+// wait Ждет сообщения по запросу, пока его не отменят или очередь не начнут останавливать.
+func (queue *Queue[T]) wait(ctx context.Context, r *request[T]) ([]item[T], error) {
 	select {
+	case items := <-r.result:
+		if ctx.Err() == nil {
+			return items, nil
+		}
+		// Сообщения пришли одновременно с отменой, клиент их уже не получит.
+		queue.giveBack(items...)
 	case <-ctx.Done():
-		queue.requests.removeByFilter(
-			func(input *request[T]) bool {
-				return input.id == r.id
-			},
+		queue.cancel(r)
+	case <-queue.closed:
+		queue.cancel(r)
+		return nil, domain.ErrQueueClosed
+	case <-queue.draining:
+		queue.cancel(r)
+		return nil, domain.ErrShuttingDown
+	}
+	return nil, domain.ErrMessageWaitTimeOut
+}
+
+// takeOrWait Забирает первые живые сообщения, а если их нет - ставит запрос в очередь ожидания.
+func (queue *Queue[T]) takeOrWait(limit int) ([]item[T], *request[T], error) {
+	queue.mu.Lock()
+	defer queue.unlock()
+	if queue.isClosed() {
+		return nil, nil, domain.ErrQueueClosed
+	}
+	var items []item[T]
+	for len(items) < limit && queue.messages.len() > 0 {
+		items = append(items, queue.alive(queue.messages.getFirstN(limit-len(items)))...)
+		queue.signalNotFull()
+	}
+	if len(items) > 0 {
+		return items, nil, nil
+	}
+	if queue.state == domain.StateDraining {
+		return nil, nil, domain.ErrShuttingDown
+	}
+	r := &request[T]{limit: limit, result: make(chan []item[T], 1)}
+	queue.requests.add(r)
+	return nil, r, nil
+}
+
+func (queue *Queue[T]) PutMessage(ctx context.Context, message T, options domain.PutOptions) error {
+	return queue.PutMessages(ctx, []T{message}, options)
+}
+
+// PutMessages Кладет сообщения по порядку, либо все сразу, либо ни одного.
+// Выкидывание по политике переполнения считается успехом, как и для одного сообщения.
+func (queue *Queue[T]) PutMessages(ctx context.Context, messages []T, options domain.PutOptions) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	for {
+		notFull, err := queue.tryPut(messages, options)
+		if err != nil || notFull == nil {
+			return err
+		}
+		select {
+		case <-notFull:
+		case <-queue.closed:
+			return domain.ErrQueueClosed
+		case <-queue.draining:
+			return domain.ErrShuttingDown
+		case <-ctx.Done():
+			return domain.ErrQueueFull
+		}
+	}
+}
+
+// tryPut Отдает сообщения ждущим, остальное кладет в очередь,
+// если места нет и политика велит ждать - возвращает канал, по которому можно дождаться освобождения.
+func (queue *Queue[T]) tryPut(messages []T, options domain.PutOptions) (<-chan struct{}, error) {
+	queue.mu.Lock()
+	defer queue.unlock()
+	if queue.isClosed() {
+		return nil, domain.ErrQueueClosed
+	}
+	items := queue.wrap(messages, options)
+	place, toStore := queue.placement(items, options)
+	if queue.stored()+toStore <= queue.maxLen {
+		place(items)
+		queue.enqueuedCount += len(items)
+		return nil, nil
+	}
+	return queue.overflowed(items, toStore, place)
+}
+
+// wrap Проставляет сообщениям срок жизни и приоритет.
+func (queue *Queue[T]) wrap(messages []T, options domain.PutOptions) []item[T] {
+	expiresAt := options.WithDefaultTTL(queue.messageTTL, time.Now()).ExpiresAt
+	items := make([]item[T], 0, len(messages))
+	for _, message := range messages {
+		items = append(items, item[T]{message: message, expiresAt: expiresAt, priority: options.Priority})
+	}
+	return items
+}
+
+// placement Куда класть сообщения и сколько места они займут, вызывать под мьютексом.
+// Отложенные ждущим не достаются, поэтому занимают место целиком.
+func (queue *Queue[T]) placement(items []item[T], options domain.PutOptions) (func([]item[T]), int) {
+	if options.DeliverAt.After(time.Now()) {
+		return func(items []item[T]) { queue.scheduled.add(options.DeliverAt, items...) }, len(items)
+	}
+	return queue.enqueue, max(len(items)-queue.waitingLimit(), 0)
+}
+
+// overflowed Места нет, поступаем по политике очереди, вызывать под мьютексом.
+func (queue *Queue[T]) overflowed(items []item[T], toStore int, place func([]item[T])) (<-chan struct{}, error) {
+	switch queue.overflow {
+	case domain.OverflowReject:
+		return nil, domain.ErrQueueFull
+	case domain.OverflowDropOldest, domain.OverflowDropNewest:
+		place(items)
+		queue.enqueuedCount += len(items)
+		queue.trim()
+		return nil, nil
+	default:
+		// Такая пачка не влезет даже в пустую очередь, ждать бесполезно.
+		if toStore > queue.maxLen {
+			return nil, domain.ErrQueueFull
+		}
+		return queue.notFull, nil
+	}
+}
+
+// trim Выкидывает лишнее с нужного конца очереди, вызывать под мьютексом.
+// Когда готовых не осталось, выкидываем отложенное, которое пришло бы последним.
+func (queue *Queue[T]) trim() {
+	for queue.stored() > queue.maxLen {
+		var (
+			trimmed item[T]
+			exist   bool
 		)
-		return result, domain.ErrMessageWaitTimeOut
-	case result = <-r.result:
-		return result, nil
+		if queue.overflow == domain.OverflowDropOldest {
+			trimmed, exist = queue.messages.evictOldest()
+		} else {
+			trimmed, exist = queue.messages.evictNewest()
+		}
+		if !exist {
+			trimmed, _ = queue.scheduled.removeLatest()
+		}
+		queue.dropped(trimmed.message)
 	}
 }
 
-func (queue *Queue[T]) PutMessage(ctx context.Context, message T) error {
-	select {
-	case queue.messages <- message:
-	case <-ctx.Done():
-		// В условиях задачи не сказано что делаем если очередь переполнилась.
-		// Сейчас реализовано так, что клиент ждет пока не запишет.
+// stored Сколько места занято, вызывать под мьютексом.
+func (queue *Queue[T]) stored() int {
+	return queue.messages.len() + queue.scheduled.len()
+}
+
+// deliverDue Переносит созревшие отложенные сообщения в очередь, место под них уже занято.
+// После закрытия ничего не переносим, отложенные остаются на месте.
+func (queue *Queue[T]) deliverDue() {
+	queue.mu.Lock()
+	defer queue.unlock()
+	if queue.isClosed() {
+		return
+	}
+	queue.enqueue(queue.scheduled.popDue(time.Now()))
+}
+
+// LeaseMessage Получение сообщения как в GetMessage, только сообщение остается в очереди до Ack.
+// Срок жизни сообщения в аренде не проверяем, протухнуть оно может только после возврата.
+func (queue *Queue[T]) LeaseMessage(ctx context.Context, visibilityTimeout time.Duration) (domain.Lease[T], error) {
+	items, err := queue.getItems(ctx, 1)
+	if err != nil {
+		return domain.Lease[T]{}, err
+	}
+	items[0].receives++
+	lease := queue.leases.add(items[0], visibilityTimeout)
+	return domain.Lease[T]{
+		Message:  queue.delivered(lease.Message.message, lease.Message.receives),
+		Receipt:  lease.Receipt,
+		Deadline: lease.Deadline,
+	}, nil
+}
+
+func (queue *Queue[T]) Ack(receipt string) error {
+	_, err := queue.AckMessage(receipt)
+	return err
+}
+
+// AckMessage То же что Ack, только возвращает подтвержденное сообщение, нужно обверткам над очередью.
+func (queue *Queue[T]) AckMessage(receipt string) (T, error) {
+	leased, exist := queue.leases.remove(receipt)
+	if !exist {
+		return leased.message, domain.ErrReceiptNotFound
+	}
+	return leased.message, nil
+}
+
+func (queue *Queue[T]) Nack(receipt string) error {
+	leased, exist := queue.leases.remove(receipt)
+	if !exist {
+		return domain.ErrReceiptNotFound
 	}
+	queue.returnLeased(leased)
 	return nil
 }
 
+// returnLeased Возвращает неподтвержденное сообщение в очередь, исчерпавшее попытки отдает в undelivered.
+func (queue *Queue[T]) returnLeased(leased item[T]) {
+	if queue.maxReceives > 0 && leased.receives >= queue.maxReceives {
+		queue.undelivered(leased.message)
+		return
+	}
+	queue.giveBack(leased)
+}
+
+// OnDrop Подписка на выкинутые по политике переполнения сообщения, задавать до начала работы с очередью.
+func (queue *Queue[T]) OnDrop(handler func(message T)) {
+	queue.dropped = handler
+}
+
+// OnDeliver Задает, как записать в сообщение номер выдачи, задавать до начала работы с очередью.
+func (queue *Queue[T]) OnDeliver(handler func(message T, count int) T) {
+	queue.delivered = handler
+}
+
+// deliveries Сообщения с номером выдачи: для выдаваемых сейчас next = 1, для Peek - 0.
+// Аренды уже посчитаны в receives.
+func (queue *Queue[T]) deliveries(items []item[T], next int) []T {
+	messages := make([]T, 0, len(items))
+	for _, i := range items {
+		messages = append(messages, queue.delivered(i.message, i.receives+next))
+	}
+	return messages
+}
+
+// OnUndelivered Подписка на недоставленные сообщения: протухшие и исчерпавшие попытки.
+// Задавать до начала работы с очередью.
+// Вызывается не под мьютексом очереди, поэтому из обработчика можно писать в другие очереди.
+func (queue *Queue[T]) OnUndelivered(handler func(message T)) {
+	queue.undelivered = handler
+}
+
+// Restore Кладет в конец очереди уже принятые ранее сообщения (например после рестарта),
+// ограничение по длине не проверяем, терять их нельзя.
+func (queue *Queue[T]) Restore(options domain.PutOptions, messages ...T) {
+	queue.mu.Lock()
+	defer queue.unlock()
+	items := queue.wrap(messages, options)
+	place, _ := queue.placement(items, options)
+	place(items)
+}
+
+func (queue *Queue[T]) Stats() domain.QueueStats {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return domain.QueueStats{
+		Depth:    queue.messages.len(),
+		Waiting:  queue.requests.len(),
+		InFlight: queue.leases.len(),
+		Delayed:  queue.scheduled.len(),
+		Expired:  queue.expiredCount,
+		Enqueued: queue.enqueuedCount,
+		Dequeued: int(queue.dequeuedCount.Load()),
+	}
+}
+
+// Peek Первые сообщения без извлечения, арендованные не показываем - их сейчас нет в очереди.
+func (queue *Queue[T]) Peek(limit int) []T {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.deliveries(queue.messages.peek(limit), 0)
+}
+
+// Purge Выкидывает все сообщения, в том числе арендованные и отложенные, для них вызывается OnDrop.
+func (queue *Queue[T]) Purge() int {
+	queue.mu.Lock()
+	purged := append(queue.messages.getAll(), queue.scheduled.getAll()...)
+	queue.signalNotFull()
+	queue.mu.Unlock()
+	purged = append(purged, queue.leases.removeAll()...)
+	for _, message := range messagesOf(purged) {
+		queue.dropped(message)
+	}
+	return len(purged)
+}
+
+// Close Отпускает всех ждущих с ErrQueueClosed, дальше очередь ничего не принимает и не отдает.
+// Сообщения не трогаем, в том числе отложенные, хранилище (если оно есть) восстановит их при следующем запуске.
 func (queue *Queue[T]) Close() {
-	close(queue.messages)
+	queue.mu.Lock()
+	queue.advance(domain.StateClosed)
+	queue.scheduled.stop()
+	queue.mu.Unlock()
+	queue.leases.stop()
+}
+
+// Drain Отпускает ждущих получателей и писателей с ErrShuttingDown, новым ждать тоже не дает.
+// То что можно сделать сразу, по-прежнему работает: так сервер успевает закончить начатое,
+// а недоставленные сообщения других очередей все еще попадают в эту.
+func (queue *Queue[T]) Drain() {
+	queue.mu.Lock()
+	queue.advance(domain.StateDraining)
+	queue.mu.Unlock()
+}
+
+// advance Переводит очередь в следующее состояние и будит тех, кто его ждет, вызывать под мьютексом.
+// Повторный или обратный переход ничего не делает, поэтому Close и Drain можно звать сколько угодно раз.
+func (queue *Queue[T]) advance(to domain.LifecycleState) {
+	next, moved := queue.state.Advance(to)
+	if !moved {
+		return
+	}
+	queue.state = next
+	if next == domain.StateDraining {
+		close(queue.draining)
+	} else {
+		close(queue.closed)
+	}
 }
 
-func (queue *Queue[T]) handle() {
-	for message := range queue.messages {
-		r := queue.requests.get()
-		r.result <- message
+// isClosed Вызывать под мьютексом.
+func (queue *Queue[T]) isClosed() bool {
+	return queue.state == domain.StateClosed
+}
+
+// Снимаем запрос с ожидания, если не успели - значит сообщение уже отдали и его надо вернуть.
+func (queue *Queue[T]) cancel(r *request[T]) {
+	queue.mu.Lock()
+	removed := queue.requests.removeByFilter(
+		func(input *request[T]) bool {
+			return input == r
+		},
+	)
+	queue.mu.Unlock()
+	if !removed {
+		queue.giveBack(<-r.result...)
+	}
+}
+
+// giveBack Возвращает недоставленные сообщения первыми, чтобы не нарушать порядок.
+// Ограничение по длине не проверяем, эти сообщения уже были приняты в очередь.
+func (queue *Queue[T]) giveBack(items ...item[T]) {
+	queue.mu.Lock()
+	queue.messages.addFirst(queue.dispatch(items)...)
+	queue.unlock()
+}
+
+// enqueue Отдает сообщения ждущим, остальное в конец очереди, вызывать под мьютексом.
+func (queue *Queue[T]) enqueue(items []item[T]) {
+	queue.messages.add(queue.dispatch(items)...)
+}
+
+// dispatch Раздает живые сообщения ждущим по порядку, возвращает то, что никому не досталось.
+// Вызывать под мьютексом, результат буферизирован, поэтому отправка не блокирует.
+func (queue *Queue[T]) dispatch(items []item[T]) []item[T] {
+	if queue.requests.len() > 0 {
+		items = queue.alive(items)
+	}
+	for len(items) > 0 {
+		r, exist := queue.requests.getFirst()
+		if !exist {
+			break
+		}
+		n := min(r.limit, len(items))
+		r.result <- items[:n:n]
+		items = items[n:]
+	}
+	return items
+}
+
+// alive Отсеивает протухшие сообщения, вызывать под мьютексом.
+func (queue *Queue[T]) alive(items []item[T]) []item[T] {
+	now := time.Now()
+	alive := items[:0:0]
+	for _, candidate := range items {
+		if candidate.expired(now) {
+			queue.expiredBuf = append(queue.expiredBuf, candidate.message)
+			queue.expiredCount++
+			continue
+		}
+		alive = append(alive, candidate)
+	}
+	return alive
+}
+
+// unlock Отпускает мьютекс и только потом отдает протухшие сообщения в обработчик.
+func (queue *Queue[T]) unlock() {
+	expired := queue.expiredBuf
+	queue.expiredBuf = nil
+	queue.mu.Unlock()
+	for _, message := range expired {
+		queue.undelivered(message)
 	}
 }
 
+// waitingLimit Сколько сообщений заберут ждущие, вызывать под мьютексом.
+func (queue *Queue[T]) waitingLimit() int {
+	var limit int
+	for _, r := range queue.requests.values() {
+		limit += r.limit
+	}
+	return limit
+}
+
+// signalNotFull Будит всех ждущих писателей, вызывать под мьютексом.
+func (queue *Queue[T]) signalNotFull() {
+	close(queue.notFull)
+	queue.notFull = make(chan struct{})
+}
+
 type request[T any] struct {
-	id     string
-	result chan T
+	limit  int
+	result chan []item[T]
+}
+
+// item Сообщение вместе со сроком жизни, нулевой срок - живет вечно.
+// Receives Сколько раз сообщение выдавали в аренду, после рестарта считаем заново.
+// Priority Учитывается только очередью с приоритетами.
+type item[T any] struct {
+	message   T
+	expiresAt time.Time
+	receives  int
+	priority  int
+}
+
+func (i item[T]) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+func messagesOf[T any](items []item[T]) []T {
+	messages := make([]T, 0, len(items))
+	for _, i := range items {
+		messages = append(messages, i.message)
+	}
+	return messages
 }
@@ -2,6 +2,7 @@ package queue_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,7 +18,7 @@ type queueTestSuite struct {
 }
 
 func (s *queueTestSuite) TestPushGet_3Request1Cancel() {
-	queueInstance := queue.NewQueue[string](3)
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 3, Overflow: domain.OverflowBlock})
 	defer queueInstance.Close()
 
 	resultFrom1, resultFrom3 := make(chan string), make(chan string)
@@ -48,9 +49,9 @@ func (s *queueTestSuite) TestPushGet_3Request1Cancel() {
 	time.Sleep(100 * time.Millisecond)
 
 	messageToSend1, messageToSend2 := uuid.NewString(), uuid.NewString()
-	err := queueInstance.PutMessage(context.Background(), messageToSend1)
+	err := queueInstance.PutMessage(context.Background(), messageToSend1, domain.PutOptions{})
 	s.Require().NoError(err)
-	err = queueInstance.PutMessage(context.Background(), messageToSend2)
+	err = queueInstance.PutMessage(context.Background(), messageToSend2, domain.PutOptions{})
 	s.Require().NoError(err)
 
 	messageFrom1Request, messageFrom3Request := <-resultFrom1, <-resultFrom3
@@ -59,7 +60,7 @@ func (s *queueTestSuite) TestPushGet_3Request1Cancel() {
 }
 
 func (s *queueTestSuite) TestPushGet_MessageWaitSomeRequest() {
-	queueInstance := queue.NewQueue[string](2)
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock})
 	defer queueInstance.Close()
 
 	messageToSend := uuid.NewString()
@@ -70,27 +71,523 @@ func (s *queueTestSuite) TestPushGet_MessageWaitSomeRequest() {
 		s.NoError(err)
 		result <- messageFromQueue
 	}()
-	err := queueInstance.PutMessage(context.Background(), messageToSend)
+	err := queueInstance.PutMessage(context.Background(), messageToSend, domain.PutOptions{})
 	s.Require().NoError(err)
 
 	s.Equal(messageToSend, <-result)
 }
 
 func (s *queueTestSuite) TestPushGet_ErrPutMessageTimeout() {
-	queueInstance := queue.NewQueue[string](2)
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock})
 	defer queueInstance.Close()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	for range 3 {
-		err := queueInstance.PutMessage(ctx, uuid.NewString())
+	for range 2 {
+		err := queueInstance.PutMessage(ctx, uuid.NewString(), domain.PutOptions{})
 		s.Require().NoError(err)
 	}
 	go func() {
 		time.Sleep(100 * time.Millisecond)
 		cancel()
 	}()
-	err := queueInstance.PutMessage(ctx, uuid.NewString())
+	err := queueInstance.PutMessage(ctx, uuid.NewString(), domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueFull)
+}
+
+func (s *queueTestSuite) TestOverflow_Reject() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 1, Overflow: domain.OverflowReject})
+	defer queueInstance.Close()
+
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "first", domain.PutOptions{}))
+	err := queueInstance.PutMessage(context.Background(), "second", domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueFull)
+	s.getEqual(queueInstance, "first")
+}
+
+func (s *queueTestSuite) TestOverflow_DropOldest() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowDropOldest})
+	defer queueInstance.Close()
+	var dropped []string
+	queueInstance.OnDrop(func(message string) { dropped = append(dropped, message) })
+
+	for _, message := range []string{"first", "second", "third"} {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	s.Equal([]string{"first"}, dropped)
+	s.getEqual(queueInstance, "second")
+	s.getEqual(queueInstance, "third")
+}
+
+func (s *queueTestSuite) TestOverflow_DropNewest() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 1, Overflow: domain.OverflowDropNewest})
+	defer queueInstance.Close()
+	var dropped []string
+	queueInstance.OnDrop(func(message string) { dropped = append(dropped, message) })
+
+	for _, message := range []string{"first", "second"} {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	s.Equal([]string{"second"}, dropped)
+	s.getEqual(queueInstance, "first")
+}
+
+func (s *queueTestSuite) TestLease_AckRemovesMessage() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+
+	messageToSend := uuid.NewString()
+	err := queueInstance.PutMessage(context.Background(), messageToSend, domain.PutOptions{})
+	s.Require().NoError(err)
+
+	lease, err := queueInstance.LeaseMessage(context.Background(), 50*time.Millisecond)
+	s.Require().NoError(err)
+	s.Equal(messageToSend, lease.Message)
+	s.NotEmpty(lease.Receipt)
+	s.Require().NoError(queueInstance.Ack(lease.Receipt))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	_, err = queueInstance.GetMessage(ctx)
+	s.ErrorIs(err, domain.ErrMessageWaitTimeOut)
+	s.ErrorIs(queueInstance.Ack(lease.Receipt), domain.ErrReceiptNotFound)
+}
+
+func (s *queueTestSuite) TestLease_ExpiredMessageReappears() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+
+	messageToSend := uuid.NewString()
+	err := queueInstance.PutMessage(context.Background(), messageToSend, domain.PutOptions{})
+	s.Require().NoError(err)
+
+	lease, err := queueInstance.LeaseMessage(context.Background(), 50*time.Millisecond)
+	s.Require().NoError(err)
+
+	messageFromQueue, err := queueInstance.GetMessage(context.Background())
+	s.Require().NoError(err)
+	s.Equal(messageToSend, messageFromQueue)
+	s.ErrorIs(queueInstance.Ack(lease.Receipt), domain.ErrReceiptNotFound)
+}
+
+func (s *queueTestSuite) TestLease_NackReturnsMessage() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+
+	messageToSend := uuid.NewString()
+	err := queueInstance.PutMessage(context.Background(), messageToSend, domain.PutOptions{})
+	s.Require().NoError(err)
+
+	lease, err := queueInstance.LeaseMessage(context.Background(), time.Hour)
+	s.Require().NoError(err)
+	s.Require().NoError(queueInstance.Nack(lease.Receipt))
+	s.ErrorIs(queueInstance.Nack(lease.Receipt), domain.ErrReceiptNotFound)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	messageFromQueue, err := queueInstance.GetMessage(ctx)
+	s.Require().NoError(err)
+	s.Equal(messageToSend, messageFromQueue)
+}
+
+// Номер выдачи растет с каждой арендой, peek его не увеличивает.
+func (s *queueTestSuite) TestLease_DeliveryCount() {
+	queueInstance := queue.NewQueue[domain.Message](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+	queueInstance.OnDeliver(domain.Message.WithDeliveryCount)
+
+	err := queueInstance.PutMessage(context.Background(), domain.Message{Body: []byte("message")}, domain.PutOptions{})
+	s.Require().NoError(err)
+	s.Zero(queueInstance.Peek(1)[0].DeliveryCount)
+
+	lease, err := queueInstance.LeaseMessage(context.Background(), time.Hour)
+	s.Require().NoError(err)
+	s.Equal(1, lease.Message.DeliveryCount)
+	s.Require().NoError(queueInstance.Nack(lease.Receipt))
+	s.Equal(1, queueInstance.Peek(1)[0].DeliveryCount)
+
+	messageFromQueue, err := queueInstance.GetMessage(context.Background())
+	s.Require().NoError(err)
+	s.Equal(domain.Message{Body: []byte("message"), DeliveryCount: 2}, messageFromQueue)
+}
+
+// Первая попытка возвращается через nack, вторая по таймауту видимости, после нее сообщение недоставлено.
+func (s *queueTestSuite) TestLease_MaxReceives() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock, MaxReceives: 2})
+	defer queueInstance.Close()
+	undelivered := make(chan string, 1)
+	queueInstance.OnUndelivered(func(message string) { undelivered <- message })
+
+	messageToSend := uuid.NewString()
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageToSend, domain.PutOptions{}))
+	lease, err := queueInstance.LeaseMessage(context.Background(), time.Hour)
+	s.Require().NoError(err)
+	s.Require().NoError(queueInstance.Nack(lease.Receipt))
+	s.Empty(undelivered)
+
+	_, err = queueInstance.LeaseMessage(context.Background(), 50*time.Millisecond)
+	s.Require().NoError(err)
+	s.Equal(messageToSend, <-undelivered)
+	s.Equal(domain.QueueStats{Enqueued: 1, Dequeued: 2}, queueInstance.Stats())
+}
+
+// Много получателей отваливаются по таймауту прямо в момент выдачи сообщения,
+// ни одно сообщение при этом не должно потеряться или задвоиться.
+func (s *queueTestSuite) TestPushGet_CancellingConsumersLoseNothing() {
+	const (
+		messagesCount       = 2000
+		cancellingConsumers = 5000
+	)
+	queueInstance := queue.NewQueue[int](domain.QueueConfig{MaxLen: messagesCount, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+
+	received := make(chan int, messagesCount)
+	wg := &sync.WaitGroup{}
+	for i := range cancellingConsumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i%3)*time.Millisecond)
+			defer cancel()
+			message, err := queueInstance.GetMessage(ctx)
+			if err == nil {
+				received <- message
+			}
+		}()
+	}
+	for i := range messagesCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.NoError(queueInstance.PutMessage(context.Background(), i, domain.PutOptions{}))
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool, messagesCount)
+	for len(seen) < messagesCount {
+		select {
+		case message := <-received:
+			s.Require().False(seen[message], "message %d delivered twice", message)
+			seen[message] = true
+		default:
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			message, err := queueInstance.GetMessage(ctx)
+			cancel()
+			s.Require().NoError(err, "lost %d messages", messagesCount-len(seen))
+			received <- message
+		}
+	}
+}
+
+func (s *queueTestSuite) TestClose_ReleasesWaiters() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 1, Overflow: domain.OverflowBlock})
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "first", domain.PutOptions{}))
+
+	getResult, putResult := make(chan error), make(chan error)
+	go func() {
+		err := queueInstance.PutMessage(context.Background(), "second", domain.PutOptions{})
+		putResult <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	queueInstance.Close()
+	s.ErrorIs(<-putResult, domain.ErrQueueClosed)
+
+	_, err := queueInstance.GetMessage(context.Background())
+	s.ErrorIs(err, domain.ErrQueueClosed)
+
+	emptyQueue := queue.NewQueue[string](domain.QueueConfig{MaxLen: 1, Overflow: domain.OverflowBlock})
+	go func() {
+		_, err := emptyQueue.GetMessage(context.Background())
+		getResult <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	s.Equal(1, emptyQueue.Stats().Waiting)
+	emptyQueue.Close()
+	s.ErrorIs(<-getResult, domain.ErrQueueClosed)
+	s.Zero(emptyQueue.Stats().Waiting)
+}
+
+// После закрытия очередь только отказывает, повторные Close и Drain ничего не ломают.
+func (s *queueTestSuite) TestClose_AfterDrain() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 1, Overflow: domain.OverflowBlock})
+	queueInstance.Drain()
+	queueInstance.Close()
+	queueInstance.Close()
+	queueInstance.Drain()
+
+	err := queueInstance.PutMessage(context.Background(), "message", domain.PutOptions{})
+	s.ErrorIs(err, domain.ErrQueueClosed)
+	_, err = queueInstance.GetMessage(context.Background())
+	s.ErrorIs(err, domain.ErrQueueClosed)
+}
+
+func (s *queueTestSuite) TestPurge_DropsReadyAndLeased() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 3, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+	var dropped []string
+	queueInstance.OnDrop(func(message string) { dropped = append(dropped, message) })
+
+	for _, message := range []string{"leased", "ready1", "ready2"} {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	lease, err := queueInstance.LeaseMessage(context.Background(), time.Minute)
+	s.Require().NoError(err)
+	s.Equal(domain.QueueStats{Depth: 2, InFlight: 1, Enqueued: 3, Dequeued: 1}, queueInstance.Stats())
+
+	s.Equal(3, queueInstance.Purge())
+	s.Equal([]string{"ready1", "ready2", "leased"}, dropped)
+	s.Equal(domain.QueueStats{Enqueued: 3, Dequeued: 1}, queueInstance.Stats())
+	s.ErrorIs(queueInstance.Ack(lease.Receipt), domain.ErrReceiptNotFound)
+}
+
+func (s *queueTestSuite) TestBatch_FifoAcrossWaiters() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 3, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+
+	first, second := make(chan []string), make(chan []string)
+	go func() {
+		messages, err := queueInstance.GetMessages(context.Background(), 2)
+		s.NoError(err)
+		first <- messages
+	}()
+	time.Sleep(50 * time.Millisecond)
+	go func() {
+		messages, err := queueInstance.GetMessages(context.Background(), 3)
+		s.NoError(err)
+		second <- messages
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	batch := []string{"a", "b", "c", "d", "e", "f"}
+	s.Require().NoError(queueInstance.PutMessages(context.Background(), batch, domain.PutOptions{}))
+	s.Equal([]string{"a", "b"}, <-first)
+	s.Equal([]string{"c", "d", "e"}, <-second)
+
+	messages, err := queueInstance.GetMessages(context.Background(), 5)
+	s.Require().NoError(err)
+	s.Equal([]string{"f"}, messages)
+}
+
+func (s *queueTestSuite) TestBatch_AllOrNothing() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 3, Overflow: domain.OverflowReject})
+	defer queueInstance.Close()
+
+	s.Require().NoError(queueInstance.PutMessages(context.Background(), []string{"a", "b"}, domain.PutOptions{}))
+	err := queueInstance.PutMessages(context.Background(), []string{"c", "d"}, domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueFull)
+	s.Equal(2, queueInstance.Stats().Depth)
+
+	blockingQueue := queue.NewQueue[string](domain.QueueConfig{MaxLen: 3, Overflow: domain.OverflowBlock})
+	defer blockingQueue.Close()
+	// Пачка больше очереди не влезет никогда, ждать таймаута незачем.
+	err = blockingQueue.PutMessages(context.Background(), []string{"a", "b", "c", "d"}, domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueFull)
+	s.Zero(blockingQueue.Stats().Depth)
+}
+
+func (s *queueTestSuite) TestBatch_DropOldest() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 3, Overflow: domain.OverflowDropOldest})
+	defer queueInstance.Close()
+	var dropped []string
+	queueInstance.OnDrop(func(message string) { dropped = append(dropped, message) })
+
+	s.Require().NoError(queueInstance.PutMessages(context.Background(), []string{"a", "b"}, domain.PutOptions{}))
+	s.Require().NoError(queueInstance.PutMessages(context.Background(), []string{"c", "d", "e"}, domain.PutOptions{}))
+	s.Equal([]string{"a", "b"}, dropped)
+	messages, err := queueInstance.GetMessages(context.Background(), 5)
+	s.Require().NoError(err)
+	s.Equal([]string{"c", "d", "e"}, messages)
+}
+
+func (s *queueTestSuite) TestPriority_HighestFirstFifoWithin() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{
+		Type: domain.QueueTypePriority, MaxLen: 5, Overflow: domain.OverflowBlock,
+	})
+	defer queueInstance.Close()
+
+	ctx := context.Background()
+	s.Require().NoError(queueInstance.PutMessages(ctx, []string{"low1", "low2"}, domain.PutOptions{}))
+	s.Require().NoError(queueInstance.PutMessage(ctx, "high1", domain.PutOptions{Priority: 10}))
+	s.Require().NoError(queueInstance.PutMessage(ctx, "mid", domain.PutOptions{Priority: 5}))
+	s.Require().NoError(queueInstance.PutMessage(ctx, "high2", domain.PutOptions{Priority: 10}))
+	s.Equal([]string{"high1", "high2", "mid"}, queueInstance.Peek(3))
+
+	// Возвращенное сообщение встает в голову своего приоритета, а не всей очереди.
+	lease, err := queueInstance.LeaseMessage(ctx, time.Hour)
+	s.Require().NoError(err)
+	s.Equal("high1", lease.Message)
+	s.Require().NoError(queueInstance.PutMessage(ctx, "low3", domain.PutOptions{}))
+	s.Require().NoError(queueInstance.Nack(lease.Receipt))
+
+	messages, err := queueInstance.GetMessages(ctx, 5)
+	s.Require().NoError(err)
+	s.Equal([]string{"high1", "high2", "mid", "low1", "low2"}, messages)
+	s.getEqual(queueInstance, "low3")
+}
+
+func (s *queueTestSuite) TestPriority_WaitersServedInArrivalOrder() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{
+		Type: domain.QueueTypePriority, MaxLen: 2, Overflow: domain.OverflowBlock,
+	})
+	defer queueInstance.Close()
+
+	first, second := make(chan string), make(chan string)
+	go func() { first <- s.get(queueInstance) }()
+	time.Sleep(50 * time.Millisecond)
+	go func() { second <- s.get(queueInstance) }()
+	time.Sleep(50 * time.Millisecond)
+
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "low", domain.PutOptions{}))
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "high", domain.PutOptions{Priority: 1}))
+	s.Equal("low", <-first)
+	s.Equal("high", <-second)
+}
+
+// При переполнении теряем наименее срочные сообщения.
+func (s *queueTestSuite) TestPriority_DropFromLowest() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{
+		Type: domain.QueueTypePriority, MaxLen: 2, Overflow: domain.OverflowDropOldest,
+	})
+	defer queueInstance.Close()
+	var dropped []string
+	queueInstance.OnDrop(func(message string) { dropped = append(dropped, message) })
+
+	ctx := context.Background()
+	s.Require().NoError(queueInstance.PutMessage(ctx, "high", domain.PutOptions{Priority: 1}))
+	s.Require().NoError(queueInstance.PutMessages(ctx, []string{"low1", "low2"}, domain.PutOptions{}))
+	s.Equal([]string{"low1"}, dropped)
+	s.Equal([]string{"high", "low2"}, queueInstance.Peek(2))
+}
+
+func (s *queueTestSuite) TestPeek_DoesNotConsume() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 3, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+
+	s.Empty(queueInstance.Peek(2))
+	s.Require().NoError(queueInstance.PutMessages(context.Background(), []string{"a", "b", "c"}, domain.PutOptions{}))
+	s.Equal([]string{"a", "b"}, queueInstance.Peek(2))
+	s.Equal([]string{"a", "b", "c"}, queueInstance.Peek(10))
+	s.getEqual(queueInstance, "a")
+	s.Equal([]string{"b", "c"}, queueInstance.Peek(10))
+}
+
+func (s *queueTestSuite) TestDelayed_DeliveredWhenDue() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowReject})
+	defer queueInstance.Close()
+
+	delayed := domain.PutOptions{DeliverAt: time.Now().Add(100 * time.Millisecond)}
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "later", delayed))
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "now", domain.PutOptions{}))
+	s.Equal(domain.QueueStats{Depth: 1, Delayed: 1, Enqueued: 2}, queueInstance.Stats())
+	// Отложенное сообщение занимает место в очереди.
+	err := queueInstance.PutMessage(context.Background(), "rejected", domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueFull)
+
+	s.getEqual(queueInstance, "now")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = queueInstance.GetMessage(ctx)
+	s.Require().ErrorIs(err, domain.ErrMessageWaitTimeOut)
+	s.getEqual(queueInstance, "later")
+	s.Equal(domain.QueueStats{Enqueued: 2, Dequeued: 2}, queueInstance.Stats())
+}
+
+func (s *queueTestSuite) TestDelayed_KeptAfterClose() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock})
+	var dropped []string
+	queueInstance.OnDrop(func(message string) { dropped = append(dropped, message) })
+
+	delayed := domain.PutOptions{DeliverAt: time.Now().Add(50 * time.Millisecond)}
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "later", delayed))
+	queueInstance.Close()
+	time.Sleep(100 * time.Millisecond)
+	s.Equal(domain.QueueStats{Delayed: 1, Enqueued: 1}, queueInstance.Stats())
+	s.Equal(1, queueInstance.Purge())
+	s.Equal([]string{"later"}, dropped)
+}
+
+func (s *queueTestSuite) TestTTL_ExpiredDroppedBeforeDelivery() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{
+		MaxLen:     3,
+		Overflow:   domain.OverflowBlock,
+		MessageTTL: 50 * time.Millisecond,
+	})
+	defer queueInstance.Close()
+	expired := make(chan string, 2)
+	queueInstance.OnUndelivered(func(message string) { expired <- message })
+
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "default ttl", domain.PutOptions{}))
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "own ttl", domain.PutOptions{
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	time.Sleep(100 * time.Millisecond)
+
+	s.getEqual(queueInstance, "own ttl")
+	s.Equal("default ttl", <-expired)
+	s.Equal(domain.QueueStats{Expired: 1, Enqueued: 2, Dequeued: 1}, queueInstance.Stats())
+}
+
+func (s *queueTestSuite) TestTTL_ExpiredNotDispatchedToWaiter() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 3, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+	expired := make(chan string, 1)
+	queueInstance.OnUndelivered(func(message string) { expired <- message })
+
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "stale", domain.PutOptions{
+		ExpiresAt: time.Now().Add(50 * time.Millisecond),
+	}))
+	lease, err := queueInstance.LeaseMessage(context.Background(), time.Minute)
+	s.Require().NoError(err)
+	time.Sleep(100 * time.Millisecond)
+
+	result := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := queueInstance.GetMessage(ctx)
+		result <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	// Вернули протухшее сообщение, ждущий его не получит.
+	s.Require().NoError(queueInstance.Nack(lease.Receipt))
+	s.Equal("stale", <-expired)
+	s.ErrorIs(<-result, domain.ErrMessageWaitTimeOut)
+}
+
+// Ждущий отпускается сразу, а то что уже лежит в очереди, по-прежнему можно забрать.
+func (s *queueTestSuite) TestDrain_ReleasesWaiters() {
+	queueInstance := queue.NewQueue[string](domain.QueueConfig{MaxLen: 2, Overflow: domain.OverflowBlock})
+	defer queueInstance.Close()
+
+	result := make(chan error)
+	go func() {
+		_, err := queueInstance.GetMessage(context.Background())
+		result <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	queueInstance.Drain()
+	s.ErrorIs(<-result, domain.ErrShuttingDown)
+
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), "left", domain.PutOptions{}))
+	s.getEqual(queueInstance, "left")
+	_, err := queueInstance.GetMessage(context.Background())
+	s.ErrorIs(err, domain.ErrShuttingDown)
+}
+
+func (s *queueTestSuite) get(queueInstance *queue.Queue[string]) string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	message, err := queueInstance.GetMessage(ctx)
+	s.NoError(err)
+	return message
+}
+
+func (s *queueTestSuite) getEqual(queueInstance *queue.Queue[string], expected string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	message, err := queueInstance.GetMessage(ctx)
 	s.Require().NoError(err)
+	s.Equal(expected, message)
 }
 
 func TestQueue(t *testing.T) {
@@ -0,0 +1,119 @@
+package queue
+
+import (
+	"container/heap"
+	"time"
+)
+
+// Планировщик отложенных сообщений: куча по времени доставки, при равном времени - по порядку добавления.
+// Таймер один, всегда заведен на ближайшее сообщение.
+// Как и basicFifo не потокобезопасен, все обращения идут под мьютексом Queue.
+type scheduler[T any] struct {
+	items schedule[T]
+	seq   uint64
+	timer *time.Timer
+	// Due Вызывается по таймеру, сам забирает созревшие сообщения через popDue.
+	due func()
+}
+
+type scheduled[T any] struct {
+	deliverAt time.Time
+	seq       uint64
+	message   T
+}
+
+func newScheduler[T any](due func()) *scheduler[T] {
+	return &scheduler[T]{due: due}
+}
+
+func (s *scheduler[T]) add(deliverAt time.Time, messages ...T) {
+	for _, message := range messages {
+		s.seq++
+		heap.Push(&s.items, &scheduled[T]{deliverAt: deliverAt, seq: s.seq, message: message})
+	}
+	s.rearm()
+}
+
+// popDue Забирает все сообщения, время которых пришло, в порядке доставки.
+func (s *scheduler[T]) popDue(now time.Time) []T {
+	var messages []T
+	for len(s.items) > 0 && !s.items[0].deliverAt.After(now) {
+		messages = append(messages, heap.Pop(&s.items).(*scheduled[T]).message) //nolint:forcetypeassert
+	}
+	s.rearm()
+	return messages
+}
+
+func (s *scheduler[T]) len() int {
+	return len(s.items)
+}
+
+// removeLatest Выкидывает сообщение, которое должно было прийти последним, нужно при переполнении.
+func (s *scheduler[T]) removeLatest() (T, bool) {
+	var empty T
+	if len(s.items) == 0 {
+		return empty, false
+	}
+	latest := 0
+	for i := range s.items {
+		if s.items.Less(latest, i) {
+			latest = i
+		}
+	}
+	removed := heap.Remove(&s.items, latest).(*scheduled[T]) //nolint:forcetypeassert
+	s.rearm()
+	return removed.message, true
+}
+
+// getAll Забирает все отложенные сообщения в порядке доставки.
+func (s *scheduler[T]) getAll() []T {
+	messages := make([]T, 0, len(s.items))
+	for len(s.items) > 0 {
+		messages = append(messages, heap.Pop(&s.items).(*scheduled[T]).message) //nolint:forcetypeassert
+	}
+	s.rearm()
+	return messages
+}
+
+// stop Останавливает таймер, сообщения остаются в планировщике.
+func (s *scheduler[T]) stop() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+func (s *scheduler[T]) rearm() {
+	s.stop()
+	if len(s.items) == 0 {
+		return
+	}
+	s.timer = time.AfterFunc(time.Until(s.items[0].deliverAt), s.due)
+}
+
+type schedule[T any] []*scheduled[T]
+
+func (items schedule[T]) Len() int {
+	return len(items)
+}
+
+func (items schedule[T]) Less(i, j int) bool {
+	if items[i].deliverAt.Equal(items[j].deliverAt) {
+		return items[i].seq < items[j].seq
+	}
+	return items[i].deliverAt.Before(items[j].deliverAt)
+}
+
+func (items schedule[T]) Swap(i, j int) {
+	items[i], items[j] = items[j], items[i]
+}
+
+func (items *schedule[T]) Push(item any) {
+	*items = append(*items, item.(*scheduled[T])) //nolint:forcetypeassert
+}
+
+func (items *schedule[T]) Pop() any {
+	old := *items
+	item := old[len(old)-1]
+	*items = old[:len(old)-1]
+	return item
+}
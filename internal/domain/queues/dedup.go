@@ -0,0 +1,113 @@
+package queues
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// dedupMaxKeys Сколько ключей идемпотентности помнит одна очередь, при переполнении забываем самые старые.
+const dedupMaxKeys = 10000
+
+// dedup Ключи идемпотентности очереди и id сообщений, положенных с ними. Живет только в памяти:
+// после рестарта или удаления очереди повтор положит сообщения заново.
+type dedup struct {
+	window  time.Duration
+	entries map[string]*dedupEntry
+	// Order Записи в порядке добавления, окно у очереди одно, поэтому это и порядок истечения.
+	// Забытые из entries записи остаются здесь, пока до них не дойдет очередь.
+	order []*dedupEntry
+	mu    *sync.Mutex
+}
+
+type dedupEntry struct {
+	key       string
+	ids       []string
+	expiresAt time.Time
+	// Pending Первая отправка с этим ключом еще не закончилась.
+	pending bool
+}
+
+// newDedup Без окна ключи не помним, nil тоже годится для put.
+func newDedup(window time.Duration) *dedup {
+	if window <= 0 {
+		return nil
+	}
+	return &dedup{
+		window:  window,
+		entries: make(map[string]*dedupEntry),
+		mu:      &sync.Mutex{},
+	}
+}
+
+// put Кладет через put, если ключа еще не было, иначе отдает id первой отправки.
+// Пока первая отправка идет, повтор получает ErrIdempotencyKeyInUse, неудачную отправку забываем.
+func (d *dedup) put(key string, ids []string, put func() error) ([]string, error) {
+	if d == nil || key == "" {
+		return ids, put()
+	}
+	entry, claimed, err := d.claim(key, ids, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return entry.ids, nil
+	}
+	err = put()
+	d.settle(entry, err)
+	return ids, err
+}
+
+func (d *dedup) claim(key string, ids []string, now time.Time) (*dedupEntry, bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.evict(now)
+	if entry, exist := d.entries[key]; exist {
+		if entry.pending {
+			return nil, false, domain.ErrIdempotencyKeyInUse
+		}
+		return entry, false, nil
+	}
+	entry := &dedupEntry{key: key, ids: ids, expiresAt: now.Add(d.window), pending: true}
+	d.entries[key] = entry
+	d.order = append(d.order, entry)
+	return entry, true, nil
+}
+
+func (d *dedup) settle(entry *dedupEntry, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry.pending = false
+	if err != nil && d.entries[entry.key] == entry {
+		delete(d.entries, entry.key)
+	}
+}
+
+// active Есть ключи, которые еще не истекли.
+func (d *dedup) active(now time.Time) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.evict(now)
+	return len(d.entries) > 0
+}
+
+// evict Забывает истекшие и лишние сверх dedupMaxKeys, вызывать под мьютексом.
+func (d *dedup) evict(now time.Time) {
+	for len(d.order) > 0 && d.stale(d.order[0], now) {
+		oldest := d.order[0]
+		if d.entries[oldest.key] == oldest {
+			delete(d.entries, oldest.key)
+		}
+		// Без копирования, как в логе: вытесненное освободится, когда append переложит order в новый массив.
+		d.order = d.order[1:]
+	}
+}
+
+// stale Запись уже забыта, истекла или не влезает в dedupMaxKeys, вызывать под мьютексом.
+func (d *dedup) stale(entry *dedupEntry, now time.Time) bool {
+	return d.entries[entry.key] != entry || !now.Before(entry.expiresAt) || len(d.entries) >= dedupMaxKeys
+}
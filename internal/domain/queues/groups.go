@@ -0,0 +1,71 @@
+package queues
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// ReadGroupFromQueue Как и обычное чтение, создает очередь при первом обращении, но с настройками по умолчанию
+// это будет не лог, поэтому лог нужно создать заранее или задать ему тип в настройках очереди.
+func (queues *Queues) ReadGroupFromQueue(
+	ctx context.Context,
+	queueName string,
+	group string,
+	limit int,
+) ([]domain.LogRecord[domain.Message], error) {
+	return withQueue(queues, queueName, func(queue domain.Queue) ([]domain.LogRecord[domain.Message], error) {
+		reader, err := asGroupReader(queueName, queue)
+		if err != nil {
+			return nil, err
+		}
+		records, err := reader.ReadGroup(ctx, group, limit)
+		if err != nil {
+			return nil, fmt.Errorf("read group %s from queue %s: %w", group, queueName, err)
+		}
+		return records, nil
+	})
+}
+
+func (queues *Queues) CommitGroupOffset(queueName string, group string, offset int64) error {
+	reader, err := queues.groupReader(queueName)
+	if err != nil {
+		return err
+	}
+	err = reader.CommitOffset(group, offset)
+	if err != nil {
+		return fmt.Errorf("commit offset of group %s in queue %s: %w", group, queueName, err)
+	}
+	return nil
+}
+
+func (queues *Queues) ResetGroupOffset(queueName string, group string, position domain.OffsetPosition) error {
+	reader, err := queues.groupReader(queueName)
+	if err != nil {
+		return err
+	}
+	err = reader.ResetOffset(group, position)
+	if err != nil {
+		return fmt.Errorf("reset offset of group %s in queue %s: %w", group, queueName, err)
+	}
+	return nil
+}
+
+// groupReader Смещения двигаем только в существующем логе, новую очередь ради этого не создаем.
+func (queues *Queues) groupReader(queueName string) (domain.GroupReader, error) { //nolint:ireturn
+	m, err := queues.getOrMakeQueue(queueName, false)
+	if err != nil {
+		return nil, err
+	}
+	return asGroupReader(queueName, m.queue)
+}
+
+// asGroupReader Группами читается только лог, остальные очереди GroupReader не реализуют.
+func asGroupReader(queueName string, queue domain.Queue) (domain.GroupReader, error) { //nolint:ireturn
+	reader, isLog := queue.(domain.GroupReader)
+	if !isLog {
+		return nil, fmt.Errorf("queue %s: %w", queueName, domain.ErrNotALog)
+	}
+	return reader, nil
+}
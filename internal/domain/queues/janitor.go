@@ -0,0 +1,91 @@
+package queues
+
+import (
+	"context"
+	"time"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Janitor Удаляет очереди, которые пустуют дольше ttl: нет сообщений, ждущих получателей и аренд.
+// Иначе опечатки и одноразовые имена навсегда съедают лимит queuesMaxCount.
+// Созданные явно через CreateQueue, логи и очереди с живыми ключами идемпотентности не удаляет:
+// у пересозданной очереди не было бы ни настроек, ни офсетов групп, ни ключей.
+type Janitor struct {
+	queues    *Queues
+	ttl       time.Duration
+	now       func() time.Time
+	idleSince map[string]time.Time
+}
+
+// NewJanitor now передается снаружи, чтобы в тестах управлять временем.
+func NewJanitor(queues *Queues, ttl time.Duration, now func() time.Time) *Janitor {
+	return &Janitor{
+		queues:    queues,
+		ttl:       ttl,
+		now:       now,
+		idleSince: make(map[string]time.Time),
+	}
+}
+
+// Run Запускает Sweep раз в interval, пока не отменят контекст.
+func (janitor *Janitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			janitor.Sweep()
+		}
+	}
+}
+
+// Sweep Запоминает с какого момента очередь пустует и удаляет те, что пустуют дольше ttl.
+// Не потокобезопасен, вызывать из одной горутины.
+func (janitor *Janitor) Sweep() {
+	now := janitor.now()
+	idleSince := make(map[string]time.Time, len(janitor.idleSince))
+	for _, info := range janitor.queues.ListQueues() {
+		if !isIdle(info.QueueStats) {
+			continue
+		}
+		since, exist := janitor.idleSince[info.Name]
+		if !exist {
+			since = now
+		}
+		if now.Sub(since) >= janitor.ttl && janitor.queues.evictIdle(info.Name) {
+			continue
+		}
+		idleSince[info.Name] = since
+	}
+	janitor.idleSince = idleSince
+}
+
+// evictIdle Перепроверяет под локом, что очередь все еще пустая, пока кто-то не успел в нее прийти.
+// Сообщения не выкидываем: если кто-то успел дописать после проверки, в журнале они останутся.
+func (queues *Queues) evictIdle(queueName string) bool {
+	queues.rw.Lock()
+	m, exist := queues.queuesByName[queueName]
+	if !exist || !m.evictable() {
+		queues.rw.Unlock()
+		return false
+	}
+	// Запрос, который уже нашел очередь, по отметке поймет, что ее можно создать заново.
+	m.evicted = true
+	delete(queues.queuesByName, queueName)
+	queues.rw.Unlock()
+	m.queue.Close()
+	return true
+}
+
+// evictable Вызывать под rw.
+func (m *managed) evictable() bool {
+	return !m.explicit && m.config.Type != domain.QueueTypeLog && !m.dedup.active(time.Now()) && isIdle(m.queue.Stats())
+}
+
+// isIdle Накопительные счетчики на простой не влияют.
+func isIdle(stats domain.QueueStats) bool {
+	return stats.Depth == 0 && stats.Waiting == 0 && stats.InFlight == 0 && stats.Delayed == 0
+}
@@ -0,0 +1,176 @@
+package queues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// ListQueues Очереди отсортированы по имени.
+func (queues *Queues) ListQueues() []domain.QueueInfo {
+	queues.rw.RLock()
+	infos := make([]domain.QueueInfo, 0, len(queues.queuesByName))
+	for queueName, m := range queues.queuesByName {
+		infos = append(infos, info(queueName, m))
+	}
+	queues.rw.RUnlock()
+	slices.SortFunc(infos, func(a, b domain.QueueInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return infos
+}
+
+func (queues *Queues) QueueInfo(queueName string) (domain.QueueInfo, error) {
+	queues.rw.RLock()
+	defer queues.rw.RUnlock()
+	m, exist := queues.queuesByName[queueName]
+	if !exist {
+		return domain.QueueInfo{}, domain.ErrQueueNotFound
+	}
+	return info(queueName, m), nil
+}
+
+func (queues *Queues) CreateQueue(queueName string, config domain.QueueConfig) error {
+	config = config.WithDefaults(queues.configs.For(queueName))
+	err := queues.checkConfig(config)
+	if err != nil {
+		return err
+	}
+	queues.rw.RLock()
+	err = queues.accepting()
+	queues.rw.RUnlock()
+	if err != nil {
+		return err
+	}
+	_, exist, err := queues.makeQueue(queueName, config, true)
+	if exist {
+		return domain.ErrQueueExists
+	}
+	return err
+}
+
+func (queues *Queues) checkConfig(config domain.QueueConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	if slices.Contains(queues.rejectedTypes, config.Type) {
+		return fmt.Errorf("%w: %s with this storage", domain.ErrNotSupported, config.Type)
+	}
+	return nil
+}
+
+// DeleteQueue Сначала закрываем, чтобы ждущие получили ErrQueueClosed и никто не успел дописать,
+// потом выкидываем оставшиеся сообщения.
+func (queues *Queues) DeleteQueue(queueName string) error {
+	queues.rw.Lock()
+	m, exist := queues.queuesByName[queueName]
+	delete(queues.queuesByName, queueName)
+	queues.rw.Unlock()
+	if !exist {
+		return domain.ErrQueueNotFound
+	}
+	m.queue.Close()
+	m.queue.Purge()
+	if m.explicit {
+		return queues.deleteConfig(queueName)
+	}
+	return nil
+}
+
+// saveConfig Вызывать под rw, чтобы настройки попали в хранилище в том же порядке, что и создание очередей.
+func (queues *Queues) saveConfig(queueName string, config domain.QueueConfig) error {
+	if queues.configStore == nil {
+		return nil
+	}
+	err := queues.configStore.SaveQueueConfig(queueName, config)
+	if err != nil {
+		return fmt.Errorf("save config of queue %s: %w", queueName, err)
+	}
+	return nil
+}
+
+func (queues *Queues) deleteConfig(queueName string) error {
+	if queues.configStore == nil {
+		return nil
+	}
+	err := queues.configStore.DeleteQueueConfig(queueName)
+	if err != nil {
+		return fmt.Errorf("delete config of queue %s: %w", queueName, err)
+	}
+	return nil
+}
+
+func (queues *Queues) PurgeQueue(queueName string) (int, error) {
+	queue, exist := queues.get(queueName)
+	if !exist {
+		return 0, domain.ErrQueueNotFound
+	}
+	return queue.Purge(), nil
+}
+
+func (queues *Queues) PeekQueue(queueName string, limit int) ([]domain.Message, error) {
+	queue, exist := queues.get(queueName)
+	if !exist {
+		return nil, domain.ErrQueueNotFound
+	}
+	return queue.Peek(limit), nil
+}
+
+// RedriveQueue Переносит сообщения из очереди недоставленных в конец исходной, счетчик попыток начинается заново.
+// Если очереди недоставленных еще нет - переносить нечего.
+func (queues *Queues) RedriveQueue(ctx context.Context, queueName string) (int, error) {
+	deadLetterQueueName, configured := queues.deadLetterQueueName(queueName)
+	if !configured {
+		return 0, domain.ErrNoDeadLetterQueue
+	}
+	deadLetterQueue, exist := queues.get(deadLetterQueueName)
+	if !exist {
+		return 0, nil
+	}
+	m, err := queues.getOrMakeNewQueue(queueName)
+	if err != nil {
+		return 0, err
+	}
+	redriven, err := redrive(ctx, deadLetterQueue, m.queue)
+	if err != nil {
+		return redriven, fmt.Errorf("redrive queue %s: %w", queueName, err)
+	}
+	return redriven, nil
+}
+
+// redrive Переносим по одному, чтобы при переполнении исходной очереди перенести сколько влезло.
+// Переносим не больше, чем было в начале, иначе при постоянном притоке недоставленных не остановимся.
+func redrive(ctx context.Context, from domain.Queue, to domain.Queue) (int, error) {
+	var redriven int
+	for range from.Stats().Depth {
+		message, err := from.GetMessage(noWait())
+		if errors.Is(err, domain.ErrMessageWaitTimeOut) {
+			break
+		}
+		if err != nil {
+			return redriven, err //nolint:wrapcheck
+		}
+		err = to.PutMessage(ctx, message, domain.PutOptions{})
+		if err != nil {
+			// Не влезло - возвращаем в конец очереди недоставленных, место в ней только что освободилось.
+			return redriven, errors.Join(err, from.PutMessage(noWait(), message, domain.PutOptions{}))
+		}
+		redriven++
+	}
+	return redriven, nil
+}
+
+// info Вызывать под мьютексом.
+func info(queueName string, m *managed) domain.QueueInfo {
+	return domain.QueueInfo{
+		Name:       queueName,
+		CreatedAt:  m.createdAt,
+		Config:     m.config,
+		QueueStats: m.queue.Stats(),
+	}
+}
@@ -2,89 +2,441 @@ package queues
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/kukwuka/queue/internal/domain"
 )
 
 type Queues struct {
-	queuesByName   map[string]domain.Queue
+	queuesByName   map[string]*managed
 	factory        domain.QueueFactory
-	queueMaxLen    int
 	queuesMaxCount int
-	rw             *sync.RWMutex
+	configs        domain.QueueConfigs
+	// DeadLetterSuffix Недоставленные сообщения очереди queue уходят в очередь queue+suffix, пустой - выкидываются.
+	deadLetterSuffix string
+	// AutoCreate Создавать очередь при первом обращении, иначе только через CreateQueue.
+	autoCreate bool
+	// RejectedTypes Типы очередей, которые фабрика не умеет хранить, CreateQueue их не создает.
+	rejectedTypes []domain.QueueType
+	// ConfigStore Куда сохранять настройки явно созданных очередей, nil - никуда.
+	configStore domain.QueueConfigStore
+	// State После Drain новые сообщения не принимаем, созданные после этого очереди тоже сразу останавливаются,
+	// после Close новые очереди не создаем.
+	state domain.LifecycleState
+	rw    *sync.RWMutex
 }
 
-func NewQueues(factory domain.QueueFactory, queueMaxLen int, queuesMaxCount int) *Queues {
+// managed Очередь вместе с тем, что про нее знает оркестратор.
+// Explicit Очередь создали через CreateQueue, janitor такие не трогает, иначе пропадут ее настройки.
+type managed struct {
+	queue     domain.Queue
+	createdAt time.Time
+	config    domain.QueueConfig
+	explicit  bool
+	dedup     *dedup
+	// Evicted Очередь удалил janitor за простой, меняется только под rw.
+	evicted bool
+}
+
+func NewQueues(
+	factory domain.QueueFactory,
+	queuesMaxCount int,
+	configs domain.QueueConfigs,
+	deadLetterSuffix string,
+) *Queues {
 	return &Queues{
-		queuesByName:   make(map[string]domain.Queue, queuesMaxCount),
-		factory:        factory,
-		queueMaxLen:    queueMaxLen,
-		queuesMaxCount: queuesMaxCount,
-		rw:             &sync.RWMutex{},
+		queuesByName:     make(map[string]*managed, queuesMaxCount),
+		factory:          factory,
+		queuesMaxCount:   queuesMaxCount,
+		configs:          configs,
+		deadLetterSuffix: deadLetterSuffix,
+		autoCreate:       true,
+		state:            domain.StateOpen,
+		rw:               &sync.RWMutex{},
+	}
+}
+
+// RequireExplicitCreate Обращение к несозданной очереди вернет ErrQueueNotFound, а не создаст ее,
+// так опечатка в имени очереди не превращается в вечное ожидание. Задавать до начала работы.
+// Очереди из журнала и очереди недоставленных по-прежнему создаются сами.
+func (queues *Queues) RequireExplicitCreate() {
+	queues.autoCreate = false
+}
+
+// RejectQueueTypes CreateQueue с этими типами вернет ErrNotSupported, например лог, если фабрика пишет журнал,
+// а лог в нем не хранится. Задавать до начала работы.
+func (queues *Queues) RejectQueueTypes(queueTypes ...domain.QueueType) {
+	queues.rejectedTypes = queueTypes
+}
+
+// StoreConfigs CreateQueue сохраняет настройки очереди в store, DeleteQueue - удаляет.
+// Вернуть их после рестарта - RestoreConfigs. Задавать до начала работы.
+func (queues *Queues) StoreConfigs(store domain.QueueConfigStore) {
+	queues.configStore = store
+}
+
+// Drain Начало остановки: новые сообщения и очереди не принимаем, ждущих отпускаем с ErrShuttingDown.
+// Уже лежащие сообщения можно забрать, аренды - подтвердить.
+func (queues *Queues) Drain() {
+	for _, queue := range queues.advance(domain.StateDraining) {
+		queue.Drain()
 	}
 }
 
+// Close Закрывает все очереди, новые после этого не создаются, поэтому закрывать можно уже без блокировки.
 func (queues *Queues) Close() {
-	for _, queue := range queues.queuesByName {
+	for _, queue := range queues.advance(domain.StateClosed) {
 		queue.Close()
 	}
 }
 
-func (queues *Queues) GetMessageFromQueue(ctx context.Context, queueName string) (string, error) {
-	queue, err := queues.getOrMakeNewQueue(queueName)
+// advance Переводит оркестратор в следующее состояние и отдает очереди, которые надо перевести следом.
+func (queues *Queues) advance(to domain.LifecycleState) []domain.Queue {
+	queues.rw.Lock()
+	defer queues.rw.Unlock()
+	next, moved := queues.state.Advance(to)
+	if !moved {
+		return nil
+	}
+	queues.state = next
+	toAdvance := make([]domain.Queue, 0, len(queues.queuesByName))
+	for _, m := range queues.queuesByName {
+		toAdvance = append(toAdvance, m.queue)
+	}
+	return toAdvance
+}
+
+// RestoreConfigs Создает явно созданные до рестарта очереди с их настройками, как CreateQueue.
+// Вызывать до Restore, иначе сообщения из журнала восстановятся в очередь с настройками по умолчанию.
+func (queues *Queues) RestoreConfigs(configs map[string]domain.QueueConfig) error {
+	queueNames := make([]string, 0, len(configs))
+	for queueName := range configs {
+		queueNames = append(queueNames, queueName)
+	}
+	slices.Sort(queueNames)
+	for _, queueName := range queueNames {
+		_, _, err := queues.makeQueue(queueName, configs[queueName], true)
+		if err != nil {
+			return fmt.Errorf("restore queue %s: %w", queueName, err)
+		}
+	}
+	return nil
+}
+
+// Restore Заранее создает очереди, например те, что восстановились из журнала после рестарта.
+func (queues *Queues) Restore(queueNames []string) error {
+	for _, queueName := range queueNames {
+		_, err := queues.getOrMakeQueue(queueName, true)
+		if err != nil {
+			return fmt.Errorf("restore queue %s: %w", queueName, err)
+		}
+	}
+	return nil
+}
+
+func (queues *Queues) GetMessageFromQueue(ctx context.Context, queueName string) (domain.Message, error) {
+	return withQueue(queues, queueName, func(queue domain.Queue) (domain.Message, error) {
+		message, err := queue.GetMessage(ctx)
+		if err != nil {
+			return domain.Message{}, fmt.Errorf("get message from queue %s: %w", queueName, err)
+		}
+		return message, nil
+	})
+}
+
+func (queues *Queues) GetMessagesFromQueue(
+	ctx context.Context,
+	queueName string,
+	limit int,
+) ([]domain.Message, error) {
+	return withQueue(queues, queueName, func(queue domain.Queue) ([]domain.Message, error) {
+		messages, err := queue.GetMessages(ctx, limit)
+		if err != nil {
+			return nil, fmt.Errorf("get messages from queue %s: %w", queueName, err)
+		}
+		return messages, nil
+	})
+}
+
+func (queues *Queues) PutMessageToQueue(
+	ctx context.Context,
+	queueName string,
+	message domain.Message,
+	options domain.PutOptions,
+) (string, error) {
+	message = message.Stamp(time.Now())
+	return withQueue(queues, queueName, func(queue domain.Queue) (string, error) {
+		keys, err := queues.checkPut(queueName, message)
+		if err != nil {
+			return "", err
+		}
+		ids, err := keys.put(options.IdempotencyKey, []string{message.ID}, func() error {
+			return queue.PutMessage(ctx, message, options)
+		})
+		if err != nil {
+			return "", fmt.Errorf("put message to queue %s: %w", queueName, err)
+		}
+		return ids[0], nil
+	})
+}
+
+// PutMessagesToQueue Ключ идемпотентности относится ко всей пачке, повтор отдает id всей первой пачки.
+func (queues *Queues) PutMessagesToQueue(
+	ctx context.Context,
+	queueName string,
+	messages []domain.Message,
+	options domain.PutOptions,
+) ([]string, error) {
+	messages = stamp(messages, time.Now())
+	return withQueue(queues, queueName, func(queue domain.Queue) ([]string, error) {
+		keys, err := queues.checkPut(queueName, messages...)
+		if err != nil {
+			return nil, err
+		}
+		ids, err := keys.put(options.IdempotencyKey, idsOf(messages), func() error {
+			return queue.PutMessages(ctx, messages, options)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("put messages to queue %s: %w", queueName, err)
+		}
+		return ids, nil
+	})
+}
+
+func (queues *Queues) LeaseMessageFromQueue(
+	ctx context.Context,
+	queueName string,
+	visibilityTimeout time.Duration,
+) (domain.Lease[domain.Message], error) {
+	return withQueue(queues, queueName, func(queue domain.Queue) (domain.Lease[domain.Message], error) {
+		lease, err := queue.LeaseMessage(ctx, visibilityTimeout)
+		if err != nil {
+			return domain.Lease[domain.Message]{}, fmt.Errorf("lease message from queue %s: %w", queueName, err)
+		}
+		return lease, nil
+	})
+}
+
+// AckMessage Подтверждать можно только в существующей очереди, новую ради этого не создаем.
+func (queues *Queues) AckMessage(queueName string, receipt string) error {
+	queue, exist := queues.get(queueName)
+	if !exist {
+		return domain.ErrReceiptNotFound
+	}
+	err := queue.Ack(receipt)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("ack message in queue %s: %w", queueName, err)
 	}
-	message, err := queue.GetMessage(ctx)
+	return nil
+}
+
+func (queues *Queues) NackMessage(queueName string, receipt string) error {
+	queue, exist := queues.get(queueName)
+	if !exist {
+		return domain.ErrReceiptNotFound
+	}
+	err := queue.Nack(receipt)
 	if err != nil {
-		return "", fmt.Errorf("get message from queue %s: %w", queueName, err)
+		return fmt.Errorf("nack message in queue %s: %w", queueName, err)
 	}
-	return message, nil
+	return nil
 }
 
-func (queues *Queues) PutMessageToQueue(ctx context.Context, queueName string, message string) error {
-	queue, err := queues.getOrMakeNewQueue(queueName)
+// withQueue Janitor мог удалить очередь за простой между тем, как запрос ее нашел, и обращением к ней.
+// Тогда запрос получил бы ErrQueueClosed, хотя очередь никто не удалял, поэтому повторяем его один раз с новой.
+func withQueue[T any](queues *Queues, queueName string, call func(queue domain.Queue) (T, error)) (T, error) {
+	m, err := queues.getOrMakeNewQueue(queueName)
 	if err != nil {
-		return err
+		var zero T
+		return zero, err
+	}
+	result, err := call(m.queue)
+	if !errors.Is(err, domain.ErrQueueClosed) || !queues.isEvicted(m) {
+		return result, err
 	}
-	err = queue.PutMessage(ctx, message)
+	m, err = queues.getOrMakeNewQueue(queueName)
 	if err != nil {
-		return fmt.Errorf("put message to queue %s: %w", queueName, err)
+		var zero T
+		return zero, err
 	}
-	return nil
+	return call(m.queue)
 }
 
-func (queues *Queues) getOrMakeNewQueue(queueName string) (domain.Queue, error) { //nolint:ireturn
-	queue, exist := queues.get(queueName)
+func (queues *Queues) isEvicted(m *managed) bool {
+	queues.rw.RLock()
+	defer queues.rw.RUnlock()
+	return m.evicted
+}
+
+func (queues *Queues) getOrMakeNewQueue(queueName string) (*managed, error) {
+	return queues.getOrMakeQueue(queueName, queues.autoCreate)
+}
+
+func (queues *Queues) getOrMakeQueue(queueName string, create bool) (*managed, error) {
+	m, exist := queues.lookup(queueName)
 	if exist {
-		return queue, nil
+		return m, nil
 	}
-	if queues.getLen() >= queues.queuesMaxCount {
-		return nil, domain.ErrMaxCountQueuesCount
+	if !create {
+		return nil, domain.ErrQueueNotFound
 	}
-	return queues.createNewQueue(queueName), nil
+	m, _, err := queues.makeQueue(queueName, queues.configs.For(queueName), false)
+	return m, err
 }
 
 func (queues *Queues) get(queueName string) (domain.Queue, bool) { //nolint:ireturn
-	queues.rw.RLock()
-	queue, exist := queues.queuesByName[queueName]
-	queues.rw.RUnlock()
-	return queue, exist
+	m, exist := queues.lookup(queueName)
+	if !exist {
+		return nil, false
+	}
+	return m.queue, true
 }
 
-func (queues *Queues) getLen() int {
+func (queues *Queues) lookup(queueName string) (*managed, bool) {
 	queues.rw.RLock()
-	length := len(queues.queuesByName)
-	queues.rw.RUnlock()
-	return length
+	defer queues.rw.RUnlock()
+	m, exist := queues.queuesByName[queueName]
+	return m, exist
 }
 
-func (queues *Queues) createNewQueue(queueName string) domain.Queue { //nolint:ireturn
-	queue := queues.factory(queues.queueMaxLen)
+// makeQueue Проверка и создание под одной блокировкой, иначе два запроса могут создать одну очередь дважды
+// или вместе превысить queuesMaxCount. Если очередь уже есть, отдаем ее и exist = true.
+func (queues *Queues) makeQueue(
+	queueName string,
+	config domain.QueueConfig,
+	explicit bool,
+) (*managed, bool, error) {
 	queues.rw.Lock()
-	queues.queuesByName[queueName] = queue
-	queues.rw.Unlock()
+	defer queues.rw.Unlock()
+	if m, exist := queues.queuesByName[queueName]; exist {
+		return m, true, nil
+	}
+	err := queues.checkCanMake()
+	if err != nil {
+		return nil, false, err
+	}
+	// Настройки сохраняем до создания, иначе очередь может уже получить сообщения, а настройки - нет.
+	if explicit {
+		err = queues.saveConfig(queueName, config)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	m := &managed{
+		queue:     queues.newQueue(queueName, config),
+		createdAt: time.Now(),
+		config:    config,
+		explicit:  explicit,
+		dedup:     newDedup(config.DedupWindow),
+	}
+	queues.queuesByName[queueName] = m
+	return m, false, nil
+}
+
+// newQueue Созданная после начала остановки очередь тоже сразу останавливается. Вызывать под rw.
+func (queues *Queues) newQueue(queueName string, config domain.QueueConfig) domain.Queue { //nolint:ireturn
+	queue := queues.factory(queueName, config, queues.deadLetter(queueName))
+	if queues.state == domain.StateDraining {
+		queue.Drain()
+	}
 	return queue
 }
+
+// checkCanMake Вызывать под rw.
+func (queues *Queues) checkCanMake() error {
+	switch {
+	case queues.state == domain.StateClosed:
+		return domain.ErrQueueClosed
+	case len(queues.queuesByName) >= queues.queuesMaxCount:
+		return domain.ErrMaxCountQueuesCount
+	default:
+		return nil
+	}
+}
+
+// accepting Принимает ли оркестратор новые сообщения и очереди, вызывать под rw.
+func (queues *Queues) accepting() error {
+	switch queues.state {
+	case domain.StateDraining:
+		return domain.ErrShuttingDown
+	case domain.StateClosed:
+		return domain.ErrQueueClosed
+	default:
+		return nil
+	}
+}
+
+// checkPut Вся пачка должна пройти по размеру, иначе не кладем ничего. При остановке не кладем вовсе.
+// Отдает ключи идемпотентности очереди, nil - если очередь их не помнит.
+func (queues *Queues) checkPut(queueName string, messages ...domain.Message) (*dedup, error) {
+	queues.rw.RLock()
+	var (
+		config domain.QueueConfig
+		keys   *dedup
+	)
+	if m, exist := queues.queuesByName[queueName]; exist {
+		config, keys = m.config, m.dedup
+	}
+	err := queues.accepting()
+	queues.rw.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("put to queue %s: %w", queueName, err)
+	}
+	for _, message := range messages {
+		if !config.MessageFits(message) {
+			return nil, fmt.Errorf("put to queue %s: %w", queueName, domain.ErrMessageTooLarge)
+		}
+	}
+	return keys, nil
+}
+
+func (queues *Queues) deadLetter(queueName string) domain.DeadLetter {
+	deadLetterQueueName, exist := queues.deadLetterQueueName(queueName)
+	if !exist {
+		return nil
+	}
+	return func(message domain.Message) {
+		// Ждать места в очереди недоставленных некому, если не влезло - сообщение теряется.
+		deadLetterQueue, err := queues.getOrMakeQueue(deadLetterQueueName, true)
+		if err == nil {
+			_ = deadLetterQueue.queue.PutMessage(noWait(), message, domain.PutOptions{})
+		}
+	}
+}
+
+// deadLetterQueueName У самих очередей недоставленных своей такой очереди нет, иначе сообщения ходили бы по кругу.
+func (queues *Queues) deadLetterQueueName(queueName string) (string, bool) {
+	if queues.deadLetterSuffix == "" || strings.HasSuffix(queueName, queues.deadLetterSuffix) {
+		return "", false
+	}
+	return queueName + queues.deadLetterSuffix, true
+}
+
+// stamp Вся пачка принята в один момент.
+func stamp(messages []domain.Message, now time.Time) []domain.Message {
+	stamped := make([]domain.Message, 0, len(messages))
+	for _, message := range messages {
+		stamped = append(stamped, message.Stamp(now))
+	}
+	return stamped
+}
+
+func idsOf(messages []domain.Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
+
+// noWait Уже отмененный контекст: забрать или положить только если можно сразу.
+func noWait() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
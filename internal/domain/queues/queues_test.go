@@ -5,8 +5,10 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/kukwuka/queue/internal/domain"
@@ -19,18 +21,29 @@ const (
 	maxCount = 3
 )
 
+var configs = domain.QueueConfigs{Default: domain.QueueConfig{MaxLen: maxLen, Overflow: domain.OverflowBlock}}
+
+// stampedMessage Сообщение с уже проставленными id и временем, Queues его не меняет и ожидания в моках точные.
+func stampedMessage(body string) domain.Message {
+	return domain.Message{
+		ID:         uuid.NewString(),
+		Body:       []byte(body),
+		EnqueuedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
 type queuesTestSuite struct {
 	suite.Suite
 }
 
 func (s *queuesTestSuite) TestPushGet_Success() {
-	queueName, messageToPut := uuid.NewString(), uuid.NewString()
+	queueName, messageToPut := uuid.NewString(), stampedMessage(uuid.NewString())
 	ctx := context.Background()
 
 	queueInstance := mocks.NewQueue(s.T())
 	queueInstance.
 		EXPECT().
-		PutMessage(ctx, messageToPut).
+		PutMessage(ctx, messageToPut, domain.PutOptions{}).
 		Return(nil).
 		Once()
 	queueInstance.
@@ -41,16 +54,16 @@ func (s *queuesTestSuite) TestPushGet_Success() {
 	factory := mocks.NewQueueFactory(s.T())
 	factory.
 		EXPECT().
-		Execute(maxLen).
+		Execute(queueName, configs.Default, mock.Anything).
 		Return(queueInstance).
 		Once()
 
-	queuesInstance := queues.NewQueues(factory.Execute, maxLen, maxCount)
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
 	defer queuesInstance.Close()
-	err := queuesInstance.PutMessageToQueue(ctx, queueName, messageToPut)
+	_, err := queuesInstance.PutMessageToQueue(ctx, queueName, messageToPut, domain.PutOptions{})
 	s.Require().NoError(err)
 
-	messageFromQueue := uuid.NewString()
+	messageFromQueue := stampedMessage(uuid.NewString())
 	queueInstance.
 		EXPECT().
 		GetMessage(ctx).
@@ -61,55 +74,308 @@ func (s *queuesTestSuite) TestPushGet_Success() {
 	s.Equal(messageFromQueue, resultMessage)
 }
 
+// Id и время постановки проставляет Queues, заданные отправителем не меняются.
+func (s *queuesTestSuite) TestPut_StampsMessage() {
+	queueName := uuid.NewString()
+	ctx := context.Background()
+	stamped := stampedMessage("stamped")
+
+	var put []domain.Message
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		PutMessages(ctx, mock.Anything, domain.PutOptions{}).
+		RunAndReturn(func(_ context.Context, messages []domain.Message, _ domain.PutOptions) error {
+			put = messages
+			return nil
+		}).
+		Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	before := time.Now()
+	messages := []domain.Message{
+		{Body: []byte("first"), Headers: map[string]string{"trace": "1"}},
+		{Body: []byte("second")},
+		stamped,
+	}
+	ids, err := queuesInstance.PutMessagesToQueue(ctx, queueName, messages, domain.PutOptions{})
+	s.Require().NoError(err)
+
+	s.Require().Len(put, 3)
+	s.NotEmpty(put[0].ID)
+	s.NotEqual(put[0].ID, put[1].ID)
+	s.Equal(map[string]string{"trace": "1"}, put[0].Headers)
+	s.False(put[0].EnqueuedAt.Before(before))
+	s.Equal(put[0].EnqueuedAt, put[1].EnqueuedAt)
+	s.Equal(stamped, put[2])
+	s.Equal([]string{put[0].ID, put[1].ID, stamped.ID}, ids)
+}
+
+// Повтор с тем же ключом отдает id первой отправки и ничего не кладет, неудачная отправка ключ не занимает.
+func (s *queuesTestSuite) TestPut_IdempotencyKey() {
+	queueName := uuid.NewString()
+	ctx := context.Background()
+	options := domain.PutOptions{IdempotencyKey: "key"}
+	dedupConfigs := domain.QueueConfigs{Default: domain.QueueConfig{MaxLen: maxLen, DedupWindow: time.Minute}}
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		PutMessage(ctx, mock.Anything, options).
+		Return(errors.New("test error")).
+		Once()
+	queueInstance.
+		EXPECT().
+		PutMessage(ctx, mock.Anything, options).
+		Return(nil).
+		Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, dedupConfigs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, dedupConfigs, "")
+	_, err := queuesInstance.PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("first")}, options)
+	s.Require().Error(err)
+	id, err := queuesInstance.PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("second")}, options)
+	s.Require().NoError(err)
+	repeated, err := queuesInstance.PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("third")}, options)
+	s.Require().NoError(err)
+	s.Equal(id, repeated)
+}
+
+// Пока первая отправка с ключом не закончилась, повтор не ждет ее, а получает ErrIdempotencyKeyInUse.
+func (s *queuesTestSuite) TestPut_IdempotencyKeyInUse() {
+	queueName := uuid.NewString()
+	ctx := context.Background()
+	options := domain.PutOptions{IdempotencyKey: "key"}
+	dedupConfigs := domain.QueueConfigs{Default: domain.QueueConfig{MaxLen: maxLen, DedupWindow: time.Minute}}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		PutMessages(ctx, mock.Anything, options).
+		RunAndReturn(func(context.Context, []domain.Message, domain.PutOptions) error {
+			close(started)
+			<-release
+			return nil
+		}).
+		Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, dedupConfigs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, dedupConfigs, "")
+	messages := []domain.Message{{Body: []byte("first")}, {Body: []byte("second")}}
+	firstIDs := make(chan []string, 1)
+	go func() {
+		ids, _ := queuesInstance.PutMessagesToQueue(ctx, queueName, messages, options)
+		firstIDs <- ids
+	}()
+	<-started
+	_, err := queuesInstance.PutMessagesToQueue(ctx, queueName, messages, options)
+	s.Require().ErrorIs(err, domain.ErrIdempotencyKeyInUse)
+	close(release)
+
+	ids := <-firstIDs
+	s.Len(ids, 2)
+	repeated, err := queuesInstance.PutMessagesToQueue(ctx, queueName, messages, options)
+	s.Require().NoError(err)
+	s.Equal(ids, repeated)
+}
+
 func (s *queuesTestSuite) TestPush_ErrMaxQueueCrowded() {
-	messageToPut := uuid.NewString()
+	messageToPut := stampedMessage(uuid.NewString())
 	ctx := context.Background()
 
 	factory := mocks.NewQueueFactory(s.T())
 	factory.
 		EXPECT().
-		Execute(maxLen).
+		Execute(mock.Anything, configs.Default, mock.Anything).
 		RunAndReturn(
-			func(_ int) domain.Queue {
+			func(_ string, _ domain.QueueConfig, _ domain.DeadLetter) domain.Queue {
 				queueInstance := mocks.NewQueue(s.T())
 				queueInstance.
 					EXPECT().
-					PutMessage(ctx, messageToPut).
+					PutMessage(ctx, messageToPut, domain.PutOptions{}).
 					Return(nil)
 				return queueInstance
 			},
 		).Times(maxCount)
 
-	queuesInstance := queues.NewQueues(factory.Execute, maxLen, maxCount)
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
 
 	for range maxCount {
-		err := queuesInstance.PutMessageToQueue(ctx, uuid.NewString(), messageToPut)
+		_, err := queuesInstance.PutMessageToQueue(ctx, uuid.NewString(), messageToPut, domain.PutOptions{})
 		s.Require().NoError(err)
 	}
 
-	err := queuesInstance.PutMessageToQueue(ctx, uuid.NewString(), messageToPut)
+	_, err := queuesInstance.PutMessageToQueue(ctx, uuid.NewString(), messageToPut, domain.PutOptions{})
 	s.ErrorIs(err, domain.ErrMaxCountQueuesCount)
 }
 
+// Каждое имя запрашивают несколько раз одновременно: очередь создается один раз и не больше maxCount всего.
+func (s *queuesTestSuite) TestConcurrentCreate_RespectsMaxCount() {
+	const (
+		namesCount = 10
+		sameName   = 3
+	)
+	ctx := context.Background()
+	message := stampedMessage("message")
+
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(mock.Anything, configs.Default, mock.Anything).
+		RunAndReturn(
+			func(_ string, _ domain.QueueConfig, _ domain.DeadLetter) domain.Queue {
+				queueInstance := mocks.NewQueue(s.T())
+				queueInstance.
+					EXPECT().
+					PutMessage(ctx, message, domain.PutOptions{}).
+					Return(nil).
+					Times(sameName)
+				return queueInstance
+			},
+		).Times(maxCount)
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		rejected int
+	)
+	for range namesCount {
+		queueName := uuid.NewString()
+		for range sameName {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := queuesInstance.PutMessageToQueue(ctx, queueName, message, domain.PutOptions{})
+				if errors.Is(err, domain.ErrMaxCountQueuesCount) {
+					mu.Lock()
+					rejected++
+					mu.Unlock()
+					return
+				}
+				s.NoError(err)
+			}()
+		}
+	}
+	wg.Wait()
+	s.Equal((namesCount-maxCount)*sameName, rejected)
+}
+
+func (s *queuesTestSuite) TestClose_NoNewQueues() {
+	queueName := uuid.NewString()
+	ctx := context.Background()
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.EXPECT().Close().Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	s.Require().NoError(queuesInstance.Restore([]string{queueName}))
+	queuesInstance.Close()
+	// Повторные Close и Drain уже ничего не делают.
+	queuesInstance.Close()
+	queuesInstance.Drain()
+
+	_, err := queuesInstance.PutMessageToQueue(ctx, uuid.NewString(), stampedMessage("message"), domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueClosed)
+	s.Require().ErrorIs(queuesInstance.CreateQueue(uuid.NewString(), domain.QueueConfig{}), domain.ErrQueueClosed)
+}
+
+// logQueue Лог для оркестратора - обычная очередь, которая еще и GroupReader.
+type logQueue struct {
+	*mocks.Queue
+	*mocks.GroupReader
+}
+
+func (s *queuesTestSuite) TestReadGroup_Log() {
+	queueName := uuid.NewString()
+	ctx := context.Background()
+	logConfig := domain.QueueConfig{Type: domain.QueueTypeLog, MaxLen: maxLen, Overflow: domain.OverflowBlock}
+	message := stampedMessage("message")
+
+	queueInstance := logQueue{Queue: mocks.NewQueue(s.T()), GroupReader: mocks.NewGroupReader(s.T())}
+	queueInstance.GroupReader.
+		EXPECT().
+		ReadGroup(ctx, "billing", 10).
+		Return([]domain.LogRecord[domain.Message]{{Offset: 0, Message: message}}, nil).
+		Once()
+	queueInstance.GroupReader.
+		EXPECT().
+		CommitOffset("billing", int64(1)).
+		Return(nil).
+		Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, logConfig, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	s.Require().NoError(queuesInstance.CreateQueue(queueName, logConfig))
+	records, err := queuesInstance.ReadGroupFromQueue(ctx, queueName, "billing", 10)
+	s.Require().NoError(err)
+	s.Equal([]domain.LogRecord[domain.Message]{{Offset: 0, Message: message}}, records)
+	s.Require().NoError(queuesInstance.CommitGroupOffset(queueName, "billing", 1))
+}
+
+func (s *queuesTestSuite) TestReadGroup_NotALog() {
+	queueName := uuid.NewString()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		Return(mocks.NewQueue(s.T())).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	_, err := queuesInstance.ReadGroupFromQueue(context.Background(), queueName, "billing", 1)
+	s.Require().ErrorIs(err, domain.ErrNotALog)
+	err = queuesInstance.ResetGroupOffset(uuid.NewString(), "billing", domain.OffsetEarliest)
+	s.Require().ErrorIs(err, domain.ErrQueueNotFound)
+}
+
 func (s *queuesTestSuite) TestGet_ErrMaxQueueCrowded() {
 	ctx := context.Background()
 
 	factory := mocks.NewQueueFactory(s.T())
 	factory.
 		EXPECT().
-		Execute(maxLen).
+		Execute(mock.Anything, configs.Default, mock.Anything).
 		RunAndReturn(
-			func(_ int) domain.Queue {
+			func(_ string, _ domain.QueueConfig, _ domain.DeadLetter) domain.Queue {
 				queueInstance := mocks.NewQueue(s.T())
 				queueInstance.
 					EXPECT().
 					GetMessage(ctx).
-					Return(uuid.NewString(), nil)
+					Return(stampedMessage(uuid.NewString()), nil)
 				return queueInstance
 			},
 		).Times(maxCount)
 
-	queuesInstance := queues.NewQueues(factory.Execute, maxLen, maxCount)
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
 
 	wg := &sync.WaitGroup{}
 	for range maxCount {
@@ -128,13 +394,13 @@ func (s *queuesTestSuite) TestGet_ErrMaxQueueCrowded() {
 }
 
 func (s *queuesTestSuite) TestPush_QueueError() {
-	queueName, messageToPut := "put_test_queue", uuid.NewString()
+	queueName, messageToPut := "put_test_queue", stampedMessage(uuid.NewString())
 	ctx := context.Background()
 
 	queueInstance := mocks.NewQueue(s.T())
 	queueInstance.
 		EXPECT().
-		PutMessage(ctx, messageToPut).
+		PutMessage(ctx, messageToPut, domain.PutOptions{}).
 		Return(errors.New("some put error")).
 		Once()
 	queueInstance.
@@ -145,24 +411,25 @@ func (s *queuesTestSuite) TestPush_QueueError() {
 	factory := mocks.NewQueueFactory(s.T())
 	factory.
 		EXPECT().
-		Execute(maxLen).
+		Execute(queueName, configs.Default, mock.Anything).
 		Return(queueInstance).
 		Once()
 
-	queuesInstance := queues.NewQueues(factory.Execute, maxLen, maxCount)
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
 	defer queuesInstance.Close()
-	err := queuesInstance.PutMessageToQueue(ctx, queueName, messageToPut)
+	_, err := queuesInstance.PutMessageToQueue(ctx, queueName, messageToPut, domain.PutOptions{})
 	s.Require().EqualError(err, "put message to queue put_test_queue: some put error")
 }
 
 func (s *queuesTestSuite) TestGet_QueueError() {
 	ctx := context.Background()
+	queueName := "get_test_queue"
 
 	queueInstance := mocks.NewQueue(s.T())
 	queueInstance.
 		EXPECT().
 		GetMessage(ctx).
-		Return("", errors.New("some put error")).
+		Return(domain.Message{}, errors.New("some put error")).
 		Once()
 	queueInstance.
 		EXPECT().
@@ -172,18 +439,628 @@ func (s *queuesTestSuite) TestGet_QueueError() {
 	factory := mocks.NewQueueFactory(s.T())
 	factory.
 		EXPECT().
-		Execute(maxLen).
+		Execute(queueName, configs.Default, mock.Anything).
 		Return(queueInstance).
 		Once()
 
-	queuesInstance := queues.NewQueues(factory.Execute, maxLen, maxCount)
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
 	defer queuesInstance.Close()
-	queueName := "get_test_queue"
 	message, err := queuesInstance.GetMessageFromQueue(ctx, queueName)
 	s.Require().EqualError(err, "get message from queue get_test_queue: some put error")
 	s.Zero(message)
 }
 
+func (s *queuesTestSuite) TestLeaseAck_Success() {
+	queueName, receipt := uuid.NewString(), uuid.NewString()
+	ctx := context.Background()
+	lease := domain.Lease[domain.Message]{Message: stampedMessage(uuid.NewString()), Receipt: receipt}
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		LeaseMessage(ctx, time.Minute).
+		Return(lease, nil).
+		Once()
+	queueInstance.
+		EXPECT().
+		Ack(receipt).
+		Return(nil).
+		Once()
+	queueInstance.
+		EXPECT().
+		Nack(receipt).
+		Return(domain.ErrReceiptNotFound).
+		Once()
+
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	resultLease, err := queuesInstance.LeaseMessageFromQueue(ctx, queueName, time.Minute)
+	s.Require().NoError(err)
+	s.Equal(lease, resultLease)
+
+	s.Require().NoError(queuesInstance.AckMessage(queueName, receipt))
+	err = queuesInstance.NackMessage(queueName, receipt)
+	s.Require().ErrorIs(err, domain.ErrReceiptNotFound)
+}
+
+func (s *queuesTestSuite) TestAck_UnknownQueue() {
+	factory := mocks.NewQueueFactory(s.T())
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	err := queuesInstance.AckMessage(uuid.NewString(), uuid.NewString())
+	s.Require().ErrorIs(err, domain.ErrReceiptNotFound)
+	err = queuesInstance.NackMessage(uuid.NewString(), uuid.NewString())
+	s.Require().ErrorIs(err, domain.ErrReceiptNotFound)
+}
+
+func (s *queuesTestSuite) TestRestore_CreatesQueues() {
+	queueNames := []string{uuid.NewString(), uuid.NewString()}
+
+	factory := mocks.NewQueueFactory(s.T())
+	for _, queueName := range queueNames {
+		factory.
+			EXPECT().
+			Execute(queueName, configs.Default, mock.Anything).
+			Return(mocks.NewQueue(s.T())).
+			Once()
+	}
+
+	queuesInstance := queues.NewQueues(factory.Execute, len(queueNames), configs, "")
+	s.Require().NoError(queuesInstance.Restore(queueNames))
+	err := queuesInstance.Restore([]string{uuid.NewString()})
+	s.Require().ErrorIs(err, domain.ErrMaxCountQueuesCount)
+}
+
+// Настройки сохраняются только у явно созданных очередей, созданная сама очередь их не пишет.
+func (s *queuesTestSuite) TestCreateQueue_StoresConfig() {
+	created, automatic := uuid.NewString(), uuid.NewString()
+	config := domain.QueueConfig{Type: domain.QueueTypePriority, MaxLen: maxLen, Overflow: domain.OverflowReject}
+
+	createdInstance := mocks.NewQueue(s.T())
+	createdInstance.EXPECT().Close().Once()
+	createdInstance.EXPECT().Purge().Return(0).Once()
+	automaticInstance := mocks.NewQueue(s.T())
+	automaticInstance.EXPECT().Close().Once()
+	automaticInstance.EXPECT().Purge().Return(0).Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.EXPECT().Execute(created, config, mock.Anything).Return(createdInstance).Once()
+	factory.EXPECT().Execute(automatic, configs.Default, mock.Anything).Return(automaticInstance).Once()
+	configStore := mocks.NewQueueConfigStore(s.T())
+	configStore.EXPECT().SaveQueueConfig(created, config).Return(nil).Once()
+	configStore.EXPECT().DeleteQueueConfig(created).Return(nil).Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	queuesInstance.StoreConfigs(configStore)
+	s.Require().NoError(queuesInstance.CreateQueue(created, config))
+	s.Require().NoError(queuesInstance.Restore([]string{automatic}))
+	s.Require().NoError(queuesInstance.DeleteQueue(created))
+	s.Require().NoError(queuesInstance.DeleteQueue(automatic))
+}
+
+// Не сохранилось - очередь не создаем, иначе после рестарта она вернется с настройками по умолчанию.
+func (s *queuesTestSuite) TestCreateQueue_ConfigNotStored() {
+	queueName := uuid.NewString()
+	storeErr := errors.New("disk is full")
+
+	configStore := mocks.NewQueueConfigStore(s.T())
+	configStore.EXPECT().SaveQueueConfig(queueName, configs.Default).Return(storeErr).Once()
+
+	queuesInstance := queues.NewQueues(mocks.NewQueueFactory(s.T()).Execute, maxCount, configs, "")
+	queuesInstance.StoreConfigs(configStore)
+	s.Require().ErrorIs(queuesInstance.CreateQueue(queueName, domain.QueueConfig{}), storeErr)
+	s.Empty(queuesInstance.ListQueues())
+}
+
+// Восстановленная с настройками очередь считается созданной явно, очередь из журнала с тем же именем - та же.
+func (s *queuesTestSuite) TestRestoreConfigs_CreatesExplicitQueues() {
+	queueName := uuid.NewString()
+	config := domain.QueueConfig{Type: domain.QueueTypePriority, MaxLen: maxLen, Overflow: domain.OverflowReject}
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.EXPECT().Stats().Return(domain.QueueStats{}).Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.EXPECT().Execute(queueName, config, mock.Anything).Return(queueInstance).Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	s.Require().NoError(queuesInstance.RestoreConfigs(map[string]domain.QueueConfig{queueName: config}))
+	s.Require().NoError(queuesInstance.Restore([]string{queueName}))
+	s.ErrorIs(queuesInstance.CreateQueue(queueName, config), domain.ErrQueueExists)
+
+	info, err := queuesInstance.QueueInfo(queueName)
+	s.Require().NoError(err)
+	s.Equal(config, info.Config)
+}
+
+func (s *queuesTestSuite) TestCreate_ConfigByQueue() {
+	queueName, messageToPut := uuid.NewString(), stampedMessage(uuid.NewString())
+	ctx := context.Background()
+	rejectConfig := domain.QueueConfig{MaxLen: maxLen, Overflow: domain.OverflowReject, MessageTTL: time.Minute}
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		PutMessage(ctx, messageToPut, domain.PutOptions{}).
+		Return(domain.ErrQueueFull).
+		Once()
+
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, rejectConfig, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, domain.QueueConfigs{
+		Default: configs.Default,
+		ByQueue: map[string]domain.QueueConfig{queueName: rejectConfig},
+	}, "")
+	_, err := queuesInstance.PutMessageToQueue(ctx, queueName, messageToPut, domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueFull)
+}
+
+func (s *queuesTestSuite) TestCreateQueue_ConfigWithDefaults() {
+	queueName := uuid.NewString()
+	ctx := context.Background()
+	message := stampedMessage("fits")
+	expectedConfig := domain.QueueConfig{MaxLen: maxLen, Overflow: domain.OverflowReject, MaxMessageBytes: 4}
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		PutMessage(ctx, message, domain.PutOptions{}).
+		Return(nil).
+		Once()
+	queueInstance.
+		EXPECT().
+		Stats().
+		Return(domain.QueueStats{}).
+		Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, expectedConfig, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	config := domain.QueueConfig{Overflow: domain.OverflowReject, MaxMessageBytes: 4}
+	s.Require().NoError(queuesInstance.CreateQueue(queueName, config))
+	s.ErrorIs(queuesInstance.CreateQueue(queueName, config), domain.ErrQueueExists)
+	s.ErrorIs(queuesInstance.CreateQueue(uuid.NewString(), domain.QueueConfig{MaxLen: -1}), domain.ErrInvalidQueueConfig)
+	s.ErrorIs(
+		queuesInstance.CreateQueue(uuid.NewString(), domain.QueueConfig{MessageTTL: 500 * time.Millisecond}),
+		domain.ErrInvalidQueueConfig,
+	)
+	s.ErrorIs(
+		queuesInstance.CreateQueue(uuid.NewString(), domain.QueueConfig{DedupWindow: 1500 * time.Millisecond}),
+		domain.ErrInvalidQueueConfig,
+	)
+	s.ErrorIs(queuesInstance.CreateQueue(uuid.NewString(), domain.QueueConfig{Type: "lifo"}), domain.ErrUnknownQueueType)
+
+	id, err := queuesInstance.PutMessageToQueue(ctx, queueName, message, domain.PutOptions{})
+	s.Require().NoError(err)
+	s.Equal(message.ID, id)
+	batch := []domain.Message{{Body: []byte("ok")}, {Body: []byte("too large")}}
+	_, err = queuesInstance.PutMessagesToQueue(ctx, queueName, batch, domain.PutOptions{})
+	s.ErrorIs(err, domain.ErrMessageTooLarge)
+
+	info, err := queuesInstance.QueueInfo(queueName)
+	s.Require().NoError(err)
+	s.Equal(expectedConfig, info.Config)
+}
+
+// Лог поверх журнала не хранится, такой очереди фабрику не вызываем вовсе.
+func (s *queuesTestSuite) TestCreateQueue_RejectedType() {
+	queuesInstance := queues.NewQueues(mocks.NewQueueFactory(s.T()).Execute, maxCount, configs, "")
+	queuesInstance.RejectQueueTypes(domain.QueueTypeLog)
+
+	err := queuesInstance.CreateQueue(uuid.NewString(), domain.QueueConfig{Type: domain.QueueTypeLog})
+	s.Require().ErrorIs(err, domain.ErrNotSupported)
+	s.Empty(queuesInstance.ListQueues())
+}
+
+func (s *queuesTestSuite) TestExplicitCreate_UnknownQueueNotFound() {
+	queueName, restoredName := uuid.NewString(), uuid.NewString()
+	ctx := context.Background()
+	message := stampedMessage("message")
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		PutMessage(ctx, message, domain.PutOptions{}).
+		Return(nil).
+		Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+	// Очереди из журнала создаем и без явного создания, иначе потеряем их сообщения.
+	factory.
+		EXPECT().
+		Execute(restoredName, configs.Default, mock.Anything).
+		Return(mocks.NewQueue(s.T())).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	queuesInstance.RequireExplicitCreate()
+	s.Require().NoError(queuesInstance.Restore([]string{restoredName}))
+
+	_, err := queuesInstance.GetMessageFromQueue(ctx, queueName)
+	s.Require().ErrorIs(err, domain.ErrQueueNotFound)
+	_, err = queuesInstance.PutMessageToQueue(ctx, queueName, message, domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueNotFound)
+
+	s.Require().NoError(queuesInstance.CreateQueue(queueName, domain.QueueConfig{}))
+	_, err = queuesInstance.PutMessageToQueue(ctx, queueName, message, domain.PutOptions{})
+	s.Require().NoError(err)
+}
+
+func (s *queuesTestSuite) TestDrain_RejectsPuts() {
+	queueName, newName := uuid.NewString(), uuid.NewString()
+	ctx := context.Background()
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.EXPECT().Drain().Once()
+	// Созданная после начала остановки очередь тоже сразу останавливается.
+	newQueueInstance := mocks.NewQueue(s.T())
+	newQueueInstance.EXPECT().Drain().Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+	factory.
+		EXPECT().
+		Execute(newName, configs.Default, mock.Anything).
+		Return(newQueueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	s.Require().NoError(queuesInstance.Restore([]string{queueName}))
+	queuesInstance.Drain()
+
+	message := domain.Message{Body: []byte("message")}
+	_, err := queuesInstance.PutMessageToQueue(ctx, queueName, message, domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrShuttingDown)
+	_, err = queuesInstance.PutMessagesToQueue(ctx, newName, []domain.Message{message}, domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrShuttingDown)
+	s.Require().ErrorIs(queuesInstance.CreateQueue(uuid.NewString(), domain.QueueConfig{}), domain.ErrShuttingDown)
+}
+
+func (s *queuesTestSuite) TestDeadLetter_RoutesToSuffixedQueue() {
+	const suffix = ".dlq"
+	queueName, message := uuid.NewString(), stampedMessage(uuid.NewString())
+
+	deadLetterQueue := mocks.NewQueue(s.T())
+	deadLetterQueue.
+		EXPECT().
+		PutMessage(mock.Anything, message, domain.PutOptions{}).
+		Return(nil).
+		Once()
+
+	var deadLetter domain.DeadLetter
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		RunAndReturn(func(_ string, _ domain.QueueConfig, queueDeadLetter domain.DeadLetter) domain.Queue {
+			deadLetter = queueDeadLetter
+			return mocks.NewQueue(s.T())
+		}).
+		Once()
+	factory.
+		EXPECT().
+		Execute(queueName+suffix, configs.Default, mock.Anything).
+		RunAndReturn(func(_ string, _ domain.QueueConfig, queueDeadLetter domain.DeadLetter) domain.Queue {
+			// У очереди недоставленных своей такой очереди нет.
+			s.Nil(queueDeadLetter)
+			return deadLetterQueue
+		}).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, suffix)
+	s.Require().NoError(queuesInstance.Restore([]string{queueName}))
+	s.Require().NotNil(deadLetter)
+	deadLetter(message)
+}
+
+func (s *queuesTestSuite) TestRedrive_MovesDeadLetters() {
+	const suffix = ".dlq"
+	queueName := uuid.NewString()
+	ctx := context.Background()
+	first, second := stampedMessage("first"), stampedMessage("second")
+
+	sourceQueue := mocks.NewQueue(s.T())
+	deadLetterQueue := mocks.NewQueue(s.T())
+	deadLetterQueue.EXPECT().Stats().Return(domain.QueueStats{Depth: 3}).Once()
+	deadLetterQueue.EXPECT().GetMessage(mock.Anything).Return(first, nil).Once()
+	deadLetterQueue.EXPECT().GetMessage(mock.Anything).Return(second, nil).Once()
+	sourceQueue.EXPECT().PutMessage(ctx, first, domain.PutOptions{}).Return(nil).Once()
+	// Второе не влезло, возвращается в очередь недоставленных.
+	sourceQueue.EXPECT().PutMessage(ctx, second, domain.PutOptions{}).Return(domain.ErrQueueFull).Once()
+	deadLetterQueue.EXPECT().PutMessage(mock.Anything, second, domain.PutOptions{}).Return(nil).Once()
+
+	factory := mocks.NewQueueFactory(s.T())
+	factory.EXPECT().Execute(queueName, configs.Default, mock.Anything).Return(sourceQueue).Once()
+	factory.EXPECT().Execute(queueName+suffix, configs.Default, mock.Anything).Return(deadLetterQueue).Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, suffix)
+	redriven, err := queuesInstance.RedriveQueue(ctx, queueName)
+	s.Require().NoError(err)
+	s.Zero(redriven, "очереди недоставленных еще нет")
+
+	s.Require().NoError(queuesInstance.Restore([]string{queueName + suffix}))
+	redriven, err = queuesInstance.RedriveQueue(ctx, queueName)
+	s.ErrorIs(err, domain.ErrQueueFull)
+	s.Equal(1, redriven)
+
+	_, err = queuesInstance.RedriveQueue(ctx, queueName+suffix)
+	s.ErrorIs(err, domain.ErrNoDeadLetterQueue)
+}
+
+func (s *queuesTestSuite) TestManagement_ListPurgeDelete() {
+	queueName, messageToPut := uuid.NewString(), stampedMessage(uuid.NewString())
+	ctx := context.Background()
+	stats := domain.QueueStats{Depth: 1, Waiting: 0, InFlight: 2}
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		PutMessage(ctx, messageToPut, domain.PutOptions{}).
+		Return(nil).
+		Once()
+	queueInstance.
+		EXPECT().
+		Stats().
+		Return(stats).
+		Twice()
+	queueInstance.
+		EXPECT().
+		Purge().
+		Return(3).
+		Twice()
+	queueInstance.
+		EXPECT().
+		Close().
+		Once()
+
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	_, err := queuesInstance.PutMessageToQueue(ctx, queueName, messageToPut, domain.PutOptions{})
+	s.Require().NoError(err)
+
+	infos := queuesInstance.ListQueues()
+	s.Require().Len(infos, 1)
+	s.Equal(queueName, infos[0].Name)
+	s.Equal(stats, infos[0].QueueStats)
+	s.NotZero(infos[0].CreatedAt)
+	info, err := queuesInstance.QueueInfo(queueName)
+	s.Require().NoError(err)
+	s.Equal(infos[0], info)
+
+	purged, err := queuesInstance.PurgeQueue(queueName)
+	s.Require().NoError(err)
+	s.Equal(3, purged)
+
+	s.Require().NoError(queuesInstance.DeleteQueue(queueName))
+	s.Empty(queuesInstance.ListQueues())
+	_, err = queuesInstance.QueueInfo(queueName)
+	s.Require().ErrorIs(err, domain.ErrQueueNotFound)
+}
+
+func (s *queuesTestSuite) TestManagement_ErrQueueNotFound() {
+	factory := mocks.NewQueueFactory(s.T())
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	_, err := queuesInstance.PurgeQueue(uuid.NewString())
+	s.Require().ErrorIs(err, domain.ErrQueueNotFound)
+	err = queuesInstance.DeleteQueue(uuid.NewString())
+	s.Require().ErrorIs(err, domain.ErrQueueNotFound)
+	_, err = queuesInstance.PeekQueue(uuid.NewString(), 1)
+	s.Require().ErrorIs(err, domain.ErrQueueNotFound)
+}
+
+func (s *queuesTestSuite) TestJanitor_EvictsIdleQueues() {
+	const ttl = time.Minute
+	idleName, busyName := uuid.NewString(), uuid.NewString()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	idleQueue := mocks.NewQueue(s.T())
+	idleQueue.
+		EXPECT().
+		Stats().
+		Return(domain.QueueStats{})
+	idleQueue.
+		EXPECT().
+		Close().
+		Once()
+	busyQueue := mocks.NewQueue(s.T())
+	busyQueue.
+		EXPECT().
+		Stats().
+		Return(domain.QueueStats{Waiting: 1})
+
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(mock.Anything, configs.Default, mock.Anything).
+		RunAndReturn(func(queueName string, _ domain.QueueConfig, _ domain.DeadLetter) domain.Queue {
+			if queueName == idleName {
+				return idleQueue
+			}
+			return busyQueue
+		}).
+		Twice()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	s.Require().NoError(queuesInstance.Restore([]string{idleName, busyName}))
+	janitor := queues.NewJanitor(queuesInstance, ttl, clock)
+
+	janitor.Sweep()
+	now = now.Add(ttl - time.Second)
+	janitor.Sweep()
+	s.Len(queuesInstance.ListQueues(), 2)
+
+	now = now.Add(time.Second)
+	janitor.Sweep()
+	infos := queuesInstance.ListQueues()
+	s.Require().Len(infos, 1)
+	s.Equal(busyName, infos[0].Name)
+}
+
+// Явно созданную очередь не удаляем, иначе при следующем обращении она создастся с настройками по умолчанию.
+func (s *queuesTestSuite) TestJanitor_KeepsExplicitQueues() {
+	const ttl = time.Minute
+	queueName := uuid.NewString()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.
+		EXPECT().
+		Stats().
+		Return(domain.QueueStats{})
+	factory := mocks.NewQueueFactory(s.T())
+	factory.
+		EXPECT().
+		Execute(queueName, configs.Default, mock.Anything).
+		Return(queueInstance).
+		Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	s.Require().NoError(queuesInstance.CreateQueue(queueName, domain.QueueConfig{}))
+	janitor := queues.NewJanitor(queuesInstance, ttl, clock)
+	janitor.Sweep()
+	now = now.Add(2 * ttl)
+	janitor.Sweep()
+	s.Len(queuesInstance.ListQueues(), 1)
+}
+
+// Лог не удаляем, иначе группы потеряют офсеты и перечитают его с начала.
+func (s *queuesTestSuite) TestJanitor_KeepsLogQueues() {
+	const ttl = time.Minute
+	queueName := uuid.NewString()
+	logConfigs := domain.QueueConfigs{Default: domain.QueueConfig{Type: domain.QueueTypeLog, MaxLen: maxLen}}
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.EXPECT().Stats().Return(domain.QueueStats{})
+	factory := mocks.NewQueueFactory(s.T())
+	factory.EXPECT().Execute(queueName, logConfigs.Default, mock.Anything).Return(queueInstance).Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, logConfigs, "")
+	s.Require().NoError(queuesInstance.Restore([]string{queueName}))
+	janitor := queues.NewJanitor(queuesInstance, ttl, clock)
+	janitor.Sweep()
+	now = now.Add(2 * ttl)
+	janitor.Sweep()
+	s.Len(queuesInstance.ListQueues(), 1)
+}
+
+// Пока помним ключи идемпотентности, очередь не удаляем, иначе повтор положит сообщение еще раз.
+func (s *queuesTestSuite) TestJanitor_KeepsQueuesWithIdempotencyKeys() {
+	const ttl = time.Minute
+	queueName := uuid.NewString()
+	ctx := context.Background()
+	options := domain.PutOptions{IdempotencyKey: "key"}
+	dedupConfigs := domain.QueueConfigs{Default: domain.QueueConfig{MaxLen: maxLen, DedupWindow: time.Hour}}
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.EXPECT().PutMessage(ctx, mock.Anything, options).Return(nil).Once()
+	queueInstance.EXPECT().Stats().Return(domain.QueueStats{})
+	factory := mocks.NewQueueFactory(s.T())
+	factory.EXPECT().Execute(queueName, dedupConfigs.Default, mock.Anything).Return(queueInstance).Once()
+
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, dedupConfigs, "")
+	_, err := queuesInstance.PutMessageToQueue(ctx, queueName, stampedMessage("message"), options)
+	s.Require().NoError(err)
+	janitor := queues.NewJanitor(queuesInstance, ttl, clock)
+	janitor.Sweep()
+	now = now.Add(2 * ttl)
+	janitor.Sweep()
+	s.Len(queuesInstance.ListQueues(), 1)
+}
+
+// Janitor удалил очередь, пока запрос уже был в ней: запрос не получает ErrQueueClosed, а повторяется с новой.
+func (s *queuesTestSuite) TestJanitor_EvictedDuringRequestRetried() {
+	const ttl = time.Minute
+	queueName, message := uuid.NewString(), stampedMessage("message")
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	var janitor *queues.Janitor
+	evictedQueue := mocks.NewQueue(s.T())
+	evictedQueue.EXPECT().Stats().Return(domain.QueueStats{})
+	evictedQueue.EXPECT().Close().Once()
+	evictedQueue.
+		EXPECT().
+		GetMessage(ctx).
+		RunAndReturn(func(context.Context) (domain.Message, error) {
+			now = now.Add(ttl)
+			janitor.Sweep()
+			return domain.Message{}, domain.ErrQueueClosed
+		}).
+		Once()
+	newQueue := mocks.NewQueue(s.T())
+	newQueue.EXPECT().GetMessage(ctx).Return(message, nil).Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.EXPECT().Execute(queueName, configs.Default, mock.Anything).Return(evictedQueue).Once()
+	factory.EXPECT().Execute(queueName, configs.Default, mock.Anything).Return(newQueue).Once()
+	queuesInstance := queues.NewQueues(factory.Execute, maxCount, configs, "")
+	janitor = queues.NewJanitor(queuesInstance, ttl, clock)
+
+	s.Require().NoError(queuesInstance.Restore([]string{queueName}))
+	janitor.Sweep()
+	got, err := queuesInstance.GetMessageFromQueue(ctx, queueName)
+	s.Require().NoError(err)
+	s.Equal(message, got)
+}
+
+// Удаленную через DeleteQueue очередь не пересоздаем, ждущий получает ErrQueueClosed.
+func (s *queuesTestSuite) TestDeleteQueue_DuringRequestNotRetried() {
+	queueName := uuid.NewString()
+	ctx := context.Background()
+
+	var queuesInstance *queues.Queues
+	queueInstance := mocks.NewQueue(s.T())
+	queueInstance.EXPECT().Close().Once()
+	queueInstance.EXPECT().Purge().Return(0).Once()
+	queueInstance.
+		EXPECT().
+		GetMessage(ctx).
+		RunAndReturn(func(context.Context) (domain.Message, error) {
+			s.Require().NoError(queuesInstance.DeleteQueue(queueName))
+			return domain.Message{}, domain.ErrQueueClosed
+		}).
+		Once()
+	factory := mocks.NewQueueFactory(s.T())
+	factory.EXPECT().Execute(queueName, configs.Default, mock.Anything).Return(queueInstance).Once()
+	queuesInstance = queues.NewQueues(factory.Execute, maxCount, configs, "")
+
+	_, err := queuesInstance.GetMessageFromQueue(ctx, queueName)
+	s.Require().ErrorIs(err, domain.ErrQueueClosed)
+}
+
 func TestQueues(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, new(queuesTestSuite))
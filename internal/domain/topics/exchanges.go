@@ -0,0 +1,103 @@
+package topics
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Exchanges Привязки очередей к обменникам по шаблону. Как и подписки топиков,
+// очередь при привязке не проверяем, а привязки живут только в памяти.
+type Exchanges struct {
+	// Bindings Привязки обменника, отсортированы по шаблону и очереди.
+	bindings map[string][]domain.Binding
+	producer domain.QueuesProducer
+	rw       *sync.RWMutex
+}
+
+func NewExchanges(producer domain.QueuesProducer) *Exchanges {
+	return &Exchanges{
+		bindings: make(map[string][]domain.Binding),
+		producer: producer,
+		rw:       &sync.RWMutex{},
+	}
+}
+
+func (exchanges *Exchanges) Bind(exchange string, binding domain.Binding) error {
+	pattern, err := normalizePattern(binding.Pattern)
+	if err != nil {
+		return err
+	}
+	binding.Pattern = pattern
+	exchanges.rw.Lock()
+	defer exchanges.rw.Unlock()
+	bindings := exchanges.bindings[exchange]
+	i, exist := slices.BinarySearchFunc(bindings, binding, compareBindings)
+	if !exist {
+		exchanges.bindings[exchange] = slices.Insert(bindings, i, binding)
+	}
+	return nil
+}
+
+// Unbind Шаблон сравниваем в том же виде, в каком его сохранил Bind.
+func (exchanges *Exchanges) Unbind(exchange string, binding domain.Binding) error {
+	pattern, err := normalizePattern(binding.Pattern)
+	if err != nil {
+		return domain.ErrBindingNotFound
+	}
+	binding.Pattern = pattern
+	exchanges.rw.Lock()
+	defer exchanges.rw.Unlock()
+	bindings := exchanges.bindings[exchange]
+	i, exist := slices.BinarySearchFunc(bindings, binding, compareBindings)
+	if !exist {
+		return domain.ErrBindingNotFound
+	}
+	bindings = slices.Delete(bindings, i, i+1)
+	if len(bindings) == 0 {
+		delete(exchanges.bindings, exchange)
+		return nil
+	}
+	exchanges.bindings[exchange] = bindings
+	return nil
+}
+
+func (exchanges *Exchanges) Bindings(exchange string) []domain.Binding {
+	exchanges.rw.RLock()
+	defer exchanges.rw.RUnlock()
+	return slices.Clone(exchanges.bindings[exchange])
+}
+
+func (exchanges *Exchanges) Publish(
+	ctx context.Context,
+	exchange string,
+	routingKey string,
+	message domain.Message,
+	options domain.PutOptions,
+) (domain.Published, error) {
+	published, err := fanOut(ctx, exchanges.producer, exchanges.route(exchange, routingKey), message, options)
+	if err != nil {
+		return published, fmt.Errorf("publish to exchange %s with key %s: %w", exchange, routingKey, err)
+	}
+	return published, nil
+}
+
+// route Очереди, шаблон которых подходит под ключ, каждая один раз.
+func (exchanges *Exchanges) route(exchange string, routingKey string) []string {
+	var queueNames []string
+	for _, binding := range exchanges.Bindings(exchange) {
+		if matchPattern(binding.Pattern, routingKey) {
+			queueNames = append(queueNames, binding.Queue)
+		}
+	}
+	slices.Sort(queueNames)
+	return slices.Compact(queueNames)
+}
+
+func compareBindings(a domain.Binding, b domain.Binding) int {
+	return cmp.Or(cmp.Compare(a.Pattern, b.Pattern), cmp.Compare(a.Queue, b.Queue))
+}
@@ -0,0 +1,144 @@
+package topics_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/domain/topics"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+type exchangesTestSuite struct {
+	suite.Suite
+}
+
+func (s *exchangesTestSuite) TestPublish_Patterns() {
+	cases := []struct {
+		pattern    string
+		routingKey string
+		match      bool
+	}{
+		{"orders.eu.created", "orders.eu.created", true},
+		{"orders.*.created", "orders.eu.created", true},
+		{"orders.*.created", "orders.eu.north.created", false},
+		{"orders.*", "orders", false},
+		{"orders.#", "orders", true},
+		{"orders.#", "orders.eu.created", true},
+		{"#.created", "orders.eu.created", true},
+		{"orders.#.created", "orders.created", true},
+		{"orders.#.created", "orders.eu.updated", false},
+		{"#", "anything.at.all", true},
+		{"orders.eu", "orders.us", false},
+		{"orders.#.#.created", "orders.created", true},
+		{"#.eu.#.created", "orders.eu.north.created", true},
+		{"#.eu.#.created", "orders.us.north.created", false},
+	}
+	for _, testCase := range cases {
+		s.Run(testCase.pattern+" "+testCase.routingKey, func() {
+			ctx := context.Background()
+			exchange := uuid.NewString()
+			producer := mocks.NewQueuesProducer(s.T())
+			want := 0
+			if testCase.match {
+				want = 1
+				producer.
+					EXPECT().
+					PutMessageToQueue(ctx, "queue", stamped, domain.PutOptions{}).
+					Return("id", nil).
+					Once()
+			}
+			exchanges := topics.NewExchanges(producer)
+			s.Require().NoError(exchanges.Bind(exchange, domain.Binding{Pattern: testCase.pattern, Queue: "queue"}))
+
+			published, err := exchanges.Publish(ctx, exchange, testCase.routingKey, stamped, domain.PutOptions{})
+			s.Require().NoError(err)
+			s.Equal(want, published.Delivered)
+		})
+	}
+}
+
+func (s *exchangesTestSuite) TestPublish_OneCopyPerQueue() {
+	ctx := context.Background()
+	exchange := uuid.NewString()
+	producer := mocks.NewQueuesProducer(s.T())
+	producer.
+		EXPECT().
+		PutMessageToQueue(ctx, "all", stamped, domain.PutOptions{}).
+		Return("id", nil).
+		Once()
+	producer.
+		EXPECT().
+		PutMessageToQueue(ctx, "eu", stamped, domain.PutOptions{}).
+		Return("id", nil).
+		Once()
+
+	exchanges := topics.NewExchanges(producer)
+	s.Require().NoError(exchanges.Bind(exchange, domain.Binding{Pattern: "orders.#", Queue: "all"}))
+	s.Require().NoError(exchanges.Bind(exchange, domain.Binding{Pattern: "orders.eu.*", Queue: "all"}))
+	s.Require().NoError(exchanges.Bind(exchange, domain.Binding{Pattern: "orders.eu.*", Queue: "eu"}))
+	s.Require().NoError(exchanges.Bind(exchange, domain.Binding{Pattern: "orders.us.*", Queue: "us"}))
+
+	published, err := exchanges.Publish(ctx, exchange, "orders.eu.created", stamped, domain.PutOptions{})
+	s.Require().NoError(err)
+	s.Equal(2, published.Delivered)
+}
+
+// Несколько # на длинном ключе без совпадения не должны перебирать варианты экспоненциально долго.
+func (s *exchangesTestSuite) TestPublish_PathologicalPattern() {
+	exchange := uuid.NewString()
+	exchanges := topics.NewExchanges(mocks.NewQueuesProducer(s.T()))
+	patterns := []string{"#.#.#.#.#.#.#.#.x", "#.a.#.a.#.a.#.a.#.a.#.a.#.a.#.x"}
+	for _, pattern := range patterns {
+		s.Require().NoError(exchanges.Bind(exchange, domain.Binding{Pattern: pattern, Queue: "queue"}))
+	}
+	routingKey := strings.Repeat("a.", 200) + "y"
+
+	start := time.Now()
+	published, err := exchanges.Publish(context.Background(), exchange, routingKey, stamped, domain.PutOptions{})
+	s.Require().NoError(err)
+	s.Zero(published.Delivered)
+	s.Less(time.Since(start), time.Second)
+}
+
+func (s *exchangesTestSuite) TestBind_BadPattern() {
+	exchanges := topics.NewExchanges(mocks.NewQueuesProducer(s.T()))
+	for _, pattern := range []string{"", "orders..created", "orders.eu*", "orders.#x"} {
+		err := exchanges.Bind(uuid.NewString(), domain.Binding{Pattern: pattern, Queue: "queue"})
+		s.Require().ErrorIs(err, domain.ErrBadPattern, pattern)
+	}
+}
+
+func (s *exchangesTestSuite) TestUnbind() {
+	exchange := uuid.NewString()
+	binding := domain.Binding{Pattern: "orders.#", Queue: "queue"}
+	exchanges := topics.NewExchanges(mocks.NewQueuesProducer(s.T()))
+	s.Require().NoError(exchanges.Bind(exchange, binding))
+	s.Require().NoError(exchanges.Bind(exchange, binding))
+	s.Equal([]domain.Binding{binding}, exchanges.Bindings(exchange))
+
+	s.Require().NoError(exchanges.Unbind(exchange, binding))
+	s.Empty(exchanges.Bindings(exchange))
+	s.Require().ErrorIs(exchanges.Unbind(exchange, binding), domain.ErrBindingNotFound)
+}
+
+// Подряд идущие # склеиваются, отвязать можно по любому из равных шаблонов.
+func (s *exchangesTestSuite) TestBind_CollapsesAnyWords() {
+	exchange := uuid.NewString()
+	exchanges := topics.NewExchanges(mocks.NewQueuesProducer(s.T()))
+	s.Require().NoError(exchanges.Bind(exchange, domain.Binding{Pattern: "orders.#.#.#", Queue: "queue"}))
+	s.Equal([]domain.Binding{{Pattern: "orders.#", Queue: "queue"}}, exchanges.Bindings(exchange))
+
+	s.Require().NoError(exchanges.Unbind(exchange, domain.Binding{Pattern: "orders.#.#", Queue: "queue"}))
+	s.Empty(exchanges.Bindings(exchange))
+}
+
+func TestExchanges(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(exchangesTestSuite))
+}
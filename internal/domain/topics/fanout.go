@@ -0,0 +1,64 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// fanOut Кладем во все очереди одновременно, чтобы заполненная очередь с политикой block
+// не задерживала остальные. Не положенное в одну очередь не отменяет остальные.
+// Копии во всех очередях - одно сообщение, с одним ID.
+// Ключ идемпотентности уходит в каждую очередь, повтор публикации не кладет копию туда, где она уже есть.
+func fanOut(
+	ctx context.Context,
+	producer domain.QueuesProducer,
+	queueNames []string,
+	message domain.Message,
+	options domain.PutOptions,
+) (domain.Published, error) {
+	message = message.Stamp(time.Now())
+	ids := make([]string, len(queueNames))
+	errs := make([]error, len(queueNames))
+	wg := &sync.WaitGroup{}
+	for i, queueName := range queueNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := producer.PutMessageToQueue(ctx, queueName, message, options)
+			if err != nil {
+				errs[i] = fmt.Errorf("queue %s: %w", queueName, err)
+				return
+			}
+			ids[i] = id
+		}()
+	}
+	wg.Wait()
+	published := domain.Published{ID: firstID(message.ID, ids), Delivered: len(queueNames) - countErrors(errs)}
+	return published, errors.Join(errs...)
+}
+
+// firstID При повторе по ключу очереди отдают id первой отправки, его и возвращаем.
+// Очередь, куда первая отправка не попала, получает копию с новым id.
+func firstID(id string, ids []string) string {
+	for _, queueID := range ids {
+		if queueID != "" && queueID != id {
+			return queueID
+		}
+	}
+	return id
+}
+
+func countErrors(errs []error) int {
+	count := 0
+	for _, err := range errs {
+		if err != nil {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,73 @@
+package topics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+const (
+	wordSeparator = "."
+	anyWord       = "*"
+	anyWords      = "#"
+)
+
+// normalizePattern Слова не пустые, * и # только целым словом: orders.*.created, orders.#, но не orders.eu*.
+// Подряд идущие # склеиваем в один: orders.#.# подходит под те же ключи, что и orders.#.
+func normalizePattern(pattern string) (string, error) {
+	words := strings.Split(pattern, wordSeparator)
+	for _, word := range words {
+		if word == "" {
+			return "", fmt.Errorf("%w: empty word in %q", domain.ErrBadPattern, pattern)
+		}
+		if word != anyWord && word != anyWords && strings.ContainsAny(word, anyWord+anyWords) {
+			return "", fmt.Errorf("%w: wildcard inside word in %q", domain.ErrBadPattern, pattern)
+		}
+	}
+	words = slices.CompactFunc(words, func(a string, b string) bool { return a == anyWords && b == anyWords })
+	return strings.Join(words, wordSeparator), nil
+}
+
+func matchPattern(pattern string, routingKey string) bool {
+	return matchWords(strings.Split(pattern, wordSeparator), strings.Split(routingKey, wordSeparator))
+}
+
+// matchWords Без перебора с возвратом, иначе шаблон из нескольких # на длинном ключе занимал бы процессор
+// экспоненциально долго. matched[j] - уже пройденные слова шаблона подходят под первые j слов ключа.
+func matchWords(pattern []string, key []string) bool {
+	matched := make([]bool, len(key)+1)
+	matched[0] = true
+	for _, word := range pattern {
+		if word == anyWords {
+			matched = matchAnyWords(matched)
+			continue
+		}
+		matched = matchNextWord(matched, word, key)
+	}
+	return matched[len(key)]
+}
+
+// matchAnyWords # забирает от нуля до всех оставшихся слов ключа.
+func matchAnyWords(matched []bool) []bool {
+	next := make([]bool, len(matched))
+	reached := false
+	for j, ok := range matched {
+		reached = reached || ok
+		next[j] = reached
+	}
+	return next
+}
+
+func matchNextWord(matched []bool, word string, key []string) []bool {
+	next := make([]bool, len(matched))
+	for j := 1; j < len(matched); j++ {
+		next[j] = matched[j-1] && matchWord(word, key[j-1])
+	}
+	return next
+}
+
+func matchWord(pattern string, word string) bool {
+	return pattern == anyWord || pattern == word
+}
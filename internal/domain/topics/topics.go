@@ -0,0 +1,76 @@
+package topics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Topics Подписки топиков на очереди. Очередь при подписке не проверяем и не создаем,
+// с ней разбираются при публикации так же, как при обычном PUT.
+// Подписки живут в памяти и после рестарта пропадают.
+type Topics struct {
+	// Subscriptions Очереди топика, отсортированы по имени.
+	subscriptions map[string][]string
+	producer      domain.QueuesProducer
+	rw            *sync.RWMutex
+}
+
+func NewTopics(producer domain.QueuesProducer) *Topics {
+	return &Topics{
+		subscriptions: make(map[string][]string),
+		producer:      producer,
+		rw:            &sync.RWMutex{},
+	}
+}
+
+func (topics *Topics) Subscribe(topic string, queueName string) error {
+	topics.rw.Lock()
+	defer topics.rw.Unlock()
+	queueNames := topics.subscriptions[topic]
+	i, exist := slices.BinarySearch(queueNames, queueName)
+	if !exist {
+		topics.subscriptions[topic] = slices.Insert(queueNames, i, queueName)
+	}
+	return nil
+}
+
+func (topics *Topics) Unsubscribe(topic string, queueName string) error {
+	topics.rw.Lock()
+	defer topics.rw.Unlock()
+	queueNames := topics.subscriptions[topic]
+	i, exist := slices.BinarySearch(queueNames, queueName)
+	if !exist {
+		return domain.ErrSubscriptionNotFound
+	}
+	queueNames = slices.Delete(queueNames, i, i+1)
+	if len(queueNames) == 0 {
+		delete(topics.subscriptions, topic)
+		return nil
+	}
+	topics.subscriptions[topic] = queueNames
+	return nil
+}
+
+func (topics *Topics) Subscriptions(topic string) []string {
+	topics.rw.RLock()
+	defer topics.rw.RUnlock()
+	return slices.Clone(topics.subscriptions[topic])
+}
+
+// Publish Подписки берем на момент публикации, подписавшиеся позже сообщение не получат.
+func (topics *Topics) Publish(
+	ctx context.Context,
+	topic string,
+	message domain.Message,
+	options domain.PutOptions,
+) (domain.Published, error) {
+	published, err := fanOut(ctx, topics.producer, topics.Subscriptions(topic), message, options)
+	if err != nil {
+		return published, fmt.Errorf("publish to topic %s: %w", topic, err)
+	}
+	return published, nil
+}
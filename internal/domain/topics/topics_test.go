@@ -0,0 +1,135 @@
+package topics_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/domain/topics"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+// stamped Сообщение с уже проставленными id и временем, при рассылке оно не меняется.
+var stamped = domain.Message{
+	ID:         "id",
+	Body:       []byte("message"),
+	EnqueuedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+}
+
+type topicsTestSuite struct {
+	suite.Suite
+}
+
+// Все копии - одно и то же сообщение с одним id.
+func (s *topicsTestSuite) TestPublish_FanOut() {
+	ctx := context.Background()
+	topic := uuid.NewString()
+	var (
+		mu     sync.Mutex
+		copies = make(map[string]domain.Message)
+	)
+	producer := mocks.NewQueuesProducer(s.T())
+	producer.
+		EXPECT().
+		PutMessageToQueue(ctx, mock.Anything, mock.Anything, domain.PutOptions{}).
+		RunAndReturn(func(_ context.Context, queueName string, message domain.Message, _ domain.PutOptions) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			copies[queueName] = message
+			return message.ID, nil
+		}).
+		Twice()
+
+	topicsInstance := topics.NewTopics(producer)
+	s.Require().NoError(topicsInstance.Subscribe(topic, "second"))
+	s.Require().NoError(topicsInstance.Subscribe(topic, "first"))
+	s.Require().NoError(topicsInstance.Subscribe(topic, "first"))
+	s.Equal([]string{"first", "second"}, topicsInstance.Subscriptions(topic))
+
+	published, err := topicsInstance.Publish(ctx, topic, domain.Message{Body: []byte("message")}, domain.PutOptions{})
+	s.Require().NoError(err)
+	s.Equal(2, published.Delivered)
+	s.Require().Len(copies, 2)
+	s.NotEmpty(copies["first"].ID)
+	s.Equal(copies["first"], copies["second"])
+	s.Equal(copies["first"].ID, published.ID)
+}
+
+func (s *topicsTestSuite) TestPublish_NoSubscriptions() {
+	topicsInstance := topics.NewTopics(mocks.NewQueuesProducer(s.T()))
+	published, err := topicsInstance.Publish(context.Background(), uuid.NewString(), stamped, domain.PutOptions{})
+	s.Require().NoError(err)
+	s.Zero(published.Delivered)
+}
+
+func (s *topicsTestSuite) TestPublish_PartialFailure() {
+	ctx := context.Background()
+	topic := uuid.NewString()
+	producer := mocks.NewQueuesProducer(s.T())
+	producer.
+		EXPECT().
+		PutMessageToQueue(ctx, "full", stamped, domain.PutOptions{}).
+		Return("", domain.ErrQueueFull).
+		Once()
+	producer.
+		EXPECT().
+		PutMessageToQueue(ctx, "free", stamped, domain.PutOptions{}).
+		Return("id", nil).
+		Once()
+
+	topicsInstance := topics.NewTopics(producer)
+	s.Require().NoError(topicsInstance.Subscribe(topic, "full"))
+	s.Require().NoError(topicsInstance.Subscribe(topic, "free"))
+
+	published, err := topicsInstance.Publish(ctx, topic, stamped, domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueFull)
+	s.Contains(err.Error(), "queue full: queue is full")
+	s.Equal(domain.Published{ID: "id", Delivered: 1}, published)
+}
+
+// Ключ уходит в каждую очередь, на повтор отдаем id первой отправки.
+func (s *topicsTestSuite) TestPublish_IdempotencyKey() {
+	ctx := context.Background()
+	topic := uuid.NewString()
+	options := domain.PutOptions{IdempotencyKey: "key"}
+	producer := mocks.NewQueuesProducer(s.T())
+	producer.
+		EXPECT().
+		PutMessageToQueue(ctx, "first", stamped, options).
+		Return("first-put", nil).
+		Once()
+	producer.
+		EXPECT().
+		PutMessageToQueue(ctx, "second", stamped, options).
+		Return(stamped.ID, nil).
+		Once()
+
+	topicsInstance := topics.NewTopics(producer)
+	s.Require().NoError(topicsInstance.Subscribe(topic, "first"))
+	s.Require().NoError(topicsInstance.Subscribe(topic, "second"))
+
+	published, err := topicsInstance.Publish(ctx, topic, stamped, options)
+	s.Require().NoError(err)
+	s.Equal(domain.Published{ID: "first-put", Delivered: 2}, published)
+}
+
+func (s *topicsTestSuite) TestUnsubscribe() {
+	topic := uuid.NewString()
+	topicsInstance := topics.NewTopics(mocks.NewQueuesProducer(s.T()))
+	s.Require().NoError(topicsInstance.Subscribe(topic, "queue"))
+
+	s.Require().NoError(topicsInstance.Unsubscribe(topic, "queue"))
+	s.Empty(topicsInstance.Subscriptions(topic))
+	s.Require().ErrorIs(topicsInstance.Unsubscribe(topic, "queue"), domain.ErrSubscriptionNotFound)
+}
+
+func TestTopics(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(topicsTestSuite))
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry Метрики в текстовом формате Prometheus, без внешних зависимостей.
+// Метрики отдаются в порядке регистрации, серии внутри метрики - по значениям меток.
+type Registry struct {
+	metrics []metric
+	mu      *sync.Mutex
+}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{mu: &sync.Mutex{}}
+}
+
+func (registry *Registry) NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	counter := &CounterVec{desc: newDesc(name, help, "counter", labels), series: newSeries[*float64]()}
+	registry.register(counter)
+	return counter
+}
+
+// NewHistogramVec Границы корзин по возрастанию, +Inf добавляется сам.
+func (registry *Registry) NewHistogramVec(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	histogram := &HistogramVec{
+		desc:    newDesc(name, help, "histogram", labels),
+		buckets: buckets,
+		series:  newSeries[*histogramValue](),
+	}
+	registry.register(histogram)
+	return histogram
+}
+
+// NewGaugeFunc Значения собираются в момент отдачи, нужно для того, что и так считается в другом месте.
+func (registry *Registry) NewGaugeFunc(name string, help string, collect Collect, labels ...string) {
+	registry.register(&funcMetric{desc: newDesc(name, help, "gauge", labels), collect: collect})
+}
+
+// NewCounterFunc То же что NewGaugeFunc, только значение не убывает.
+func (registry *Registry) NewCounterFunc(name string, help string, collect Collect, labels ...string) {
+	registry.register(&funcMetric{desc: newDesc(name, help, "counter", labels), collect: collect})
+}
+
+func (registry *Registry) Write(w io.Writer) error {
+	registry.mu.Lock()
+	metrics := registry.metrics
+	registry.mu.Unlock()
+	buffered := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(buffered)
+	}
+	err := buffered.Flush()
+	if err != nil {
+		return fmt.Errorf("write metrics: %w", err)
+	}
+	return nil
+}
+
+func (registry *Registry) register(m metric) {
+	registry.mu.Lock()
+	registry.metrics = append(registry.metrics, m)
+	registry.mu.Unlock()
+}
+
+// Collect Отдает значения через emit, значения меток в порядке их объявления.
+type Collect func(emit func(value float64, labelValues ...string))
+
+type funcMetric struct {
+	desc
+	collect Collect
+}
+
+func (m *funcMetric) write(w *bufio.Writer) {
+	m.writeHeader(w)
+	m.collect(func(value float64, labelValues ...string) {
+		m.writeSample(w, "", labelValues, "", value)
+	})
+}
+
+type desc struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func newDesc(name string, help string, kind string, labels []string) desc {
+	return desc{name: name, help: help, kind: kind, labels: labels}
+}
+
+func (d desc) writeHeader(w *bufio.Writer) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, d.help, d.name, d.kind)
+}
+
+// writeSample Extra Уже готовая дополнительная метка, нужна для корзин гистограммы (le="0.5").
+func (d desc) writeSample(w *bufio.Writer, suffix string, labelValues []string, extra string, value float64) {
+	_, _ = w.WriteString(d.name + suffix)
+	pairs := make([]string, 0, len(d.labels)+1)
+	for i, label := range d.labels {
+		pairs = append(pairs, label+`="`+escape(labelValues[i])+`"`)
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) > 0 {
+		_, _ = w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	_, _ = w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,73 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kukwuka/queue/internal/infrastructure/metrics"
+)
+
+type registryTestSuite struct {
+	suite.Suite
+}
+
+func (s *registryTestSuite) TestWrite_TextFormat() {
+	registry := metrics.NewRegistry()
+	counter := registry.NewCounterVec("requests_total", "Requests.", "handler")
+	histogram := registry.NewHistogramVec("wait_seconds", "Wait.", []float64{0.1, 1}, "queue")
+	registry.NewGaugeFunc("depth", "Depth.", func(emit func(value float64, labelValues ...string)) {
+		emit(3, `a"b`)
+	}, "queue")
+
+	counter.Inc("get")
+	counter.Add(2, "get")
+	counter.Inc("put")
+	histogram.Observe(0.05, "q")
+	histogram.Observe(0.5, "q")
+	histogram.Observe(5, "q")
+
+	buffer := bytes.NewBuffer(nil)
+	s.Require().NoError(registry.Write(buffer))
+	s.Equal(`# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{handler="get"} 3
+requests_total{handler="put"} 1
+# HELP wait_seconds Wait.
+# TYPE wait_seconds histogram
+wait_seconds_bucket{queue="q",le="0.1"} 1
+wait_seconds_bucket{queue="q",le="1"} 2
+wait_seconds_bucket{queue="q",le="+Inf"} 3
+wait_seconds_sum{queue="q"} 5.55
+wait_seconds_count{queue="q"} 3
+# HELP depth Depth.
+# TYPE depth gauge
+depth{queue="a\"b"} 3
+`, buffer.String())
+}
+
+func (s *registryTestSuite) TestHistogramVec_Delete() {
+	registry := metrics.NewRegistry()
+	histogram := registry.NewHistogramVec("wait_seconds", "Wait.", []float64{1}, "queue")
+	for _, queueName := range []string{"a", "b", "c"} {
+		histogram.Observe(0.5, queueName)
+	}
+	histogram.Delete("a")
+	histogram.DeleteFunc(func(labelValues []string) bool { return labelValues[0] == "c" })
+
+	buffer := bytes.NewBuffer(nil)
+	s.Require().NoError(registry.Write(buffer))
+	s.NotContains(buffer.String(), `queue="a"`)
+	s.Contains(buffer.String(), `wait_seconds_count{queue="b"} 1`)
+	s.NotContains(buffer.String(), `queue="c"`)
+}
+
+func (s *registryTestSuite) TestExponentialBuckets() {
+	s.Equal([]float64{0.5, 1, 2, 4}, metrics.ExponentialBuckets(0.5, 2, 4))
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(registryTestSuite))
+}
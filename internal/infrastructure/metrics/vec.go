@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"bufio"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CounterVec Счетчики с метками, серия появляется при первом Add.
+type CounterVec struct {
+	desc
+	series *series[*float64]
+}
+
+func (counter *CounterVec) Inc(labelValues ...string) {
+	counter.Add(1, labelValues...)
+}
+
+func (counter *CounterVec) Add(value float64, labelValues ...string) {
+	counter.series.update(labelValues, func() *float64 { return new(float64) }, func(total *float64) {
+		*total += value
+	})
+}
+
+func (counter *CounterVec) write(w *bufio.Writer) {
+	counter.writeHeader(w)
+	counter.series.each(func(labelValues []string, total *float64) {
+		counter.writeSample(w, "", labelValues, "", *total)
+	})
+}
+
+// HistogramVec Гистограммы с метками, корзины накопительные, как того требует формат.
+type HistogramVec struct {
+	desc
+	buckets []float64
+	series  *series[*histogramValue]
+}
+
+type histogramValue struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (histogram *HistogramVec) Observe(value float64, labelValues ...string) {
+	newValue := func() *histogramValue {
+		return &histogramValue{counts: make([]uint64, len(histogram.buckets))}
+	}
+	histogram.series.update(labelValues, newValue, func(h *histogramValue) {
+		for i, bound := range histogram.buckets {
+			if value <= bound {
+				h.counts[i]++
+			}
+		}
+		h.sum += value
+		h.count++
+	})
+}
+
+// Delete Убирает серию, например когда объект, которому она принадлежит, удален.
+func (histogram *HistogramVec) Delete(labelValues ...string) {
+	histogram.series.deleteFunc(func(seriesLabelValues []string) bool {
+		return slices.Equal(seriesLabelValues, labelValues)
+	})
+}
+
+// DeleteFunc Убирает серии, для меток которых remove вернул true.
+func (histogram *HistogramVec) DeleteFunc(remove func(labelValues []string) bool) {
+	histogram.series.deleteFunc(remove)
+}
+
+func (histogram *HistogramVec) write(w *bufio.Writer) {
+	histogram.writeHeader(w)
+	histogram.series.each(func(labelValues []string, h *histogramValue) {
+		for i, bound := range histogram.buckets {
+			histogram.writeSample(w, "_bucket", labelValues, `le="`+formatFloat(bound)+`"`, float64(h.counts[i]))
+		}
+		histogram.writeSample(w, "_bucket", labelValues, `le="+Inf"`, float64(h.count))
+		histogram.writeSample(w, "_sum", labelValues, "", h.sum)
+		histogram.writeSample(w, "_count", labelValues, "", float64(h.count))
+	})
+}
+
+// ExponentialBuckets Count границ, начиная со start, каждая следующая в factor раз больше.
+func ExponentialBuckets(start float64, factor float64, count int) []float64 {
+	buckets := make([]float64, 0, count)
+	for i := range count {
+		buckets = append(buckets, start*math.Pow(factor, float64(i)))
+	}
+	return buckets
+}
+
+// series Значения по набору меток, ключ - значения меток через разделитель, который не встречается в тексте.
+type series[V any] struct {
+	values map[string]V
+	labels map[string][]string
+	mu     *sync.Mutex
+}
+
+func newSeries[V any]() *series[V] {
+	return &series[V]{
+		values: make(map[string]V),
+		labels: make(map[string][]string),
+		mu:     &sync.Mutex{},
+	}
+}
+
+func (s *series[V]) update(labelValues []string, newValue func() V, apply func(V)) {
+	key := strings.Join(labelValues, "\xff")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, exist := s.values[key]
+	if !exist {
+		value = newValue()
+		s.values[key] = value
+		s.labels[key] = slices.Clone(labelValues)
+	}
+	apply(value)
+}
+
+func (s *series[V]) deleteFunc(remove func(labelValues []string) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, labelValues := range s.labels {
+		if remove(labelValues) {
+			delete(s.values, key)
+			delete(s.labels, key)
+		}
+	}
+}
+
+// each Обходит серии по порядку ключей, чтобы вывод не прыгал от запроса к запросу.
+func (s *series[V]) each(visit func(labelValues []string, value V)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		visit(s.labels[key], s.values[key])
+	}
+}
@@ -0,0 +1,555 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SyncPolicy string
+
+const (
+	SyncAlways   SyncPolicy = "always"
+	SyncInterval SyncPolicy = "interval"
+	SyncNever    SyncPolicy = "never"
+)
+
+var (
+	ErrUnknownSyncPolicy = errors.New("unknown sync policy")
+	ErrCorruptedSegment  = errors.New("corrupted segment")
+	// ErrInvalidSyncInterval У политики interval период должен быть положительным.
+	ErrInvalidSyncInterval = errors.New("sync interval must be positive")
+)
+
+func ParseSyncPolicy(value string) (SyncPolicy, error) {
+	policy := SyncPolicy(value)
+	switch policy {
+	case SyncAlways, SyncInterval, SyncNever:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownSyncPolicy, value)
+	}
+}
+
+type Config struct {
+	Dir          string
+	SyncPolicy   SyncPolicy
+	SyncInterval time.Duration
+	// SegmentSize После превышения размера начинаем новый сегмент, 0 - без ротации.
+	SegmentSize int64
+}
+
+// validate Нулевой период time.NewTicker не принимает и падает с паникой.
+func (config Config) validate() error {
+	if config.SyncPolicy == SyncInterval && config.SyncInterval <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidSyncInterval, config.SyncInterval)
+	}
+	return nil
+}
+
+const (
+	segmentExt = ".wal"
+	// Заголовок записи: длина и crc32 полезной нагрузки.
+	headerSize = 8
+	// Защита от мусорной длины в недописанном заголовке.
+	maxRecordSize = 64 << 20
+)
+
+type op string
+
+const (
+	opPut op = "put"
+	// Сообщение больше не в очереди (забрали или подтвердили).
+	opAck op = "ack"
+	// Настройки очереди, созданной через CreateQueue.
+	opConfig op = "config"
+	// Очередь удалили через DeleteQueue, ее настройки больше не нужны.
+	opDelete op = "delete"
+)
+
+type record struct {
+	Seq   uint64 `json:"seq"`
+	Op    op     `json:"op"`
+	Queue string `json:"queue"`
+	// ID У ack - номер подтверждаемого сообщения, у put - номер исходной записи, если ее перенесли при сжатии.
+	ID      uint64 `json:"id,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Payload Тело, которое не текст, в base64. Текст пишем в Message, чтобы журнал оставался читаемым.
+	Payload []byte `json:"payload,omitempty"`
+	// MessageID, Headers, ContentType и EnqueuedAt Метаданные сообщения, в старых журналах их нет.
+	MessageID   string            `json:"messageId,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Raw         bool              `json:"raw,omitempty"`
+	EnqueuedAt  int64             `json:"enqueuedAt,omitempty"`
+	// DeliverAt Время доставки отложенного сообщения в наносекундах unix, 0 - сразу.
+	DeliverAt int64 `json:"deliverAt,omitempty"`
+	// ExpiresAt Срок жизни сообщения в наносекундах unix, 0 - вечно.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+	Priority  int   `json:"priority,omitempty"`
+	// Config Только у записи config.
+	Config *queueConfig `json:"config,omitempty"`
+}
+
+// queueConfig Настройки очереди в журнале, длительности в наносекундах.
+type queueConfig struct {
+	Type            string `json:"type,omitempty"`
+	MaxLen          int    `json:"maxLen"`
+	Overflow        string `json:"overflow,omitempty"`
+	MessageTTL      int64  `json:"messageTTL,omitempty"`
+	MaxReceives     int    `json:"maxReceives,omitempty"`
+	MaxMessageBytes int    `json:"maxMessageBytes,omitempty"`
+	DedupWindow     int64  `json:"dedupWindow,omitempty"`
+}
+
+// Сегмент журнала, файл называется номером первой записи в нем.
+// Live Сколько сообщений из этого сегмента еще не удалено, при нуле сегмент можно удалять.
+// Puts Сколько всего сообщений в него записали, по доле живых решаем, сжимать ли его.
+type segment struct {
+	start uint64
+	live  int
+	puts  int
+}
+
+// sparse Живые сообщения есть, но их не больше половины записанных.
+func (s *segment) sparse() bool {
+	return s.live > 0 && s.live*2 <= s.puts
+}
+
+// Журнал только дописывается, номер записи put служит идентификатором сообщения.
+type journal struct {
+	config   Config
+	active   *os.File
+	size     int64
+	nextSeq  uint64
+	segments []*segment
+	// Owners В каком сегменте лежит сообщение.
+	owners map[uint64]*segment
+	// Configs Последние записи config по очередям. Пишутся заново в начало каждого сегмента,
+	// поэтому переживают удаление старых сегментов.
+	configs map[string]record
+	stop    chan struct{}
+	mu      *sync.Mutex
+}
+
+func openJournal(config Config) (*journal, *replayState, error) {
+	err := os.MkdirAll(config.Dir, 0o750)
+	if err != nil {
+		return nil, nil, fmt.Errorf("make data dir: %w", err)
+	}
+	starts, err := listSegments(config.Dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	j := &journal{
+		config:  config,
+		nextSeq: 1,
+		owners:  make(map[uint64]*segment),
+		configs: make(map[string]record),
+		stop:    make(chan struct{}),
+		mu:      &sync.Mutex{},
+	}
+	state := newReplayState()
+	for i, start := range starts {
+		err = j.replaySegment(start, i == len(starts)-1, state)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	err = j.openActive()
+	if err != nil {
+		return nil, nil, err
+	}
+	j.removeDrained()
+	if config.SyncPolicy == SyncInterval {
+		go j.syncEvery(config.SyncInterval)
+	}
+	return j, state, nil
+}
+
+// append Пишет записи подряд и синхронизирует один раз, возвращает номер первой записи.
+// Номера остальных идут следом без пропусков.
+func (j *journal) append(records ...record) (uint64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	err := j.rotateIfFull()
+	if err != nil {
+		return 0, err
+	}
+	first := j.nextSeq
+	for _, rec := range records {
+		err = j.write(rec)
+		if err != nil {
+			return 0, err
+		}
+	}
+	j.removeDrained()
+	if j.config.SyncPolicy == SyncAlways {
+		return first, j.sync()
+	}
+	return first, nil
+}
+
+// rotateIfFull Ротация пишет в новый сегмент настройки и перенесенные сообщения, поэтому только до пачки,
+// иначе номера записей пачки шли бы с пропусками.
+func (j *journal) rotateIfFull() error {
+	if j.config.SegmentSize > 0 && j.size >= j.config.SegmentSize {
+		return j.rotate()
+	}
+	return nil
+}
+
+func (j *journal) write(rec record) error {
+	rec.Seq = j.nextSeq
+	payload, err := encode(rec)
+	if err != nil {
+		return err
+	}
+	written, err := j.active.Write(payload)
+	j.size += int64(written)
+	if err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	j.nextSeq++
+	j.apply(rec, j.segments[len(j.segments)-1], nil)
+	return nil
+}
+
+func (j *journal) close() error {
+	close(j.stop)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	err := j.sync()
+	if err != nil {
+		return err
+	}
+	err = j.active.Close()
+	if err != nil {
+		return fmt.Errorf("close segment: %w", err)
+	}
+	return nil
+}
+
+// apply Учет записи в сегментах, state нужен только при воспроизведении.
+func (j *journal) apply(rec record, owner *segment, state *replayState) {
+	switch rec.Op {
+	case opPut:
+		j.own(rec.messageSeq(), owner)
+		state.put(rec)
+	case opAck:
+		j.ack(rec, state)
+	case opConfig:
+		j.configs[rec.Queue] = rec
+	case opDelete:
+		delete(j.configs, rec.Queue)
+	}
+}
+
+// own Перенесенное при сжатии сообщение переходит из старого сегмента в новый.
+func (j *journal) own(id uint64, owner *segment) {
+	if previous, exist := j.owners[id]; exist {
+		previous.live--
+	}
+	owner.live++
+	owner.puts++
+	j.owners[id] = owner
+}
+
+func (j *journal) ack(rec record, state *replayState) {
+	putOwner, exist := j.owners[rec.ID]
+	if !exist {
+		return
+	}
+	putOwner.live--
+	delete(j.owners, rec.ID)
+	state.ack(rec)
+}
+
+func (j *journal) replaySegment(start uint64, last bool, state *replayState) error {
+	file, err := os.OpenFile(j.segmentPath(start), os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("open segment: %w", err)
+	}
+	defer file.Close()
+	owner := &segment{start: start}
+	j.segments = append(j.segments, owner)
+	j.nextSeq = max(j.nextSeq, start)
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		rec, size, err := decode(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// Хвост последнего сегмента мог не дописаться при падении, его просто отрезаем.
+			if last {
+				return truncate(file, offset)
+			}
+			return fmt.Errorf("segment %d: %w", start, err)
+		}
+		offset += size
+		j.nextSeq = rec.Seq + 1
+		j.apply(rec, owner, state)
+	}
+}
+
+func (j *journal) openActive() error {
+	if len(j.segments) == 0 {
+		j.segments = append(j.segments, &segment{start: j.nextSeq})
+	}
+	start := j.segments[len(j.segments)-1].start
+	file, err := os.OpenFile(j.segmentPath(start), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("open active segment: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat active segment: %w", err)
+	}
+	j.active, j.size = file, info.Size()
+	return nil
+}
+
+func (j *journal) rotate() error {
+	if j.config.SyncPolicy != SyncNever {
+		err := j.sync()
+		if err != nil {
+			return err
+		}
+	}
+	err := j.active.Close()
+	if err != nil {
+		return fmt.Errorf("close segment: %w", err)
+	}
+	j.segments = append(j.segments, &segment{start: j.nextSeq})
+	err = j.openActive()
+	if err != nil {
+		return err
+	}
+	err = j.writeConfigs()
+	if err != nil {
+		return err
+	}
+	return j.compactHead()
+}
+
+// compactHead Одно долгоживущее сообщение, например в очереди недоставленных, держало бы первый сегмент,
+// а с ним и все следующие: удаляем только с начала журнала. Поэтому если живых в первом сегменте не больше
+// половины, переписываем их и его настройки в активный сегмент, после чего первый удалит removeDrained.
+func (j *journal) compactHead() error {
+	head := j.segments[0]
+	if !head.sparse() {
+		return nil
+	}
+	records, err := j.readSegment(head.start)
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		err = j.move(rec, head)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// move Переписывает в активный сегмент запись из from, если она еще нужна. Сообщение сохраняет свой номер,
+// иначе его нельзя было бы подтвердить.
+func (j *journal) move(rec record, from *segment) error {
+	if !j.isCurrent(rec, from) {
+		return nil
+	}
+	if rec.Op == opPut {
+		rec.ID = rec.messageSeq()
+	}
+	return j.write(rec)
+}
+
+// isCurrent Запись еще нужна: сообщение живое и лежит в owner, или это последние настройки очереди.
+func (j *journal) isCurrent(rec record, owner *segment) bool {
+	switch rec.Op {
+	case opPut:
+		return j.owners[rec.messageSeq()] == owner
+	case opConfig:
+		return j.configs[rec.Queue].Seq == rec.Seq
+	default:
+		return false
+	}
+}
+
+func (j *journal) readSegment(start uint64) ([]record, error) {
+	file, err := os.Open(j.segmentPath(start))
+	if err != nil {
+		return nil, fmt.Errorf("open segment: %w", err)
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var records []record
+	for {
+		rec, _, err := decode(reader)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("segment %d: %w", start, err)
+		}
+		records = append(records, rec)
+	}
+}
+
+// writeConfigs Настройки очередей в начале сегмента, чтобы они не пропали, когда удалят сегменты до него.
+func (j *journal) writeConfigs() error {
+	queueNames := make([]string, 0, len(j.configs))
+	for queueName := range j.configs {
+		queueNames = append(queueNames, queueName)
+	}
+	slices.Sort(queueNames)
+	for _, queueName := range queueNames {
+		err := j.write(j.configs[queueName])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configOf Записанные настройки очереди, exist = false - если их нет.
+func (j *journal) configOf(queueName string) (queueConfig, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	rec, exist := j.configs[queueName]
+	if !exist {
+		return queueConfig{}, false
+	}
+	return *rec.Config, true
+}
+
+// queueConfigs Настройки всех очередей, которые создали и не удалили.
+func (j *journal) queueConfigs() map[string]queueConfig {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	configs := make(map[string]queueConfig, len(j.configs))
+	for queueName, rec := range j.configs {
+		configs[queueName] = *rec.Config
+	}
+	return configs
+}
+
+// removeDrained Удаляет сегменты, все сообщения которых уже удалены, активный не трогаем.
+// Удаляем только с начала журнала: в сегменте могут быть подтверждения сообщений из более старого живого сегмента,
+// без них после рестарта эти сообщения вернулись бы.
+func (j *journal) removeDrained() {
+	drained := 0
+	for drained < len(j.segments)-1 && j.segments[drained].live == 0 {
+		// Если удалить не получилось - ничего страшного, при воспроизведении он просто будет пустой.
+		_ = os.Remove(j.segmentPath(j.segments[drained].start))
+		drained++
+	}
+	j.segments = j.segments[drained:]
+}
+
+func (j *journal) syncEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			j.mu.Lock()
+			_ = j.sync()
+			j.mu.Unlock()
+		case <-j.stop:
+			return
+		}
+	}
+}
+
+func (j *journal) sync() error {
+	err := j.active.Sync()
+	if err != nil {
+		return fmt.Errorf("sync segment: %w", err)
+	}
+	return nil
+}
+
+func (j *journal) segmentPath(start uint64) string {
+	return filepath.Join(j.config.Dir, fmt.Sprintf("%020d%s", start, segmentExt))
+}
+
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read data dir: %w", err)
+	}
+	starts := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		name, isSegment := strings.CutSuffix(entry.Name(), segmentExt)
+		if !isSegment {
+			continue
+		}
+		start, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse segment name %s: %w", entry.Name(), err)
+		}
+		starts = append(starts, start)
+	}
+	slices.Sort(starts)
+	return starts, nil
+}
+
+func encode(rec record) ([]byte, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("marshal record: %w", err)
+	}
+	buffer := make([]byte, headerSize, headerSize+len(payload))
+	binary.LittleEndian.PutUint32(buffer[:4], uint32(len(payload))) //nolint:gosec
+	binary.LittleEndian.PutUint32(buffer[4:], crc32.ChecksumIEEE(payload))
+	return append(buffer, payload...), nil
+}
+
+func decode(reader io.Reader) (record, int64, error) {
+	var rec record
+	header := make([]byte, headerSize)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return rec, 0, ErrCorruptedSegment
+		}
+		return rec, 0, err //nolint:wrapcheck
+	}
+	length := binary.LittleEndian.Uint32(header[:4])
+	if length > maxRecordSize {
+		return rec, 0, ErrCorruptedSegment
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	if err != nil || crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:]) {
+		return rec, 0, ErrCorruptedSegment
+	}
+	err = json.Unmarshal(payload, &rec)
+	if err != nil {
+		return rec, 0, fmt.Errorf("%w: %w", ErrCorruptedSegment, err)
+	}
+	return rec, int64(headerSize + len(payload)), nil
+}
+
+func truncate(file *os.File, offset int64) error {
+	err := file.Truncate(offset)
+	if err != nil {
+		return fmt.Errorf("truncate corrupted tail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package wal
+
+import (
+	"slices"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Состояние очередей, собранное при воспроизведении журнала.
+type replayState struct {
+	order   map[string][]uint64
+	entries map[uint64]entry
+}
+
+func newReplayState() *replayState {
+	return &replayState{
+		order:   make(map[string][]uint64),
+		entries: make(map[uint64]entry),
+	}
+}
+
+// При дописывании в журнал состояние не ведем, поэтому методы работают и с nil.
+func (state *replayState) put(rec record) {
+	if state == nil {
+		return
+	}
+	id := rec.messageSeq()
+	// Перенесенное при сжатии сообщение может встретиться дважды, если старый сегмент не удалился.
+	if _, exist := state.entries[id]; !exist {
+		state.order[rec.Queue] = append(state.order[rec.Queue], id)
+	}
+	state.entries[id] = entry{
+		id: id,
+		message: domain.Message{
+			ID:          rec.MessageID,
+			Body:        rec.body(),
+			Headers:     rec.Headers,
+			ContentType: rec.ContentType,
+			Raw:         rec.Raw,
+			EnqueuedAt:  fromUnixNano(rec.EnqueuedAt),
+		},
+		options: domain.PutOptions{
+			DeliverAt: fromUnixNano(rec.DeliverAt),
+			ExpiresAt: fromUnixNano(rec.ExpiresAt),
+			Priority:  rec.Priority,
+		},
+	}
+}
+
+func (state *replayState) ack(rec record) {
+	if state == nil {
+		return
+	}
+	delete(state.entries, rec.ID)
+}
+
+// queues Оставшиеся сообщения по очередям в порядке записи, очереди без сообщений не возвращаем.
+// Перенесенные при сжатии сообщения лежат дальше в журнале, поэтому порядок восстанавливаем по номерам.
+func (state *replayState) queues() map[string][]entry {
+	result := make(map[string][]entry, len(state.order))
+	for queueName, ids := range state.order {
+		slices.Sort(ids)
+		entries := make([]entry, 0, len(ids))
+		for _, id := range ids {
+			if e, exist := state.entries[id]; exist {
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) > 0 {
+			result[queueName] = slices.Clip(entries)
+		}
+	}
+	return result
+}
+
+// messageSeq Номер сообщения: номер записи put, а у перенесенной при сжатии - номер исходной записи.
+func (rec record) messageSeq() uint64 {
+	if rec.ID != 0 {
+		return rec.ID
+	}
+	return rec.Seq
+}
+
+// body В старых журналах и для текста тело лежит в Message.
+func (rec record) body() []byte {
+	if rec.Payload != nil {
+		return rec.Payload
+	}
+	return []byte(rec.Message)
+}
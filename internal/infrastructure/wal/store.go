@@ -0,0 +1,330 @@
+// nolint:ireturn
+package wal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/domain/queue"
+)
+
+// Store Хранилище очередей поверх журнала, Factory реализует domain.QueueFactory, а сам Store -
+// domain.QueueConfigStore.
+// Сообщения живут в обычной очереди в памяти, журнал нужен только чтобы восстановить их после рестарта.
+type Store struct {
+	journal *journal
+	// Restored Восстановленные из журнала сообщения, забираются при создании очереди.
+	restored map[string][]entry
+	mu       *sync.Mutex
+}
+
+type entry struct {
+	id      uint64
+	message domain.Message
+	// Options Время доставки и срок жизни, нужны чтобы восстановить сообщение как было.
+	options domain.PutOptions
+}
+
+func NewStore(config Config) (*Store, error) {
+	err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+	j, state, err := openJournal(config)
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	return &Store{
+		journal:  j,
+		restored: state.queues(),
+		mu:       &sync.Mutex{},
+	}, nil
+}
+
+// Names Очереди, в которых после рестарта остались сообщения.
+func (store *Store) Names() []string {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	names := make([]string, 0, len(store.restored))
+	for name := range store.restored {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Configs Настройки очередей, созданных через CreateQueue и не удаленных, см. Queues.RestoreConfigs.
+func (store *Store) Configs() map[string]domain.QueueConfig {
+	records := store.journal.queueConfigs()
+	configs := make(map[string]domain.QueueConfig, len(records))
+	for queueName, rec := range records {
+		configs[queueName] = rec.domainConfig()
+	}
+	return configs
+}
+
+// SaveQueueConfig Те же настройки повторно не пишем: после рестарта RestoreConfigs создает очереди так же,
+// как CreateQueue.
+func (store *Store) SaveQueueConfig(queueName string, config domain.QueueConfig) error {
+	rec := configRecord(queueName, config)
+	saved, exist := store.journal.configOf(queueName)
+	if exist && saved == *rec.Config {
+		return nil
+	}
+	_, err := store.journal.append(rec)
+	if err != nil {
+		return fmt.Errorf("write config to journal: %w", err)
+	}
+	return nil
+}
+
+func (store *Store) DeleteQueueConfig(queueName string) error {
+	_, err := store.journal.append(record{Op: opDelete, Queue: queueName})
+	if err != nil {
+		return fmt.Errorf("write delete to journal: %w", err)
+	}
+	return nil
+}
+
+// Factory Лог в журнал не пишем, поэтому с журналом логи не создаются: см. Queues.RejectQueueTypes.
+func (store *Store) Factory(name string, config domain.QueueConfig, deadLetter domain.DeadLetter) domain.Queue {
+	if config.Type == domain.QueueTypeLog {
+		return queue.NewLog[domain.Message](config)
+	}
+	store.mu.Lock()
+	restored := store.restored[name]
+	delete(store.restored, name)
+	store.mu.Unlock()
+
+	durable := &durableQueue{
+		name:       name,
+		inner:      queue.NewQueue[entry](config),
+		journal:    store.journal,
+		messageTTL: config.MessageTTL,
+	}
+	// Выкинутое по переполнению и недоставленное тоже удаляем из журнала, ошибку вернуть некому,
+	// в худшем случае сообщение вернется после рестарта.
+	durable.inner.OnDrop(func(e entry) { _ = durable.remove(e) })
+	durable.inner.OnDeliver(func(e entry, count int) entry {
+		e.message.DeliveryCount = count
+		return e
+	})
+	durable.inner.OnUndelivered(func(e entry) {
+		_ = durable.remove(e)
+		if deadLetter != nil {
+			deadLetter(e.message)
+		}
+	})
+	for _, e := range restored {
+		durable.inner.Restore(e.options, e)
+	}
+	return durable
+}
+
+// Close Закрывать после того как закрыты все очереди.
+func (store *Store) Close() error {
+	return store.journal.close()
+}
+
+type durableQueue struct {
+	name       string
+	inner      *queue.Queue[entry]
+	journal    *journal
+	messageTTL time.Duration
+}
+
+func (q *durableQueue) GetMessage(ctx context.Context) (domain.Message, error) {
+	e, err := q.inner.GetMessage(ctx)
+	if err != nil {
+		return domain.Message{}, err //nolint:wrapcheck
+	}
+	err = q.remove(e)
+	if err != nil {
+		return domain.Message{}, err
+	}
+	return e.message, nil
+}
+
+func (q *durableQueue) GetMessages(ctx context.Context, limit int) ([]domain.Message, error) {
+	entries, err := q.inner.GetMessages(ctx, limit)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	err = q.remove(entries...)
+	if err != nil {
+		return nil, err
+	}
+	return messagesOf(entries), nil
+}
+
+func (q *durableQueue) PutMessage(ctx context.Context, message domain.Message, options domain.PutOptions) error {
+	return q.PutMessages(ctx, []domain.Message{message}, options)
+}
+
+// PutMessages Сначала пишем в журнал, потом в память.
+// Срок жизни по умолчанию считаем сами, чтобы в журнал попал тот же срок, что и в очередь.
+func (q *durableQueue) PutMessages(ctx context.Context, messages []domain.Message, options domain.PutOptions) error {
+	options = options.WithDefaultTTL(q.messageTTL, time.Now())
+	records := make([]record, 0, len(messages))
+	for _, message := range messages {
+		records = append(records, putRecord(q.name, message, options))
+	}
+	first, err := q.journal.append(records...)
+	if err != nil {
+		return fmt.Errorf("write put to journal: %w", err)
+	}
+	entries := make([]entry, 0, len(messages))
+	for i, message := range messages {
+		entries = append(entries, entry{
+			id:      first + uint64(i), //nolint:gosec
+			message: message,
+			options: options,
+		})
+	}
+	err = q.inner.PutMessages(ctx, entries, options)
+	if err != nil {
+		// В очередь не попало, значит и восстанавливать после рестарта не надо.
+		return errors.Join(err, q.remove(entries...))
+	}
+	return nil
+}
+
+func (q *durableQueue) LeaseMessage(
+	ctx context.Context,
+	visibilityTimeout time.Duration,
+) (domain.Lease[domain.Message], error) {
+	lease, err := q.inner.LeaseMessage(ctx, visibilityTimeout)
+	if err != nil {
+		return domain.Lease[domain.Message]{}, err //nolint:wrapcheck
+	}
+	return domain.Lease[domain.Message]{
+		Message:  lease.Message.message,
+		Receipt:  lease.Receipt,
+		Deadline: lease.Deadline,
+	}, nil
+}
+
+// Ack Сообщения в аренде остаются в журнале, удаляем только после подтверждения.
+func (q *durableQueue) Ack(receipt string) error {
+	e, err := q.inner.AckMessage(receipt)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	return q.remove(e)
+}
+
+func (q *durableQueue) Nack(receipt string) error {
+	return q.inner.Nack(receipt) //nolint:wrapcheck
+}
+
+func (q *durableQueue) Stats() domain.QueueStats {
+	return q.inner.Stats()
+}
+
+func (q *durableQueue) Peek(limit int) []domain.Message {
+	return messagesOf(q.inner.Peek(limit))
+}
+
+// Purge Удаленные сообщения попадают в журнал через OnDrop.
+func (q *durableQueue) Purge() int {
+	return q.inner.Purge()
+}
+
+func (q *durableQueue) Drain() {
+	q.inner.Drain()
+}
+
+func (q *durableQueue) Close() {
+	q.inner.Close()
+}
+
+func (q *durableQueue) remove(entries ...entry) error {
+	records := make([]record, 0, len(entries))
+	for _, e := range entries {
+		records = append(records, record{Op: opAck, Queue: q.name, ID: e.id})
+	}
+	_, err := q.journal.append(records...)
+	if err != nil {
+		return fmt.Errorf("write ack to journal: %w", err)
+	}
+	return nil
+}
+
+func messagesOf(entries []entry) []domain.Message {
+	messages := make([]domain.Message, 0, len(entries))
+	for _, e := range entries {
+		messages = append(messages, e.message)
+	}
+	return messages
+}
+
+// putRecord Сообщение целиком, с метаданными, чтобы после рестарта оно было тем же самым.
+func putRecord(queueName string, message domain.Message, options domain.PutOptions) record {
+	rec := record{
+		Op:          opPut,
+		Queue:       queueName,
+		MessageID:   message.ID,
+		Headers:     message.Headers,
+		ContentType: message.ContentType,
+		Raw:         message.Raw,
+		EnqueuedAt:  toUnixNano(message.EnqueuedAt),
+		DeliverAt:   toUnixNano(options.DeliverAt),
+		ExpiresAt:   toUnixNano(options.ExpiresAt),
+		Priority:    options.Priority,
+	}
+	if utf8.Valid(message.Body) {
+		rec.Message = string(message.Body)
+	} else {
+		rec.Payload = message.Body
+	}
+	return rec
+}
+
+func configRecord(queueName string, config domain.QueueConfig) record {
+	return record{
+		Op:    opConfig,
+		Queue: queueName,
+		Config: &queueConfig{
+			Type:            string(config.Type),
+			MaxLen:          config.MaxLen,
+			Overflow:        string(config.Overflow),
+			MessageTTL:      int64(config.MessageTTL),
+			MaxReceives:     config.MaxReceives,
+			MaxMessageBytes: config.MaxMessageBytes,
+			DedupWindow:     int64(config.DedupWindow),
+		},
+	}
+}
+
+func (config queueConfig) domainConfig() domain.QueueConfig {
+	return domain.QueueConfig{
+		Type:            domain.QueueType(config.Type),
+		MaxLen:          config.MaxLen,
+		Overflow:        domain.OverflowPolicy(config.Overflow),
+		MessageTTL:      time.Duration(config.MessageTTL),
+		MaxReceives:     config.MaxReceives,
+		MaxMessageBytes: config.MaxMessageBytes,
+		DedupWindow:     time.Duration(config.DedupWindow),
+	}
+}
+
+// toUnixNano Нулевое время храним как 0, чтобы не раздувать запись.
+func toUnixNano(at time.Time) int64 {
+	if at.IsZero() {
+		return 0
+	}
+	return at.UnixNano()
+}
+
+func fromUnixNano(nano int64) time.Time {
+	if nano == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nano)
+}
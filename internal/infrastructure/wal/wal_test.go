@@ -0,0 +1,447 @@
+package wal_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/infrastructure/wal"
+)
+
+const maxLen = 10
+
+type walTestSuite struct {
+	suite.Suite
+}
+
+func (s *walTestSuite) TestReplay_RestoresQueuesInOrder() {
+	config := s.config(wal.SyncInterval, 0)
+	store := s.newStore(config)
+	first, second := s.factory(store, "first"), s.factory(store, "second")
+	for _, message := range messagesOf("a", "b", "c") {
+		s.Require().NoError(first.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	s.Require().NoError(second.PutMessage(context.Background(), messageOf("x"), domain.PutOptions{}))
+	s.getEqual(first, "a")
+	s.closeStore(store, first, second)
+
+	store = s.newStore(config)
+	s.Equal([]string{"first", "second"}, store.Names())
+	first, second = s.factory(store, "first"), s.factory(store, "second")
+	s.getEqual(first, "b")
+	s.getEqual(first, "c")
+	s.getEqual(second, "x")
+	s.closeStore(store, first, second)
+}
+
+func (s *walTestSuite) TestReplay_UnackedLeaseRestored() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "leases")
+	for _, message := range messagesOf("acked", "leased") {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	acked, err := queueInstance.LeaseMessage(context.Background(), time.Minute)
+	s.Require().NoError(err)
+	s.Require().NoError(queueInstance.Ack(acked.Receipt))
+	_, err = queueInstance.LeaseMessage(context.Background(), time.Minute)
+	s.Require().NoError(err)
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "leases")
+	s.getEqual(queueInstance, "leased")
+	s.closeStore(store, queueInstance)
+}
+
+// Метаданные переживают рестарт, а номер выдачи в журнал не пишется и после рестарта считается заново.
+func (s *walTestSuite) TestReplay_KeepsMetadata() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "metadata")
+	message := domain.Message{
+		ID:          "id",
+		Body:        []byte("message"),
+		Headers:     map[string]string{"trace": "1"},
+		ContentType: "text/plain",
+		Raw:         true,
+		EnqueuedAt:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	lease, err := queueInstance.LeaseMessage(context.Background(), time.Minute)
+	s.Require().NoError(err)
+	s.Equal(1, lease.Message.DeliveryCount)
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "metadata")
+	restored, err := queueInstance.GetMessage(context.Background())
+	s.Require().NoError(err)
+	s.True(message.EnqueuedAt.Equal(restored.EnqueuedAt))
+	restored.EnqueuedAt = message.EnqueuedAt
+	s.Equal(message.WithDeliveryCount(1), restored)
+	s.closeStore(store, queueInstance)
+}
+
+// Тело не UTF-8 пишется в журнал отдельно от текста и восстанавливается байт в байт.
+func (s *walTestSuite) TestReplay_BinaryBody() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "binary")
+	messages := []domain.Message{{Body: []byte{0x0a, 0xff, 0x00}}, {Body: []byte("text")}}
+	s.Require().NoError(queueInstance.PutMessages(context.Background(), messages, domain.PutOptions{}))
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "binary")
+	restored, err := queueInstance.GetMessages(context.Background(), maxLen)
+	s.Require().NoError(err)
+	s.Require().Len(restored, 2)
+	s.Equal([]byte{0x0a, 0xff, 0x00}, restored[0].Body)
+	s.Equal([]byte("text"), restored[1].Body)
+	s.closeStore(store, queueInstance)
+}
+
+func (s *walTestSuite) TestReplay_Batch() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "batch")
+	messages := messagesOf("a", "b", "c", "d")
+	s.Require().NoError(queueInstance.PutMessages(context.Background(), messages, domain.PutOptions{}))
+	messages, err := queueInstance.GetMessages(context.Background(), 2)
+	s.Require().NoError(err)
+	s.Equal([]string{"a", "b"}, bodiesOf(messages))
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "batch")
+	messages, err = queueInstance.GetMessages(context.Background(), maxLen)
+	s.Require().NoError(err)
+	s.Equal([]string{"c", "d"}, bodiesOf(messages))
+	s.closeStore(store, queueInstance)
+}
+
+func (s *walTestSuite) TestReplay_DelayedKeepsDeliveryTime() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "delayed")
+	delayed := domain.PutOptions{DeliverAt: time.Now().Add(300 * time.Millisecond)}
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageOf("later"), delayed))
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "delayed")
+	s.Equal(domain.QueueStats{Delayed: 1}, queueInstance.Stats())
+	s.getEqual(queueInstance, "later")
+	s.closeStore(store, queueInstance)
+}
+
+func (s *walTestSuite) TestReplay_PriorityKept() {
+	config := s.config(wal.SyncAlways, 0)
+	queueConfig := domain.QueueConfig{Type: domain.QueueTypePriority, MaxLen: maxLen, Overflow: domain.OverflowBlock}
+	store := s.newStore(config)
+	queueInstance := store.Factory("priority", queueConfig, nil)
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageOf("low"), domain.PutOptions{}))
+	high := messageOf("high")
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), high, domain.PutOptions{Priority: 1}))
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = store.Factory("priority", queueConfig, nil)
+	s.getEqual(queueInstance, "high")
+	s.getEqual(queueInstance, "low")
+	s.closeStore(store, queueInstance)
+}
+
+// Настройки явно созданной очереди переживают рестарт, удаленной - нет.
+func (s *walTestSuite) TestReplay_ConfigsRestored() {
+	config := s.config(wal.SyncAlways, 0)
+	queueConfig := domain.QueueConfig{
+		Type:        domain.QueueTypePriority,
+		MaxLen:      maxLen,
+		Overflow:    domain.OverflowReject,
+		MessageTTL:  time.Minute,
+		MaxReceives: 3,
+	}
+	store := s.newStore(config)
+	s.Require().NoError(store.SaveQueueConfig("jobs", queueConfig))
+	s.Require().NoError(store.SaveQueueConfig("deleted", queueConfig))
+	s.Require().NoError(store.DeleteQueueConfig("deleted"))
+	s.Require().NoError(store.Close())
+
+	store = s.newStore(config)
+	s.Equal(map[string]domain.QueueConfig{"jobs": queueConfig}, store.Configs())
+	s.Require().NoError(store.Close())
+}
+
+// Сегмент с записью настроек удаляется вместе с сообщениями, но настройки пишутся заново в каждый новый сегмент.
+func (s *walTestSuite) TestSegments_ConfigsSurviveRemoval() {
+	config := s.config(wal.SyncNever, 1)
+	queueConfig := domain.QueueConfig{Type: domain.QueueTypePriority, MaxLen: maxLen, Overflow: domain.OverflowBlock}
+	store := s.newStore(config)
+	s.Require().NoError(store.SaveQueueConfig("jobs", queueConfig))
+	first := s.segments(config.Dir)[0]
+	queueInstance := store.Factory("jobs", queueConfig, nil)
+	for _, message := range messagesOf("a", "b") {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	s.getEqual(queueInstance, "a")
+	s.getEqual(queueInstance, "b")
+	s.NotContains(s.segments(config.Dir), first)
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	s.Equal(map[string]domain.QueueConfig{"jobs": queueConfig}, store.Configs())
+	s.Require().NoError(store.Close())
+}
+
+func (s *walTestSuite) TestExpired_RemovedFromJournal() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueConfig := domain.QueueConfig{MaxLen: maxLen, Overflow: domain.OverflowBlock, MessageTTL: 50 * time.Millisecond}
+	deadLetters := make(chan string, 1)
+	deadLetter := func(message domain.Message) { deadLetters <- string(message.Body) }
+	queueInstance := store.Factory("ttl", queueConfig, deadLetter)
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageOf("stale"), domain.PutOptions{}))
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageOf("fresh"), domain.PutOptions{
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	s.closeStore(store, queueInstance)
+
+	// Срок жизни пережил рестарт, протухшее сообщение уходит в очередь недоставленных.
+	time.Sleep(100 * time.Millisecond)
+	store = s.newStore(config)
+	queueInstance = store.Factory("ttl", queueConfig, deadLetter)
+	s.getEqual(queueInstance, "fresh")
+	s.Equal("stale", <-deadLetters)
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	s.Empty(store.Names())
+	s.Require().NoError(store.Close())
+}
+
+func (s *walTestSuite) TestSegments_DrainedSegmentsRemoved() {
+	config := s.config(wal.SyncNever, 1)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "rotate")
+	for _, message := range messagesOf("a", "b", "c") {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	s.Len(s.segments(config.Dir), 3)
+
+	s.getEqual(queueInstance, "a")
+	s.getEqual(queueInstance, "b")
+	// Удалены сегменты a и b, подтверждение a лежит после живого c и остается вместе с активным.
+	s.Len(s.segments(config.Dir), 3)
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "rotate")
+	s.getEqual(queueInstance, "c")
+	s.closeStore(store, queueInstance)
+}
+
+// Сегмент только с подтверждениями удалять нельзя, пока жив более старый сегмент с их сообщениями,
+// иначе после рестарта забранные сообщения вернутся. По две записи в сегменте: [a b] [c d] [ack b, ack c] [ack d, e].
+func (s *walTestSuite) TestSegments_AcksKeptWhileOlderSegmentLive() {
+	config := s.config(wal.SyncAlways, 100)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "acks")
+	for _, message := range messagesOf("a", "b", "c", "d") {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	_, err := queueInstance.LeaseMessage(context.Background(), time.Minute)
+	s.Require().NoError(err)
+	s.getEqual(queueInstance, "b")
+	s.getEqual(queueInstance, "c")
+	s.getEqual(queueInstance, "d")
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageOf("e"), domain.PutOptions{}))
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "acks")
+	messages, err := queueInstance.GetMessages(context.Background(), maxLen)
+	s.Require().NoError(err)
+	s.Equal([]string{"a", "e"}, bodiesOf(messages))
+	s.closeStore(store, queueInstance)
+}
+
+// Долго лежащие сообщения переносятся из первого сегмента в активный, и сегментов не становится больше
+// с каждой ротацией. Перенесенные сообщения подтверждаются и восстанавливаются в исходном порядке.
+func (s *walTestSuite) TestSegments_PinnedMessageCompacted() {
+	config := s.config(wal.SyncNever, 512)
+	store := s.newStore(config)
+	pinned, busy := s.factory(store, "dlq"), s.factory(store, "busy")
+	for _, message := range messagesOf("first", "second") {
+		s.Require().NoError(pinned.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	for range 2000 {
+		s.Require().NoError(busy.PutMessage(context.Background(), messageOf("message"), domain.PutOptions{}))
+		s.getEqual(busy, "message")
+	}
+	s.LessOrEqual(len(s.segments(config.Dir)), 4)
+	s.Require().NoError(pinned.PutMessage(context.Background(), messageOf("third"), domain.PutOptions{}))
+	s.getEqual(pinned, "first")
+	s.closeStore(store, pinned, busy)
+
+	store = s.newStore(config)
+	s.Equal([]string{"dlq"}, store.Names())
+	pinned = s.factory(store, "dlq")
+	messages, err := pinned.GetMessages(context.Background(), maxLen)
+	s.Require().NoError(err)
+	s.Equal([]string{"second", "third"}, bodiesOf(messages))
+	s.closeStore(store, pinned)
+}
+
+func (s *walTestSuite) TestReplay_TornTailIgnored() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "torn")
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageOf("whole"), domain.PutOptions{}))
+	s.closeStore(store, queueInstance)
+
+	segments := s.segments(config.Dir)
+	s.Require().Len(segments, 1)
+	file, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0)
+	s.Require().NoError(err)
+	_, err = file.Write([]byte{42, 0, 0, 0, 1, 2})
+	s.Require().NoError(err)
+	s.Require().NoError(file.Close())
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "torn")
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageOf("after"), domain.PutOptions{}))
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = s.factory(store, "torn")
+	s.getEqual(queueInstance, "whole")
+	s.getEqual(queueInstance, "after")
+	s.closeStore(store, queueInstance)
+}
+
+func (s *walTestSuite) TestOverflow_DroppedNotRestored() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueInstance := store.Factory("drop", domain.QueueConfig{MaxLen: 1, Overflow: domain.OverflowDropOldest}, nil)
+	for _, message := range messagesOf("dropped", "kept") {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = store.Factory("drop", domain.QueueConfig{MaxLen: 1, Overflow: domain.OverflowReject}, nil)
+	s.getEqual(queueInstance, "kept")
+	s.Require().NoError(queueInstance.PutMessage(context.Background(), messageOf("next"), domain.PutOptions{}))
+	err := queueInstance.PutMessage(context.Background(), messageOf("rejected"), domain.PutOptions{})
+	s.Require().ErrorIs(err, domain.ErrQueueFull)
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	queueInstance = store.Factory("drop", domain.QueueConfig{MaxLen: 1, Overflow: domain.OverflowReject}, nil)
+	s.getEqual(queueInstance, "next")
+	s.closeStore(store, queueInstance)
+}
+
+func (s *walTestSuite) TestPurge_NotRestored() {
+	config := s.config(wal.SyncAlways, 0)
+	store := s.newStore(config)
+	queueInstance := s.factory(store, "purge")
+	for _, message := range messagesOf("ready", "leased") {
+		s.Require().NoError(queueInstance.PutMessage(context.Background(), message, domain.PutOptions{}))
+	}
+	_, err := queueInstance.LeaseMessage(context.Background(), time.Minute)
+	s.Require().NoError(err)
+	s.Equal(2, queueInstance.Purge())
+	s.closeStore(store, queueInstance)
+
+	store = s.newStore(config)
+	s.Empty(store.Names())
+	s.Require().NoError(store.Close())
+}
+
+func (s *walTestSuite) TestParseSyncPolicy() {
+	policy, err := wal.ParseSyncPolicy("interval")
+	s.Require().NoError(err)
+	s.Equal(wal.SyncInterval, policy)
+	_, err = wal.ParseSyncPolicy("sometimes")
+	s.Require().ErrorIs(err, wal.ErrUnknownSyncPolicy)
+}
+
+func (s *walTestSuite) TestNewStore_InvalidSyncInterval() {
+	config := s.config(wal.SyncInterval, 0)
+	config.SyncInterval = 0
+	_, err := wal.NewStore(config)
+	s.Require().ErrorIs(err, wal.ErrInvalidSyncInterval)
+}
+
+func (s *walTestSuite) config(policy wal.SyncPolicy, segmentSize int64) wal.Config {
+	return wal.Config{
+		Dir:          s.T().TempDir(),
+		SyncPolicy:   policy,
+		SyncInterval: 10 * time.Millisecond,
+		SegmentSize:  segmentSize,
+	}
+}
+
+func (s *walTestSuite) factory(store *wal.Store, name string) domain.Queue { //nolint:ireturn
+	return store.Factory(name, domain.QueueConfig{MaxLen: maxLen, Overflow: domain.OverflowBlock}, nil)
+}
+
+func (s *walTestSuite) newStore(config wal.Config) *wal.Store {
+	store, err := wal.NewStore(config)
+	s.Require().NoError(err)
+	return store
+}
+
+func (s *walTestSuite) closeStore(store *wal.Store, queues ...domain.Queue) {
+	for _, queueInstance := range queues {
+		queueInstance.Close()
+	}
+	s.Require().NoError(store.Close())
+}
+
+func (s *walTestSuite) getEqual(queueInstance domain.Queue, expected string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	message, err := queueInstance.GetMessage(ctx)
+	s.Require().NoError(err)
+	s.Equal(expected, string(message.Body))
+}
+
+func messageOf(body string) domain.Message {
+	return domain.Message{Body: []byte(body)}
+}
+
+func messagesOf(bodies ...string) []domain.Message {
+	messages := make([]domain.Message, 0, len(bodies))
+	for _, body := range bodies {
+		messages = append(messages, messageOf(body))
+	}
+	return messages
+}
+
+func bodiesOf(messages []domain.Message) []string {
+	bodies := make([]string, 0, len(messages))
+	for _, message := range messages {
+		bodies = append(bodies, string(message.Body))
+	}
+	return bodies
+}
+
+func (s *walTestSuite) segments(dir string) []string {
+	segments, err := filepath.Glob(filepath.Join(dir, "*.wal"))
+	s.Require().NoError(err)
+	return segments
+}
+
+func TestWAL(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(walTestSuite))
+}
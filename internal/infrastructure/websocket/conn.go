@@ -0,0 +1,151 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// WebSocket поверх net.Conn без внешних зависимостей, только то, что нужно нам:
+// сообщения целиком, без расширений и подпротоколов.
+
+// CloseCode Код причины закрытия соединения по RFC 6455.
+type CloseCode uint16
+
+const (
+	CloseNormal          CloseCode = 1000
+	CloseGoingAway       CloseCode = 1001
+	CloseProtocolError   CloseCode = 1002
+	CloseMessageTooLarge CloseCode = 1009
+)
+
+const defaultMaxMessageBytes = 1 << 20
+
+type Options struct {
+	// ReadTimeout Сколько ждем любой фрейм от собеседника, 0 - без ограничения.
+	// Понг тоже фрейм, поэтому вместе с регулярным Ping это проверка, что собеседник жив.
+	ReadTimeout time.Duration
+	// WriteTimeout Сколько ждем отправку одного сообщения, 0 - без ограничения.
+	WriteTimeout time.Duration
+	// MaxMessageBytes Ограничение на входящее сообщение, 0 - 1MB.
+	MaxMessageBytes int64
+}
+
+// Conn Читать можно из одной горутины, писать - из любых, запись под мьютексом.
+// На Ping отвечаем сами, Close собеседника подтверждаем сами.
+type Conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+	options Options
+	// Client Клиент маскирует свои фреймы и ждет немаскированные, сервер наоборот.
+	client  bool
+	writeMu *sync.Mutex
+}
+
+func newConn(netConn net.Conn, reader *bufio.Reader, options Options, client bool) *Conn {
+	if options.MaxMessageBytes <= 0 {
+		options.MaxMessageBytes = defaultMaxMessageBytes
+	}
+	return &Conn{
+		netConn: netConn,
+		reader:  reader,
+		options: options,
+		client:  client,
+		writeMu: &sync.Mutex{},
+	}
+}
+
+// ReadMessage Собирает фрагментированное сообщение целиком, управляющие фреймы обрабатывает по пути.
+// После Close от собеседника возвращает ErrClosed.
+func (conn *Conn) ReadMessage() (Opcode, []byte, error) {
+	var (
+		opcode  Opcode
+		message []byte
+	)
+	for {
+		f, err := conn.nextFrame(conn.options.MaxMessageBytes - int64(len(message)))
+		if err != nil {
+			return 0, nil, err
+		}
+		if f.opcode.isControl() {
+			err = conn.control(f)
+			if err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+		opcode, err = continued(opcode, f.opcode)
+		if err != nil {
+			return 0, nil, err
+		}
+		message = append(message, f.payload...)
+		if f.fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+func (conn *Conn) nextFrame(limit int64) (frame, error) {
+	if conn.options.ReadTimeout > 0 {
+		_ = conn.netConn.SetReadDeadline(time.Now().Add(conn.options.ReadTimeout))
+	}
+	return readFrame(conn.reader, !conn.client, limit)
+}
+
+// continued Тип сообщения, которое продолжает фрейм: продолжение бывает только у начатого сообщения.
+func continued(current Opcode, next Opcode) (Opcode, error) {
+	switch {
+	case current == 0 && next == OpContinuation, current != 0 && next != OpContinuation:
+		return 0, fmt.Errorf("%w: unexpected fragment", ErrProtocol)
+	case current == 0:
+		return next, nil
+	default:
+		return current, nil
+	}
+}
+
+func (conn *Conn) control(f frame) error {
+	switch f.opcode {
+	case OpPing:
+		return conn.WriteMessage(OpPong, f.payload)
+	case OpClose:
+		// Подтверждаем тем же кодом, дальше писать в соединение нельзя.
+		_ = conn.WriteMessage(OpClose, f.payload)
+		return ErrClosed
+	default:
+		return nil
+	}
+}
+
+func (conn *Conn) WriteMessage(opcode Opcode, payload []byte) error {
+	var key *[maskKeyLength]byte
+	if conn.client {
+		key = &[maskKeyLength]byte{}
+		_, _ = rand.Read(key[:])
+	}
+	conn.writeMu.Lock()
+	defer conn.writeMu.Unlock()
+	if conn.options.WriteTimeout > 0 {
+		_ = conn.netConn.SetWriteDeadline(time.Now().Add(conn.options.WriteTimeout))
+	}
+	err := writeFrame(conn.netConn, opcode, payload, key)
+	if err != nil {
+		return fmt.Errorf("write websocket frame: %w", err)
+	}
+	return nil
+}
+
+func (conn *Conn) Ping() error {
+	return conn.WriteMessage(OpPing, nil)
+}
+
+// Close Отправляет собеседнику причину и закрывает соединение, не дожидаясь ответа.
+func (conn *Conn) Close(code CloseCode, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	_ = conn.WriteMessage(OpClose, append(payload, reason...))
+	return conn.netConn.Close() //nolint:wrapcheck
+}
@@ -0,0 +1,113 @@
+package websocket_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kukwuka/queue/internal/infrastructure/websocket"
+)
+
+type connTestSuite struct {
+	suite.Suite
+}
+
+// echoServer Отвечает тем же сообщением, на "close" закрывает соединение сам.
+func (s *connTestSuite) echoServer(options websocket.Options) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r, options)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for {
+			opcode, message, err := conn.ReadMessage()
+			if err != nil {
+				_ = conn.Close(websocket.CloseMessageTooLarge, err.Error())
+				return
+			}
+			if string(message) == "close" {
+				_ = conn.Close(websocket.CloseNormal, "bye")
+				return
+			}
+			s.NoError(conn.WriteMessage(opcode, message))
+		}
+	}))
+}
+
+func (s *connTestSuite) dial(server *httptest.Server) *websocket.Conn {
+	conn, err := websocket.Dial(
+		context.Background(),
+		"ws"+strings.TrimPrefix(server.URL, "http"),
+		websocket.Options{ReadTimeout: time.Second},
+	)
+	s.Require().NoError(err)
+	return conn
+}
+
+func (s *connTestSuite) TestEcho_SmallAndLarge() {
+	server := s.echoServer(websocket.Options{})
+	defer server.Close()
+	conn := s.dial(server)
+	defer conn.Close(websocket.CloseNormal, "")
+
+	// Большое сообщение идет с 64-битной длиной.
+	for _, message := range []string{"hello", strings.Repeat("x", 300), strings.Repeat("y", 70000)} {
+		s.Require().NoError(conn.WriteMessage(websocket.OpText, []byte(message)))
+		opcode, received, err := conn.ReadMessage()
+		s.Require().NoError(err)
+		s.Equal(websocket.OpText, opcode)
+		s.Equal(message, string(received))
+	}
+	// Понг на пинг сервер отправляет сам, клиент его пропускает и получает следующее сообщение.
+	s.Require().NoError(conn.Ping())
+	s.Require().NoError(conn.WriteMessage(websocket.OpBinary, []byte{1, 2}))
+	opcode, received, err := conn.ReadMessage()
+	s.Require().NoError(err)
+	s.Equal(websocket.OpBinary, opcode)
+	s.Equal([]byte{1, 2}, received)
+}
+
+func (s *connTestSuite) TestClose_FromServer() {
+	server := s.echoServer(websocket.Options{})
+	defer server.Close()
+	conn := s.dial(server)
+	defer conn.Close(websocket.CloseNormal, "")
+
+	s.Require().NoError(conn.WriteMessage(websocket.OpText, []byte("close")))
+	_, _, err := conn.ReadMessage()
+	s.ErrorIs(err, websocket.ErrClosed)
+}
+
+func (s *connTestSuite) TestMessageTooLarge() {
+	server := s.echoServer(websocket.Options{MaxMessageBytes: 10})
+	defer server.Close()
+	conn := s.dial(server)
+	defer conn.Close(websocket.CloseNormal, "")
+
+	s.Require().NoError(conn.WriteMessage(websocket.OpText, []byte(strings.Repeat("x", 11))))
+	_, _, err := conn.ReadMessage()
+	s.ErrorIs(err, websocket.ErrClosed)
+}
+
+func (s *connTestSuite) TestUpgrade_BadHandshake() {
+	server := s.echoServer(websocket.Options{})
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	s.Require().NoError(err)
+	response, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer response.Body.Close()
+	s.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
+func TestConn(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(connTestSuite))
+}
@@ -0,0 +1,154 @@
+package websocket
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Opcode Тип фрейма по RFC 6455.
+type Opcode byte
+
+const (
+	OpContinuation Opcode = 0x0
+	OpText         Opcode = 0x1
+	OpBinary       Opcode = 0x2
+	OpClose        Opcode = 0x8
+	OpPing         Opcode = 0x9
+	OpPong         Opcode = 0xA
+)
+
+var (
+	ErrBadHandshake    = errors.New("bad websocket handshake")
+	ErrProtocol        = errors.New("websocket protocol error")
+	ErrMessageTooLarge = errors.New("websocket message is too large")
+	ErrClosed          = errors.New("websocket connection is closed")
+)
+
+// isControl Управляющие фреймы не фрагментируются и приходят посреди фрагментированного сообщения.
+func (opcode Opcode) isControl() bool {
+	return opcode >= OpClose
+}
+
+type frame struct {
+	fin     bool
+	opcode  Opcode
+	payload []byte
+}
+
+const (
+	finBit             = 0x80
+	reservedBits       = 0x70
+	opcodeBits         = 0x0f
+	maskBit            = 0x80
+	lengthBits         = 0x7f
+	maxControlLength   = 125
+	length16           = 126
+	length64           = 127
+	maskKeyLength      = 4
+	frameHeaderMaxSize = 14
+)
+
+// readFrame Limit ограничивает длину фрейма с данными, чтобы не выделять память под чужой заголовок.
+func readFrame(r io.Reader, masked bool, limit int64) (frame, error) {
+	var header [2]byte
+	_, err := io.ReadFull(r, header[:])
+	if err != nil {
+		return frame{}, err //nolint:wrapcheck
+	}
+	f := frame{fin: header[0]&finBit != 0, opcode: Opcode(header[0] & opcodeBits)}
+	err = checkHeader(f, header, masked)
+	if err != nil {
+		return frame{}, err
+	}
+	length, err := readLength(r, header[1]&lengthBits)
+	if err != nil {
+		return frame{}, err
+	}
+	if !f.opcode.isControl() && length > uint64(limit) {
+		return frame{}, ErrMessageTooLarge
+	}
+	f.payload, err = readPayload(r, masked, length)
+	return f, err
+}
+
+func checkHeader(f frame, header [2]byte, masked bool) error {
+	switch {
+	case header[0]&reservedBits != 0:
+		return fmt.Errorf("%w: reserved bits are set", ErrProtocol)
+	case (header[1]&maskBit != 0) != masked:
+		return fmt.Errorf("%w: wrong masking", ErrProtocol)
+	case f.opcode.isControl() && (!f.fin || header[1]&lengthBits > maxControlLength):
+		return fmt.Errorf("%w: bad control frame", ErrProtocol)
+	default:
+		return nil
+	}
+}
+
+func readLength(r io.Reader, short byte) (uint64, error) {
+	switch short {
+	case length16:
+		var length [2]byte
+		_, err := io.ReadFull(r, length[:])
+		return uint64(binary.BigEndian.Uint16(length[:])), err //nolint:wrapcheck
+	case length64:
+		var length [8]byte
+		_, err := io.ReadFull(r, length[:])
+		return binary.BigEndian.Uint64(length[:]), err //nolint:wrapcheck
+	default:
+		return uint64(short), nil
+	}
+}
+
+func readPayload(r io.Reader, masked bool, length uint64) ([]byte, error) {
+	var key [maskKeyLength]byte
+	if masked {
+		_, err := io.ReadFull(r, key[:])
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+	}
+	payload := make([]byte, length)
+	_, err := io.ReadFull(r, payload)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	if masked {
+		mask(payload, key)
+	}
+	return payload, nil
+}
+
+// writeFrame Пишет один фрейм целиком, сообщения не фрагментируем. Маскирует только клиент.
+func writeFrame(w io.Writer, opcode Opcode, payload []byte, key *[maskKeyLength]byte) error {
+	header := make([]byte, 0, frameHeaderMaxSize)
+	header = append(header, finBit|byte(opcode))
+	var maskFlag byte
+	if key != nil {
+		maskFlag = maskBit
+	}
+	switch length := len(payload); {
+	case length <= maxControlLength:
+		header = append(header, maskFlag|byte(length))
+	case length <= 0xffff:
+		header = append(header, maskFlag|length16)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, maskFlag|length64)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+	if key != nil {
+		header = append(header, key[:]...)
+		payload = append([]byte(nil), payload...)
+		mask(payload, *key)
+	}
+	_, err := w.Write(append(header, payload...))
+	return err //nolint:wrapcheck
+}
+
+func mask(payload []byte, key [maskKeyLength]byte) {
+	for i := range payload {
+		payload[i] ^= key[i%maskKeyLength]
+	}
+}
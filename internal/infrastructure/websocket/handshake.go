@@ -0,0 +1,116 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// acceptGUID Константа из RFC 6455, из нее и ключа клиента считается Sec-WebSocket-Accept.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Upgrade Переводит HTTP запрос в WebSocket. На неправильный запрос возвращает ErrBadHandshake,
+// ответ клиенту в этом случае пишет вызывающий. После успеха писать в w нельзя.
+func Upgrade(w http.ResponseWriter, r *http.Request, options Options) (*Conn, error) {
+	key, err := checkHandshake(r)
+	if err != nil {
+		return nil, err
+	}
+	netConn, buffered, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijack connection: %w", err)
+	}
+	_, err = buffered.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		_ = netConn.Close()
+		return nil, fmt.Errorf("write handshake: %w", err)
+	}
+	return newConn(netConn, buffered.Reader, options, false), nil
+}
+
+func checkHandshake(r *http.Request) (string, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet,
+		!hasToken(r.Header, "Connection", "upgrade"),
+		!hasToken(r.Header, "Upgrade", "websocket"):
+		return "", fmt.Errorf("%w: not an upgrade request", ErrBadHandshake)
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		return "", fmt.Errorf("%w: only version 13 is supported", ErrBadHandshake)
+	case key == "":
+		return "", fmt.Errorf("%w: no key", ErrBadHandshake)
+	default:
+		return key, nil
+	}
+}
+
+// hasToken Заголовки вроде Connection: keep-alive, Upgrade перечисляют значения через запятую.
+func hasToken(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, candidate := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(candidate), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID)) //nolint:gosec
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Dial Клиент для ws:// адресов, без TLS. Нужен для проверки сервера и утилит.
+func Dial(ctx context.Context, rawURL string, options Options) (*Conn, error) {
+	const keyLength = 16
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+	netConn, err := (&net.Dialer{}).DialContext(ctx, "tcp", target.Host)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+	nonce := make([]byte, keyLength)
+	_, _ = rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+	reader := bufio.NewReader(netConn)
+	err = clientHandshake(netConn, reader, target, key)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	return newConn(netConn, reader, options, true), nil
+}
+
+func clientHandshake(netConn net.Conn, reader *bufio.Reader, target *url.URL, key string) error {
+	_, err := fmt.Fprintf(netConn, "GET %s HTTP/1.1\r\nHost: %s\r\n"+
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", target.RequestURI(), target.Host, key)
+	if err != nil {
+		return fmt.Errorf("write handshake: %w", err)
+	}
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		return fmt.Errorf("read handshake: %w", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusSwitchingProtocols ||
+		response.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return fmt.Errorf("%w: server answered %s", ErrBadHandshake, response.Status)
+	}
+	return nil
+}
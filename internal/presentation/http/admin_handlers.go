@@ -0,0 +1,212 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Ручки управления очередями, к несуществующей очереди отвечаем 404, новую не создаем.
+
+type queueInfoSchemas struct {
+	Name      string             `json:"name"`
+	Depth     int                `json:"depth"`
+	Waiting   int                `json:"waiting"`
+	InFlight  int                `json:"inFlight"`
+	Delayed   int                `json:"delayed"`
+	Expired   int                `json:"expired"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Config    queueConfigSchemas `json:"config"`
+}
+
+// Настройки очереди, при создании незаданные поля берутся из настроек сервера.
+// MessageTTL в секундах, как и ttl сообщения, DedupWindow - тоже в секундах.
+type queueConfigSchemas struct {
+	Type            domain.QueueType      `json:"type,omitempty"`
+	MaxSize         int                   `json:"maxSize,omitempty"`
+	OverflowPolicy  domain.OverflowPolicy `json:"overflowPolicy,omitempty"`
+	MessageTTL      int                   `json:"messageTTL,omitempty"`
+	MaxReceives     int                   `json:"maxReceives,omitempty"`
+	MaxMessageBytes int                   `json:"maxMessageBytes,omitempty"`
+	DedupWindow     int                   `json:"dedupWindow,omitempty"`
+}
+
+func (schema queueConfigSchemas) config() domain.QueueConfig {
+	return domain.QueueConfig{
+		Type:            schema.Type,
+		MaxLen:          schema.MaxSize,
+		Overflow:        schema.OverflowPolicy,
+		MessageTTL:      time.Second * time.Duration(schema.MessageTTL),
+		MaxReceives:     schema.MaxReceives,
+		MaxMessageBytes: schema.MaxMessageBytes,
+		DedupWindow:     time.Second * time.Duration(schema.DedupWindow),
+	}
+}
+
+func toQueueConfigSchemas(config domain.QueueConfig) queueConfigSchemas {
+	return queueConfigSchemas{
+		Type:            config.Type,
+		MaxSize:         config.MaxLen,
+		OverflowPolicy:  config.Overflow,
+		MessageTTL:      int(config.MessageTTL / time.Second),
+		MaxReceives:     config.MaxReceives,
+		MaxMessageBytes: config.MaxMessageBytes,
+		DedupWindow:     int(config.DedupWindow / time.Second),
+	}
+}
+
+type purgeSchemas struct {
+	Purged int `json:"purged"`
+}
+
+type redriveSchemas struct {
+	Redriven int `json:"redriven"`
+}
+
+func newListQueuesHandler(queues domain.Queues, _ *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		infos := queues.ListQueues()
+		schemas := make([]queueInfoSchemas, 0, len(infos))
+		for _, info := range infos {
+			schemas = append(schemas, toQueueInfoSchemas(info))
+		}
+		writeJSON(w, schemas)
+	}
+}
+
+func newQueueInfoHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		info, err := queues.QueueInfo(r.PathValue("queue"))
+		if err != nil {
+			writeAdminError(w, "queue info handler", err, logger)
+			return
+		}
+		writeJSON(w, toQueueInfoSchemas(info))
+	}
+}
+
+// Создаем очередь со своими настройками, с пустым телом {} - с настройками по умолчанию.
+func newCreateQueueHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var schema queueConfigSchemas
+		err := json.NewDecoder(r.Body).Decode(&schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = queues.CreateQueue(r.PathValue("queue"), schema.config())
+		if err != nil {
+			writeAdminError(w, "create queue handler", err, logger)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func newDeleteQueueHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := queues.DeleteQueue(r.PathValue("queue"))
+		if err != nil {
+			writeAdminError(w, "delete queue handler", err, logger)
+			return
+		}
+	}
+}
+
+func newPurgeQueueHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		purged, err := queues.PurgeQueue(r.PathValue("queue"))
+		if err != nil {
+			writeAdminError(w, "purge queue handler", err, logger)
+			return
+		}
+		writeJSON(w, purgeSchemas{Purged: purged})
+	}
+}
+
+// Смотрим первые сообщения, не забирая их у получателей.
+func newPeekQueueHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	const defaultPeekLimit = 10
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultPeekLimit
+		if r.URL.Query().Has(limitQueryParamKey) {
+			var err error
+			limit, err = strconv.Atoi(r.URL.Query().Get(limitQueryParamKey))
+			if err != nil || limit <= 0 {
+				http.Error(w, "limit must be positive number", http.StatusBadRequest)
+				return
+			}
+		}
+		messages, err := queues.PeekQueue(r.PathValue("queue"), limit)
+		if err != nil {
+			writeAdminError(w, "peek queue handler", err, logger)
+			return
+		}
+		writeJSON(w, toDeliveredBatchSchemas(messages))
+	}
+}
+
+// Возвращаем сообщения из очереди недоставленных, например после исправления получателя.
+func newRedriveQueueHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		redriven, err := queues.RedriveQueue(r.Context(), r.PathValue("queue"))
+		if err != nil {
+			writeAdminError(w, "redrive queue handler", err, logger)
+			return
+		}
+		writeJSON(w, redriveSchemas{Redriven: redriven})
+	}
+}
+
+func toQueueInfoSchemas(info domain.QueueInfo) queueInfoSchemas {
+	return queueInfoSchemas{
+		Name:      info.Name,
+		Depth:     info.Depth,
+		Waiting:   info.Waiting,
+		InFlight:  info.InFlight,
+		Delayed:   info.Delayed,
+		Expired:   info.Expired,
+		CreatedAt: info.CreatedAt,
+		Config:    toQueueConfigSchemas(info.Config),
+	}
+}
+
+func writeAdminError(w http.ResponseWriter, name string, err error, logger *slog.Logger) {
+	status, known := adminErrorStatus(err)
+	if known {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	logger.Error(fmt.Errorf("%s: %w", name, err).Error())
+}
+
+// adminErrorStatus Перенос сообщений пишет в очередь, поэтому ошибки записи отдаем так же, как в PUT.
+func adminErrorStatus(err error) (int, bool) {
+	status, known := matchErrorStatus(
+		err,
+		errorStatus{domain.ErrQueueNotFound, http.StatusNotFound},
+		errorStatus{domain.ErrQueueExists, http.StatusConflict},
+		errorStatus{domain.ErrNoDeadLetterQueue, http.StatusBadRequest},
+		errorStatus{domain.ErrInvalidQueueConfig, http.StatusBadRequest},
+		errorStatus{domain.ErrUnknownOverflowPolicy, http.StatusBadRequest},
+		errorStatus{domain.ErrUnknownQueueType, http.StatusBadRequest},
+	)
+	if known {
+		return status, true
+	}
+	return putErrorStatus(err)
+}
+
+func writeJSON(w http.ResponseWriter, payload any) {
+	err := json.NewEncoder(w).Encode(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
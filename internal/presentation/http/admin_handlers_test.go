@@ -0,0 +1,225 @@
+package http_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kukwuka/queue/internal/domain"
+	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+func (s *handlerTestSuite) TestListQueuesHandler_Success() {
+	req, err := http.NewRequest(http.MethodGet, "/queues", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		ListQueues().
+		Return([]domain.QueueInfo{{
+			Name:       queueName,
+			CreatedAt:  createdAt,
+			Config:     domain.QueueConfig{Type: domain.QueueTypeFIFO, MaxLen: 10, Overflow: domain.OverflowReject},
+			QueueStats: domain.QueueStats{Depth: 1, Waiting: 2, InFlight: 3, Delayed: 4, Expired: 5},
+		}})
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(
+		`[{
+			"name": "test", "depth": 1, "waiting": 2, "inFlight": 3, "delayed": 4, "expired": 5,
+			"createdAt": "2024-05-01T10:00:00Z",
+			"config": {"type": "fifo", "maxSize": 10, "overflowPolicy": "reject"}
+		}]`,
+		response.Body.String(),
+	)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestQueueInfoHandler_ErrQueueNotFound() {
+	req, err := http.NewRequest(http.MethodGet, "/queues/"+queueName, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		QueueInfo(queueName).
+		Return(domain.QueueInfo{}, domain.ErrQueueNotFound)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusNotFound, response.Code)
+	s.Equal("queue not found\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestCreateQueueHandler_Success() {
+	body := []byte(`{"type": "priority", "maxSize": 100, "messageTTL": 60, "maxMessageBytes": 1024}`)
+	req, err := http.NewRequest(http.MethodPut, "/queues/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		CreateQueue(queueName, domain.QueueConfig{
+			Type:            domain.QueueTypePriority,
+			MaxLen:          100,
+			MessageTTL:      time.Minute,
+			MaxMessageBytes: 1024,
+		}).
+		Return(nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusCreated, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestCreateQueueHandler_ErrQueueExists() {
+	req, err := http.NewRequest(http.MethodPut, "/queues/"+queueName, bytes.NewBufferString(`{}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		CreateQueue(queueName, domain.QueueConfig{}).
+		Return(domain.ErrQueueExists)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusConflict, response.Code)
+	s.Equal("queue already exists\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestDeleteQueueHandler_Success() {
+	req, err := http.NewRequest(http.MethodDelete, "/queue/"+queueName, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		DeleteQueue(queueName).
+		Return(nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPurgeQueueHandler_Success() {
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/purge", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PurgeQueue(queueName).
+		Return(5, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"purged": 5}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestRedriveQueueHandler_Success() {
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/redrive", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		RedriveQueue(mock.Anything, queueName).
+		Return(3, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"redriven": 3}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestRedriveQueueHandler_ErrNoDeadLetterQueue() {
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/redrive", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		RedriveQueue(mock.Anything, queueName).
+		Return(0, domain.ErrNoDeadLetterQueue)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Equal("dead letter queue is not configured\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPeekQueueHandler_Success() {
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"/peek?limit=2", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PeekQueue(queueName, 2).
+		Return([]domain.Message{
+			{ID: "1", Body: []byte("first"), EnqueuedAt: enqueuedAt},
+			{ID: "2", Body: []byte("second"), EnqueuedAt: enqueuedAt, DeliveryCount: 1},
+		}, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"messages": [
+		{"id": "1", "message": "first", "enqueuedAt": "2024-05-01T10:00:00Z", "deliveryCount": 0},
+		{"id": "2", "message": "second", "enqueuedAt": "2024-05-01T10:00:00Z", "deliveryCount": 1}
+	]}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPeekQueueHandler_ErrInvalidLimit() {
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"/peek?limit=-1", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Equal("limit must be positive number\n", response.Body.String())
+	s.Zero(buffer.String())
+}
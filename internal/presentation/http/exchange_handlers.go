@@ -0,0 +1,97 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Обменники: сообщение с ключом orders.eu.created копируется в очереди, привязанные подходящим шаблоном.
+
+const patternQueryParamKey = "pattern"
+
+type bindingSchemas struct {
+	Pattern string `json:"pattern"`
+	Queue   string `json:"queue"`
+}
+
+type bindingsSchemas struct {
+	Bindings []bindingSchemas `json:"bindings"`
+}
+
+func newBindHandler(exchanges domain.Exchanges, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var schema bindingSchemas
+		err := json.NewDecoder(r.Body).Decode(&schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if schema.Queue == "" {
+			http.Error(w, "queue must not be empty", http.StatusBadRequest)
+			return
+		}
+		err = exchanges.Bind(r.PathValue("exchange"), domain.Binding(schema))
+		switch {
+		case errors.Is(err, domain.ErrBadPattern):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case err != nil:
+			logger.Error(fmt.Errorf("bind handler: %w", err).Error())
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}
+}
+
+// Шаблон передается параметром pattern, # в нем нужно экранировать как %23.
+func newUnbindHandler(exchanges domain.Exchanges, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		binding := domain.Binding{Pattern: r.URL.Query().Get(patternQueryParamKey), Queue: r.PathValue("queue")}
+		err := exchanges.Unbind(r.PathValue("exchange"), binding)
+		switch {
+		case errors.Is(err, domain.ErrBindingNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err != nil:
+			logger.Error(fmt.Errorf("unbind handler: %w", err).Error())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+func newBindingsHandler(exchanges domain.Exchanges, _ *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bindings := exchanges.Bindings(r.PathValue("exchange"))
+		schemas := make([]bindingSchemas, 0, len(bindings))
+		for _, binding := range bindings {
+			schemas = append(schemas, bindingSchemas(binding))
+		}
+		writeJSON(w, bindingsSchemas{Bindings: schemas})
+	}
+}
+
+// Тело, Idempotency-Key и ответ как у публикации в топик.
+func newPublishToExchangeHandler(exchanges domain.Exchanges, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var schema messageSchemas
+		err := json.NewDecoder(r.Body).Decode(&schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		options, err := schema.putOptions()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		options.IdempotencyKey = r.Header.Get(idempotencyKeyHeader)
+		published, err := exchanges.Publish(
+			r.Context(), r.PathValue("exchange"), r.PathValue("routingKey"), schema.message(), options,
+		)
+		writePublishResult(w, "publish to exchange handler", published, err, logger)
+	}
+}
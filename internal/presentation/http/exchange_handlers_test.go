@@ -0,0 +1,123 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/kukwuka/queue/internal/domain"
+	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+const exchangeName = "events"
+
+func (s *handlerTestSuite) TestBindHandler_Success() {
+	req, err := http.NewRequest(http.MethodPost, "/exchange/"+exchangeName+"/bindings",
+		bytes.NewBufferString(`{"pattern": "orders.#", "queue": "`+queueName+`"}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	exchanges := mocks.NewExchanges(s.T())
+	exchanges.
+		EXPECT().
+		Bind(exchangeName, domain.Binding{Pattern: "orders.#", Queue: queueName}).
+		Return(nil).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), mocks.NewTopics(s.T()), exchanges, logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusCreated, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestBindHandler_BadPattern() {
+	req, err := http.NewRequest(http.MethodPost, "/exchange/"+exchangeName+"/bindings",
+		bytes.NewBufferString(`{"pattern": "orders..created", "queue": "`+queueName+`"}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	exchanges := mocks.NewExchanges(s.T())
+	exchanges.
+		EXPECT().
+		Bind(exchangeName, domain.Binding{Pattern: "orders..created", Queue: queueName}).
+		Return(domain.ErrBadPattern).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), mocks.NewTopics(s.T()), exchanges, logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestUnbindHandler_NotFound() {
+	req, err := http.NewRequest(http.MethodDelete,
+		"/exchange/"+exchangeName+"/bindings/"+queueName+"?pattern=orders.%23", nil)
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	exchanges := mocks.NewExchanges(s.T())
+	exchanges.
+		EXPECT().
+		Unbind(exchangeName, domain.Binding{Pattern: "orders.#", Queue: queueName}).
+		Return(domain.ErrBindingNotFound).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), mocks.NewTopics(s.T()), exchanges, logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusNotFound, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestBindingsHandler_Success() {
+	req, err := http.NewRequest(http.MethodGet, "/exchange/"+exchangeName+"/bindings", nil)
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	exchanges := mocks.NewExchanges(s.T())
+	exchanges.
+		EXPECT().
+		Bindings(exchangeName).
+		Return([]domain.Binding{{Pattern: "orders.*.created", Queue: "created"}, {Pattern: "orders.#", Queue: "all"}}).
+		Once()
+	logger := slog.New(slog.NewJSONHandler(bytes.NewBuffer(nil), nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), mocks.NewTopics(s.T()), exchanges, logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"bindings": [
+		{"pattern": "orders.*.created", "queue": "created"},
+		{"pattern": "orders.#", "queue": "all"}
+	]}`, response.Body.String())
+}
+
+func (s *handlerTestSuite) TestPublishToExchangeHandler_Success() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodPut, "/exchange/"+exchangeName+"/orders.eu.created",
+		bytes.NewBufferString(`{"message": "message", "ttl": 0}`))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	req.Header.Set("Idempotency-Key", "key")
+	response := httptest.NewRecorder()
+
+	exchanges := mocks.NewExchanges(s.T())
+	exchanges.
+		EXPECT().
+		Publish(
+			ctx, exchangeName, "orders.eu.created", domain.Message{Body: []byte("message")},
+			domain.PutOptions{IdempotencyKey: "key"},
+		).
+		Return(domain.Published{ID: "id", Delivered: 3}, nil).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), mocks.NewTopics(s.T()), exchanges, logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"id": "id", "delivered": 3}`, response.Body.String())
+	s.Zero(buffer.String())
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Чтение лога группами: GET /queue/{queue}?group=billing отдает сообщения со смещения группы,
+// смещение двигает клиент через commit, поэтому без commit группа получит те же сообщения снова.
+
+const groupQueryParamKey = "group"
+
+type logRecordSchemas struct {
+	Offset int64 `json:"offset"`
+	deliveredSchemas
+}
+
+// groupReadSchemas NextOffset - что передать в commit, когда прочитанное обработано.
+type groupReadSchemas struct {
+	Records    []logRecordSchemas `json:"records"`
+	NextOffset int64              `json:"nextOffset"`
+}
+
+type commitSchemas struct {
+	Offset *int64 `json:"offset"`
+}
+
+type resetSchemas struct {
+	Position string `json:"position"`
+}
+
+// Сколько отдать задает max, по умолчанию одно сообщение. Новых нет - ждем, как обычный GET.
+func readGroupFromQueue(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	queues domain.Queues,
+	logger *slog.Logger,
+) {
+	limit := 1
+	if r.URL.Query().Has(maxQueryParamKey) {
+		var err error
+		limit, err = strconv.Atoi(r.URL.Query().Get(maxQueryParamKey))
+		if err != nil || limit <= 0 {
+			http.Error(w, "max must be positive number", http.StatusBadRequest)
+			return
+		}
+	}
+	records, err := queues.ReadGroupFromQueue(ctx, r.PathValue("queue"), r.URL.Query().Get(groupQueryParamKey), limit)
+	if err != nil {
+		writeGetError(w, "read group from queue handler", err, logger)
+		return
+	}
+	schemas := make([]logRecordSchemas, 0, len(records))
+	for _, record := range records {
+		schemas = append(schemas, logRecordSchemas{
+			Offset:           record.Offset,
+			deliveredSchemas: toDeliveredSchemas(record.Message),
+		})
+	}
+	writeJSON(w, groupReadSchemas{Records: schemas, NextOffset: records[len(records)-1].Offset + 1})
+}
+
+func newCommitOffsetHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var schema commitSchemas
+		err := json.NewDecoder(r.Body).Decode(&schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if schema.Offset == nil {
+			http.Error(w, "offset is required", http.StatusBadRequest)
+			return
+		}
+		err = queues.CommitGroupOffset(r.PathValue("queue"), r.PathValue("group"), *schema.Offset)
+		if err != nil {
+			writeGetError(w, "commit offset handler", err, logger)
+			return
+		}
+	}
+}
+
+// Сброс на earliest - перечитать все хранимое, на latest - пропустить все до текущего момента.
+func newResetOffsetHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var schema resetSchemas
+		err := json.NewDecoder(r.Body).Decode(&schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		position, err := domain.ParseOffsetPosition(schema.Position)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = queues.ResetGroupOffset(r.PathValue("queue"), r.PathValue("group"), position)
+		if err != nil {
+			writeGetError(w, "reset offset handler", err, logger)
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/kukwuka/queue/internal/domain"
+	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+func (s *handlerTestSuite) TestReadGroupHandler_Success() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"?group=billing&max=2", nil)
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		ReadGroupFromQueue(ctx, queueName, "billing", 2).
+		Return([]domain.LogRecord[domain.Message]{
+			{Offset: 4, Message: domain.Message{ID: "a", Body: []byte("a"), EnqueuedAt: enqueuedAt}},
+			{Offset: 5, Message: domain.Message{ID: "b", Body: []byte("b"), EnqueuedAt: enqueuedAt}},
+		}, nil).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{
+		"records": [
+			{"offset": 4, "id": "a", "message": "a", "enqueuedAt": "2024-05-01T10:00:00Z", "deliveryCount": 0},
+			{"offset": 5, "id": "b", "message": "b", "enqueuedAt": "2024-05-01T10:00:00Z", "deliveryCount": 0}
+		],
+		"nextOffset": 6
+	}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_ErrGroupRequired() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName, nil)
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(ctx, queueName).
+		Return(domain.Message{}, fmt.Errorf("get message from queue %s: %w", queueName, domain.ErrGroupRequired)).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestCommitOffsetHandler_Success() {
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/groups/billing/commit",
+		bytes.NewBufferString(`{"offset": 6}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		CommitGroupOffset(queueName, "billing", int64(6)).
+		Return(nil).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestCommitOffsetHandler_NoOffset() {
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/groups/billing/commit",
+		bytes.NewBufferString(`{}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	logger := slog.New(slog.NewJSONHandler(bytes.NewBuffer(nil), nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Equal("offset is required\n", response.Body.String())
+}
+
+func (s *handlerTestSuite) TestResetOffsetHandler_Success() {
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/groups/billing/reset",
+		bytes.NewBufferString(`{"position": "earliest"}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		ResetGroupOffset(queueName, "billing", domain.OffsetEarliest).
+		Return(nil).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestResetOffsetHandler_UnknownPosition() {
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/groups/billing/reset",
+		bytes.NewBufferString(`{"position": "middle"}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	logger := slog.New(slog.NewJSONHandler(bytes.NewBuffer(nil), nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+}
@@ -10,8 +10,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/kukwuka/queue/internal/domain"
@@ -21,6 +23,9 @@ import (
 
 const queueName = "test"
 
+// enqueuedAt Время постановки у сообщений из моков.
+var enqueuedAt = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
 type handlerTestSuite struct {
 	suite.Suite
 }
@@ -33,17 +38,27 @@ func (s *handlerTestSuite) TestGetFromQueueHandler_Success() {
 	response := httptest.NewRecorder()
 
 	queuesInstance := mocks.NewQueues(s.T())
-	message := uuid.NewString()
+	message := domain.Message{
+		ID:            uuid.NewString(),
+		Body:          []byte("message"),
+		Headers:       map[string]string{"trace": "1"},
+		ContentType:   "text/plain",
+		EnqueuedAt:    enqueuedAt,
+		DeliveryCount: 2,
+	}
 	queuesInstance.
 		EXPECT().
 		GetMessageFromQueue(ctx, queueName).
 		Return(message, nil)
 	buffer := bytes.NewBuffer(nil)
 	logger := slog.New(slog.NewJSONHandler(buffer, nil))
-	mux := appHTTP.NewRouter(queuesInstance, logger)
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
 	mux.ServeHTTP(response, req)
 	s.Equal(http.StatusOK, response.Code)
-	s.JSONEq(fmt.Sprintf("{\"message\": %q}", message), response.Body.String())
+	s.JSONEq(fmt.Sprintf(`{
+		"id": %q, "message": "message", "headers": {"trace": "1"}, "contentType": "text/plain",
+		"enqueuedAt": "2024-05-01T10:00:00Z", "deliveryCount": 2
+	}`, message.ID), response.Body.String())
 	s.Zero(buffer.String())
 }
 
@@ -55,16 +70,15 @@ func (s *handlerTestSuite) TestGetFromQueueHandler_ErrorFromQueues() {
 	response := httptest.NewRecorder()
 
 	queuesInstance := mocks.NewQueues(s.T())
-	message := uuid.NewString()
 	queuesInstance.
 		EXPECT().
 		GetMessageFromQueue(ctx, queueName).
-		Return(message, errors.New("some error"))
+		Return(domain.Message{}, errors.New("some error"))
 
 	buffer := bytes.NewBuffer(nil)
 	logger := slog.New(slog.NewJSONHandler(buffer, nil))
 
-	mux := appHTTP.NewRouter(queuesInstance, logger)
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
 	mux.ServeHTTP(response, req)
 	s.Equal(http.StatusInternalServerError, response.Code)
 	s.Zero(response.Body.String())
@@ -80,16 +94,15 @@ func (s *handlerTestSuite) TestGetFromQueueHandler_ErrorContextCanceled() {
 	response := httptest.NewRecorder()
 
 	queuesInstance := mocks.NewQueues(s.T())
-	message := uuid.NewString()
 	queuesInstance.
 		EXPECT().
 		GetMessageFromQueue(ctx, queueName).
-		Return(message, domain.ErrMessageWaitTimeOut)
+		Return(domain.Message{}, domain.ErrMessageWaitTimeOut)
 
 	buffer := bytes.NewBuffer(nil)
 	logger := slog.New(slog.NewJSONHandler(buffer, nil))
 
-	mux := appHTTP.NewRouter(queuesInstance, logger)
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
 	mux.ServeHTTP(response, req)
 	s.Equal(http.StatusNotFound, response.Code)
 	s.Equal("didn't wait for the message\n", response.Body.String())
@@ -107,14 +120,174 @@ func (s *handlerTestSuite) TestPutToQueueHandler_Success() {
 	queuesInstance := mocks.NewQueues(s.T())
 	queuesInstance.
 		EXPECT().
-		PutMessageToQueue(ctx, queueName, "message").
-		Return(nil)
+		PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("message")}, domain.PutOptions{}).
+		Return("id", nil)
 	buffer := bytes.NewBuffer(nil)
 	logger := slog.New(slog.NewJSONHandler(buffer, nil))
-	mux := appHTTP.NewRouter(queuesInstance, logger)
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"id": "id"}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_IdempotencyKey() {
+	ctx := context.Background()
+	body := []byte(`{"message": "message"}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req.Header.Set("Idempotency-Key", "key")
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	options := domain.PutOptions{IdempotencyKey: "key"}
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("message")}, options).
+		Return("first", nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"id": "first"}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_IdempotencyKeyInUse() {
+	ctx := context.Background()
+	body := []byte(`{"message": "message"}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req.Header.Set("Idempotency-Key", "key")
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	options := domain.PutOptions{IdempotencyKey: "key"}
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("message")}, options).
+		Return("", domain.ErrIdempotencyKeyInUse)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusConflict, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_Metadata() {
+	ctx := context.Background()
+	body := []byte(`{"message": "{}", "headers": {"trace": "1"}, "contentType": "application/json"}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, domain.Message{
+			Body:        []byte("{}"),
+			Headers:     map[string]string{"trace": "1"},
+			ContentType: "application/json",
+		}, domain.PutOptions{}).
+		Return("id", nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_Delay() {
+	ctx := context.Background()
+	message := domain.Message{Body: []byte("message")}
+	body := []byte(`{"message": "message", "delay": 30}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	expected := time.Now().Add(30 * time.Second)
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, message, mock.MatchedBy(func(options domain.PutOptions) bool {
+			return options.DeliverAt.Sub(expected).Abs() < time.Second
+		})).
+		Return("id", nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutBatchToQueueHandler_Priority() {
+	ctx := context.Background()
+	body := []byte(`{"messages": ["a", "b"], "priority": 7}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName+"/batch", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	messages := []domain.Message{{Body: []byte("a")}, {Body: []byte("b")}}
+	queuesInstance.
+		EXPECT().
+		PutMessagesToQueue(ctx, queueName, messages, domain.PutOptions{Priority: 7}).
+		Return([]string{"1", "2"}, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
 	mux.ServeHTTP(response, req)
 	s.Equal(http.StatusOK, response.Code)
-	s.Zero(response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_TTLFromDeliverAt() {
+	ctx := context.Background()
+	message := domain.Message{Body: []byte("message")}
+	body := []byte(`{"message": "message", "deliverAt": "2030-01-01T00:00:00Z", "ttl": 60}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	deliverAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, message, mock.MatchedBy(func(options domain.PutOptions) bool {
+			return options.DeliverAt.Equal(deliverAt) && options.ExpiresAt.Equal(deliverAt.Add(time.Minute))
+		})).
+		Return("id", nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_ErrDelayAndDeliverAt() {
+	body := []byte(`{"message": "message", "delay": 30, "deliverAt": "2030-01-01T00:00:00Z"}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Equal("only one of delay and deliverAt can be set\n", response.Body.String())
 	s.Zero(buffer.String())
 }
 
@@ -129,7 +302,7 @@ func (s *handlerTestSuite) TestPutToQueueHandler_ErrInvalidJson() {
 	queuesInstance := mocks.NewQueues(s.T())
 	buffer := bytes.NewBuffer(nil)
 	logger := slog.New(slog.NewJSONHandler(buffer, nil))
-	mux := appHTTP.NewRouter(queuesInstance, logger)
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
 	mux.ServeHTTP(response, req)
 	s.Equal(http.StatusBadRequest, response.Code)
 	s.Equal("invalid character '{' looking for beginning of object key string\n", response.Body.String())
@@ -147,17 +320,331 @@ func (s *handlerTestSuite) TestPutToQueueHandler_ErrFromQueues() {
 	queuesInstance := mocks.NewQueues(s.T())
 	queuesInstance.
 		EXPECT().
-		PutMessageToQueue(ctx, queueName, "message").
-		Return(errors.New("some put error"))
+		PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("message")}, domain.PutOptions{}).
+		Return("", errors.New("some put error"))
 	buffer := bytes.NewBuffer(nil)
 	logger := slog.New(slog.NewJSONHandler(buffer, nil))
-	mux := appHTTP.NewRouter(queuesInstance, logger)
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
 	mux.ServeHTTP(response, req)
 	s.Equal(http.StatusInternalServerError, response.Code)
 	s.Zero(response.Body.String())
 	s.logMessageEqual("put to queue handler: some put error", buffer.Bytes())
 }
 
+func (s *handlerTestSuite) TestGetFromQueueHandler_Batch() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"?max=3", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessagesFromQueue(ctx, queueName, 3).
+		Return([]domain.Message{
+			{ID: "1", Body: []byte("first"), EnqueuedAt: enqueuedAt, DeliveryCount: 1},
+			{ID: "2", Body: []byte("second"), EnqueuedAt: enqueuedAt, DeliveryCount: 1},
+		}, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"messages": [
+		{"id": "1", "message": "first", "enqueuedAt": "2024-05-01T10:00:00Z", "deliveryCount": 1},
+		{"id": "2", "message": "second", "enqueuedAt": "2024-05-01T10:00:00Z", "deliveryCount": 1}
+	]}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutBatchToQueueHandler_Success() {
+	ctx := context.Background()
+	body := []byte(`{"messages": ["first", "second"]}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName+"/batch", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	messages := []domain.Message{{Body: []byte("first")}, {Body: []byte("second")}}
+	queuesInstance.
+		EXPECT().
+		PutMessagesToQueue(ctx, queueName, messages, domain.PutOptions{}).
+		Return([]string{"1", "2"}, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"ids": ["1", "2"]}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutBatchToQueueHandler_ErrEmpty() {
+	body := []byte(`{"messages": []}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName+"/batch", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Equal("messages must not be empty\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_Lease() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"?visibility=30", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	lease := domain.Lease[domain.Message]{
+		Message: domain.Message{ID: uuid.NewString(), Body: []byte("message"), EnqueuedAt: enqueuedAt, DeliveryCount: 1},
+		Receipt: uuid.NewString(),
+	}
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(ctx, queueName, 30*time.Second).
+		Return(lease, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(
+		fmt.Sprintf(`{
+			"id": %q, "message": "message", "enqueuedAt": "2024-05-01T10:00:00Z", "deliveryCount": 1,
+			"receipt": %q, "visibilityTimeout": 30
+		}`, lease.Message.ID, lease.Receipt),
+		response.Body.String(),
+	)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_ErrInvalidVisibility() {
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"?visibility=0", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestAckHandler_Success() {
+	receipt := uuid.NewString()
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/ack/"+receipt, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		AckMessage(queueName, receipt).
+		Return(nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestNackHandler_ErrReceiptNotFound() {
+	receipt := uuid.NewString()
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/nack/"+receipt, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		NackMessage(queueName, receipt).
+		Return(domain.ErrReceiptNotFound)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusNotFound, response.Code)
+	s.Equal("receipt not found\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestRejectHandler_Success() {
+	receipt := uuid.NewString()
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/reject/"+receipt, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		NackMessage(queueName, receipt).
+		Return(nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestAckHandler_ErrFromQueues() {
+	receipt := uuid.NewString()
+	req, err := http.NewRequest(http.MethodPost, "/queue/"+queueName+"/ack/"+receipt, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		AckMessage(queueName, receipt).
+		Return(errors.New("some ack error"))
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusInternalServerError, response.Code)
+	s.logMessageEqual("ack handler: some ack error", buffer.Bytes())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_ErrQueueFull() {
+	ctx := context.Background()
+	body := []byte(`{"message": "message"}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("message")}, domain.PutOptions{}).
+		Return("", fmt.Errorf("put message to queue %s: %w", queueName, domain.ErrQueueFull))
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusInsufficientStorage, response.Code)
+	s.Equal("put message to queue test: queue is full\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_ErrMessageTooLarge() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBufferString(`{"message": "message"}`))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("message")}, domain.PutOptions{}).
+		Return("", domain.ErrMessageTooLarge)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusRequestEntityTooLarge, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_ErrShuttingDown() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBufferString(`{"message": "message"}`))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("message")}, domain.PutOptions{}).
+		Return("", domain.ErrShuttingDown)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusServiceUnavailable, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_ErrShuttingDown() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(ctx, queueName).
+		Return(domain.Message{}, domain.ErrShuttingDown)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusServiceUnavailable, response.Code)
+	s.Equal("shutting down\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_ErrQueueNotFound() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(ctx, queueName).
+		Return(domain.Message{}, domain.ErrQueueNotFound)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusNotFound, response.Code)
+	s.Equal("queue not found\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_ErrQueueClosed() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(ctx, queueName).
+		Return(domain.Message{}, domain.ErrQueueClosed)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusGone, response.Code)
+	s.Equal("queue is closed\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
 // Проверяем правильно ли логируем.
 func (s *handlerTestSuite) logMessageEqual(expectedMessage string, log []byte) {
 	type logSchema struct {
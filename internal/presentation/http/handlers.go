@@ -9,23 +9,188 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"github.com/kukwuka/queue/internal/domain"
 )
 
 // Я обычно использую echo, непривычно с чистым http работать.
 
-func NewRouter(queues domain.Queues, logger *slog.Logger) *http.ServeMux {
+func NewRouter(
+	queues domain.Queues,
+	topics domain.Topics,
+	exchanges domain.Exchanges,
+	logger *slog.Logger,
+) *http.ServeMux {
 	mux := http.NewServeMux()
-	mux.HandleFunc("PUT /queue/{queue}", newPutToQueueHandler(queues, logger))
-	mux.HandleFunc("GET /queue/{queue}", newGetFromQueueHandler(queues, logger))
+	metricsInstance := newHTTPMetrics(queues)
+	queues = waitObservedQueues{Queues: queues, wait: metricsInstance.wait}
+	handle := func(pattern string, handler http.HandlerFunc) {
+		mux.HandleFunc(pattern, metricsInstance.instrument(pattern, handler))
+	}
+	handle("PUT /queue/{queue}", newPutToQueueHandler(queues, logger))
+	handle("PUT /queue/{queue}/batch", newPutBatchToQueueHandler(queues, logger))
+	handle("GET /queue/{queue}", newGetFromQueueHandler(queues, logger))
+	handle("POST /queue/{queue}/ack/{receipt}", newAckHandler(queues, logger))
+	handle("POST /queue/{queue}/nack/{receipt}", newNackHandler(queues, logger))
+	handle("POST /queue/{queue}/reject/{receipt}", newNackHandler(queues, logger))
+	handle("GET /queues", newListQueuesHandler(queues, logger))
+	handle("GET /queues/{queue}", newQueueInfoHandler(queues, logger))
+	handle("PUT /queues/{queue}", newCreateQueueHandler(queues, logger))
+	handle("DELETE /queue/{queue}", newDeleteQueueHandler(queues, logger))
+	handle("POST /queue/{queue}/purge", newPurgeQueueHandler(queues, logger))
+	handle("GET /queue/{queue}/peek", newPeekQueueHandler(queues, logger))
+	handle("GET /queue/{queue}/stream", newStreamHandler(queues, logger))
+	handle("POST /queue/{queue}/redrive", newRedriveQueueHandler(queues, logger))
+	handle("POST /queue/{queue}/groups/{group}/commit", newCommitOffsetHandler(queues, logger))
+	handle("POST /queue/{queue}/groups/{group}/reset", newResetOffsetHandler(queues, logger))
+	handle("PUT /topic/{topic}", newPublishHandler(topics, logger))
+	handle("GET /topic/{topic}/subscriptions", newSubscriptionsHandler(topics, logger))
+	handle("POST /topic/{topic}/subscriptions", newSubscribeHandler(topics, logger))
+	handle("DELETE /topic/{topic}/subscriptions/{queue}", newUnsubscribeHandler(topics, logger))
+	handle("PUT /exchange/{exchange}/{routingKey}", newPublishToExchangeHandler(exchanges, logger))
+	handle("GET /exchange/{exchange}/bindings", newBindingsHandler(exchanges, logger))
+	handle("POST /exchange/{exchange}/bindings", newBindHandler(exchanges, logger))
+	handle("DELETE /exchange/{exchange}/bindings/{queue}", newUnbindHandler(exchanges, logger))
+	handle("GET /ws", newWebSocketHandler(queues, logger))
+	handle("GET /metrics", newMetricsHandler(metricsInstance, queues, logger))
 	return mux
 }
 
-const timeOutQueryParamKey = "timeout"
+const (
+	timeOutQueryParamKey    = "timeout"
+	visibilityQueryParamKey = "visibility"
+	maxQueryParamKey        = "max"
+	limitQueryParamKey      = "limit"
+	// rawQueryParamKey Отдать тело как есть, с его Content-Type, а метаданные - в заголовках.
+	rawQueryParamKey = "raw"
+	// idempotencyKeyHeader Повтор PUT с тем же ключом не кладет сообщение еще раз, а отвечает id первого.
+	idempotencyKeyHeader = "Idempotency-Key"
+)
 
+// messageSchemas Headers и ContentType сервер не разбирает, а возвращает получателю как есть.
 type messageSchemas struct {
-	Message string `json:"message"`
+	Message     string            `json:"message"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	putOptionsSchemas
+}
+
+func (schema messageSchemas) message() domain.Message {
+	return domain.Message{Body: []byte(schema.Message), Headers: schema.Headers, ContentType: schema.ContentType}
+}
+
+// deliveredSchemas Сообщение, как его видит получатель. DeliveryCount - какая это по счету выдача,
+// в peek - сколько раз сообщение уже выдавали.
+type deliveredSchemas struct {
+	ID            string            `json:"id"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	ContentType   string            `json:"contentType,omitempty"`
+	EnqueuedAt    time.Time         `json:"enqueuedAt"`
+	DeliveryCount int               `json:"deliveryCount"`
+	bodySchemas
+}
+
+func toDeliveredSchemas(message domain.Message) deliveredSchemas {
+	return deliveredSchemas{
+		ID:            message.ID,
+		bodySchemas:   toBodySchemas(message.Body),
+		Headers:       message.Headers,
+		ContentType:   message.ContentType,
+		EnqueuedAt:    message.EnqueuedAt,
+		DeliveryCount: message.DeliveryCount,
+	}
+}
+
+// bodySchemas Тело-текст отдаем в message, остальное - в payload (base64) с encoding: base64,
+// иначе JSON заменил бы байты не из UTF-8 на U+FFFD.
+type bodySchemas struct {
+	Message  *string `json:"message,omitempty"`
+	Payload  []byte  `json:"payload,omitempty"`
+	Encoding string  `json:"encoding,omitempty"`
+}
+
+const base64Encoding = "base64"
+
+func toBodySchemas(body []byte) bodySchemas {
+	if !utf8.Valid(body) {
+		return bodySchemas{Payload: body, Encoding: base64Encoding}
+	}
+	message := string(body)
+	return bodySchemas{Message: &message}
+}
+
+type putResultSchemas struct {
+	ID string `json:"id"`
+}
+
+type putBatchResultSchemas struct {
+	IDs []string `json:"ids"`
+}
+
+type deliveredBatchSchemas struct {
+	Messages []deliveredSchemas `json:"messages"`
+}
+
+func toDeliveredBatchSchemas(messages []domain.Message) deliveredBatchSchemas {
+	schemas := make([]deliveredSchemas, 0, len(messages))
+	for _, message := range messages {
+		schemas = append(schemas, toDeliveredSchemas(message))
+	}
+	return deliveredBatchSchemas{Messages: schemas}
+}
+
+// batchSchemas Headers и ContentType общие для всех сообщений пачки.
+type batchSchemas struct {
+	Messages    []string          `json:"messages"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	putOptionsSchemas
+}
+
+func (schema batchSchemas) messages() []domain.Message {
+	messages := make([]domain.Message, 0, len(schema.Messages))
+	for _, body := range schema.Messages {
+		message := domain.Message{Body: []byte(body), Headers: schema.Headers, ContentType: schema.ContentType}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// Отложенная доставка: либо через delay секунд, либо в момент deliverAt.
+// TTL в секундах отсчитывается от момента доставки.
+// Priority Чем больше, тем раньше выдается, работает только в очереди с приоритетами.
+type putOptionsSchemas struct {
+	Delay     int        `json:"delay,omitempty"`
+	DeliverAt *time.Time `json:"deliverAt,omitempty"`
+	TTL       int        `json:"ttl,omitempty"`
+	Priority  int        `json:"priority,omitempty"`
+}
+
+func (schema putOptionsSchemas) putOptions() (domain.PutOptions, error) {
+	switch {
+	case schema.Delay < 0:
+		return domain.PutOptions{}, errors.New("delay must not be negative")
+	case schema.TTL < 0:
+		return domain.PutOptions{}, errors.New("ttl must not be negative")
+	case schema.Delay > 0 && schema.DeliverAt != nil:
+		return domain.PutOptions{}, errors.New("only one of delay and deliverAt can be set")
+	}
+	options := domain.PutOptions{Priority: schema.Priority}
+	now := time.Now()
+	if schema.DeliverAt != nil {
+		options.DeliverAt = *schema.DeliverAt
+	}
+	if schema.Delay > 0 {
+		options.DeliverAt = now.Add(time.Second * time.Duration(schema.Delay))
+	}
+	return options.WithDefaultTTL(time.Second*time.Duration(schema.TTL), now), nil
+}
+
+type leaseSchemas struct {
+	deliveredSchemas
+	Receipt           string `json:"receipt"`
+	VisibilityTimeout int    `json:"visibilityTimeout"`
 }
 
 func newGetFromQueueHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
@@ -36,20 +201,126 @@ func newGetFromQueueHandler(queues domain.Queues, logger *slog.Logger) http.Hand
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		queueName := r.PathValue("queue")
-		message, err := queues.GetMessageFromQueue(ctx, queueName) //nolint:contextcheck
+		query := r.URL.Query()
+		switch {
+		case query.Has(rawQueryParamKey) && (query.Has(maxQueryParamKey) || query.Has(groupQueryParamKey)):
+			http.Error(w, "raw is supported only for a single message", http.StatusBadRequest)
+		case query.Has(groupQueryParamKey):
+			readGroupFromQueue(ctx, w, r, queues, logger) //nolint:contextcheck
+		case query.Has(visibilityQueryParamKey) && query.Has(maxQueryParamKey):
+			http.Error(w, "max is not supported with visibility", http.StatusBadRequest)
+		case query.Has(visibilityQueryParamKey):
+			leaseFromQueue(ctx, w, r, queues, logger) //nolint:contextcheck
+		case query.Has(maxQueryParamKey):
+			getBatchFromQueue(ctx, w, r, queues, logger) //nolint:contextcheck
+		default:
+			getFromQueue(ctx, w, r, queues, logger) //nolint:contextcheck
+		}
+	}
+}
+
+func getFromQueue(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	queues domain.Queues,
+	logger *slog.Logger,
+) {
+	message, err := queues.GetMessageFromQueue(ctx, r.PathValue("queue"))
+	if err != nil {
+		writeGetError(w, "get from queue handler", err, logger)
+		return
+	}
+	if message.Raw || r.URL.Query().Has(rawQueryParamKey) {
+		writeRaw(w, message)
+		return
+	}
+	err = json.NewEncoder(w).Encode(toDeliveredSchemas(message))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Отдаем сколько есть, но не больше max, если сообщений нет - ждем первые.
+func getBatchFromQueue(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	queues domain.Queues,
+	logger *slog.Logger,
+) {
+	limit, err := strconv.Atoi(r.URL.Query().Get(maxQueryParamKey))
+	if err != nil || limit <= 0 {
+		http.Error(w, "max must be positive number", http.StatusBadRequest)
+		return
+	}
+	messages, err := queues.GetMessagesFromQueue(ctx, r.PathValue("queue"), limit)
+	if err != nil {
+		writeGetError(w, "get batch from queue handler", err, logger)
+		return
+	}
+	writeJSON(w, toDeliveredBatchSchemas(messages))
+}
+
+// В режиме аренды сообщение не пропадает, пока клиент не подтвердит его через ack.
+func leaseFromQueue(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	queues domain.Queues,
+	logger *slog.Logger,
+) {
+	visibilitySecond, err := strconv.Atoi(r.URL.Query().Get(visibilityQueryParamKey))
+	if err != nil || visibilitySecond <= 0 {
+		http.Error(w, "visibility must be positive number of seconds", http.StatusBadRequest)
+		return
+	}
+	visibilityTimeout := time.Second * time.Duration(visibilitySecond)
+	lease, err := queues.LeaseMessageFromQueue(ctx, r.PathValue("queue"), visibilityTimeout)
+	if err != nil {
+		writeGetError(w, "lease from queue handler", err, logger)
+		return
+	}
+	if lease.Message.Raw || r.URL.Query().Has(rawQueryParamKey) {
+		writeRawLease(w, lease, visibilitySecond)
+		return
+	}
+	err = json.NewEncoder(w).Encode(leaseSchemas{
+		deliveredSchemas:  toDeliveredSchemas(lease.Message),
+		Receipt:           lease.Receipt,
+		VisibilityTimeout: visibilitySecond,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func newAckHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return newReceiptHandler("ack handler", queues.AckMessage, logger)
+}
+
+// Nack он же reject: сообщение возвращается в очередь, попытка доставки засчитывается.
+func newNackHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return newReceiptHandler("nack handler", queues.NackMessage, logger)
+}
+
+// Ack и Nack отличаются только действием над арендой.
+func newReceiptHandler(
+	name string,
+	action func(queueName string, receipt string) error,
+	logger *slog.Logger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := action(r.PathValue("queue"), r.PathValue("receipt"))
 		if err != nil {
-			if errors.Is(err, domain.ErrMessageWaitTimeOut) {
+			if errors.Is(err, domain.ErrReceiptNotFound) {
 				http.Error(w, err.Error(), http.StatusNotFound)
 				return
 			}
-			logger.Error(fmt.Errorf("get from queue handler: %w", err).Error())
 			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		err = json.NewEncoder(w).Encode(messageSchemas{Message: message})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			logger.Error(fmt.Errorf("%s: %w", name, err).Error())
 			return
 		}
 	}
@@ -73,22 +344,108 @@ func makeCtx(r *http.Request) (context.Context, func(), error) {
 
 func newPutToQueueHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		queueName := r.PathValue("queue")
-		var schema messageSchemas
+		message, options, err := decodePut(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		options.IdempotencyKey = r.Header.Get(idempotencyKeyHeader)
+		id, err := queues.PutMessageToQueue(r.Context(), r.PathValue("queue"), message, options)
+		if err != nil {
+			writePutError(w, "put to queue handler", err, logger)
+			return
+		}
+		writeJSON(w, putResultSchemas{ID: id})
+	}
+}
+
+// Пачка кладется целиком или не кладется вовсе.
+func newPutBatchToQueueHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var schema batchSchemas
 		err := json.NewDecoder(r.Body).Decode(&schema)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		err = queues.PutMessageToQueue(r.Context(), queueName, schema.Message)
+		if len(schema.Messages) == 0 {
+			http.Error(w, "messages must not be empty", http.StatusBadRequest)
+			return
+		}
+		options, err := schema.putOptions()
 		if err != nil {
-			if errors.Is(err, domain.ErrMaxCountQueuesCount) {
-				http.Error(w, err.Error(), http.StatusTooManyRequests)
-				return
-			}
-			w.WriteHeader(http.StatusInternalServerError)
-			logger.Error(fmt.Errorf("put to queue handler: %w", err).Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		options.IdempotencyKey = r.Header.Get(idempotencyKeyHeader)
+		ids, err := queues.PutMessagesToQueue(r.Context(), r.PathValue("queue"), schema.messages(), options)
+		if err != nil {
+			writePutError(w, "put batch to queue handler", err, logger)
+			return
+		}
+		writeJSON(w, putBatchResultSchemas{IDs: ids})
+	}
+}
+
+func writeGetError(w http.ResponseWriter, name string, err error, logger *slog.Logger) {
+	status, known := getErrorStatus(err)
+	if known {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	logger.Error(fmt.Errorf("%s: %w", name, err).Error())
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+func writePutError(w http.ResponseWriter, name string, err error, logger *slog.Logger) {
+	status, known := putErrorStatus(err)
+	if known {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	logger.Error(fmt.Errorf("%s: %w", name, err).Error())
+}
+
+// getErrorStatus Ошибки чтения, которые отдаем клиенту с понятным статусом, остальные - 500.
+func getErrorStatus(err error) (int, bool) {
+	return matchErrorStatus(
+		err,
+		errorStatus{domain.ErrMessageWaitTimeOut, http.StatusNotFound},
+		errorStatus{domain.ErrQueueNotFound, http.StatusNotFound},
+		errorStatus{domain.ErrQueueClosed, http.StatusGone},
+		errorStatus{domain.ErrShuttingDown, http.StatusServiceUnavailable},
+		errorStatus{domain.ErrGroupRequired, http.StatusBadRequest},
+		errorStatus{domain.ErrNotALog, http.StatusBadRequest},
+		errorStatus{domain.ErrInvalidOffset, http.StatusBadRequest},
+	)
+}
+
+// putErrorStatus Ошибки записи, которые отдаем клиенту с понятным статусом, остальные - 500.
+func putErrorStatus(err error) (int, bool) {
+	return matchErrorStatus(
+		err,
+		errorStatus{domain.ErrMaxCountQueuesCount, http.StatusTooManyRequests},
+		errorStatus{domain.ErrQueueFull, http.StatusInsufficientStorage},
+		errorStatus{domain.ErrQueueClosed, http.StatusGone},
+		errorStatus{domain.ErrMessageTooLarge, http.StatusRequestEntityTooLarge},
+		errorStatus{domain.ErrQueueNotFound, http.StatusNotFound},
+		errorStatus{domain.ErrShuttingDown, http.StatusServiceUnavailable},
+		errorStatus{domain.ErrNotSupported, http.StatusBadRequest},
+		errorStatus{domain.ErrIdempotencyKeyInUse, http.StatusConflict},
+	)
+}
+
+type errorStatus struct {
+	err    error
+	status int
+}
+
+func matchErrorStatus(err error, known ...errorStatus) (int, bool) {
+	for _, candidate := range known {
+		if errors.Is(err, candidate.err) {
+			return candidate.status, true
+		}
 	}
+	return 0, false
 }
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/infrastructure/metrics"
+)
+
+// Метрики в формате Prometheus: запросы по ручкам и состояние очередей на момент отдачи.
+
+type httpMetrics struct {
+	registry *metrics.Registry
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	wait     *metrics.HistogramVec
+}
+
+func newHTTPMetrics(queues domain.Queues) *httpMetrics {
+	const (
+		bucketStart  = 0.005
+		bucketFactor = 2
+		bucketCount  = 14
+	)
+	registry := metrics.NewRegistry()
+	buckets := metrics.ExponentialBuckets(bucketStart, bucketFactor, bucketCount)
+	httpMetricsInstance := &httpMetrics{
+		registry: registry,
+		requests: registry.NewCounterVec("http_requests_total", "HTTP requests by handler and status code.",
+			"handler", "code"),
+		duration: registry.NewHistogramVec("http_request_duration_seconds", "HTTP request latency by handler.",
+			buckets, "handler"),
+		wait: registry.NewHistogramVec("queue_wait_seconds", "Time consumers waited for messages.",
+			buckets, "queue"),
+	}
+	registerQueueMetrics(registry, queues)
+	return httpMetricsInstance
+}
+
+// registerQueueMetrics Значения берутся из статистики очередей, отдельно ничего не считаем.
+func registerQueueMetrics(registry *metrics.Registry, queues domain.Queues) {
+	gauge := func(value func(stats domain.QueueStats) int) metrics.Collect {
+		return func(emit func(value float64, labelValues ...string)) {
+			for _, info := range queues.ListQueues() {
+				emit(float64(value(info.QueueStats)), info.Name)
+			}
+		}
+	}
+	registry.NewGaugeFunc("queue_depth", "Messages ready for delivery.",
+		gauge(func(stats domain.QueueStats) int { return stats.Depth }), "queue")
+	registry.NewGaugeFunc("queue_waiting_consumers", "Consumers waiting for messages.",
+		gauge(func(stats domain.QueueStats) int { return stats.Waiting }), "queue")
+	registry.NewGaugeFunc("queue_in_flight", "Leased messages waiting for ack.",
+		gauge(func(stats domain.QueueStats) int { return stats.InFlight }), "queue")
+	registry.NewGaugeFunc("queue_delayed", "Messages waiting for delayed delivery.",
+		gauge(func(stats domain.QueueStats) int { return stats.Delayed }), "queue")
+	registry.NewCounterFunc("queue_enqueued_total", "Messages accepted by queue.",
+		gauge(func(stats domain.QueueStats) int { return stats.Enqueued }), "queue")
+	registry.NewCounterFunc("queue_dequeued_total", "Messages handed to consumers.",
+		gauge(func(stats domain.QueueStats) int { return stats.Dequeued }), "queue")
+	registry.NewCounterFunc("queue_expired_total", "Messages dropped after TTL.",
+		gauge(func(stats domain.QueueStats) int { return stats.Expired }), "queue")
+}
+
+// instrument Handler - шаблон маршрута, а не путь, иначе метрик будет столько же, сколько очередей.
+func (m *httpMetrics) instrument(handler string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r)
+		m.requests.Inc(handler, strconv.Itoa(recorder.status))
+		m.duration.Observe(time.Since(start).Seconds(), handler)
+	}
+}
+
+func newMetricsHandler(m *httpMetrics, queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		m.pruneWait(queues)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		err := m.registry.Write(w)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap Нужен http.ResponseController, чтобы добраться до Flusher и прочего у исходного writer.
+func (recorder *statusRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
+
+// waitObservedQueues Замеряет, сколько получатели ждали сообщения, остальное отдает как есть.
+type waitObservedQueues struct {
+	domain.Queues
+	wait *metrics.HistogramVec
+}
+
+func (queues waitObservedQueues) GetMessageFromQueue(ctx context.Context, name string) (domain.Message, error) {
+	start := time.Now()
+	message, err := queues.Queues.GetMessageFromQueue(ctx, name)
+	queues.observe(name, start, err)
+	return message, err //nolint:wrapcheck
+}
+
+func (queues waitObservedQueues) GetMessagesFromQueue(
+	ctx context.Context,
+	name string,
+	limit int,
+) ([]domain.Message, error) {
+	start := time.Now()
+	messages, err := queues.Queues.GetMessagesFromQueue(ctx, name, limit)
+	queues.observe(name, start, err)
+	return messages, err //nolint:wrapcheck
+}
+
+func (queues waitObservedQueues) LeaseMessageFromQueue(
+	ctx context.Context,
+	name string,
+	visibilityTimeout time.Duration,
+) (domain.Lease[domain.Message], error) {
+	start := time.Now()
+	lease, err := queues.Queues.LeaseMessageFromQueue(ctx, name, visibilityTimeout)
+	queues.observe(name, start, err)
+	return lease, err //nolint:wrapcheck
+}
+
+// observe Учитываем только ожидание в найденной очереди: дождались сообщения или не дождались за timeout.
+// При остальных ошибках очереди могло и не быть, а любое имя из запроса не должно становиться серией.
+func (queues waitObservedQueues) observe(name string, start time.Time, err error) {
+	if err != nil && !errors.Is(err, domain.ErrMessageWaitTimeOut) {
+		return
+	}
+	queues.wait.Observe(time.Since(start).Seconds(), name)
+}
+
+// DeleteQueue Вместе с очередью удаляем и ее серию ожидания.
+func (queues waitObservedQueues) DeleteQueue(name string) error {
+	err := queues.Queues.DeleteQueue(name)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	queues.wait.Delete(name)
+	return nil
+}
+
+// pruneWait Убирает серии ожидания очередей, которых больше нет, например удаленных janitor.
+func (m *httpMetrics) pruneWait(queues domain.Queues) {
+	names := make(map[string]struct{})
+	for _, info := range queues.ListQueues() {
+		names[info.Name] = struct{}{}
+	}
+	m.wait.DeleteFunc(func(labelValues []string) bool {
+		_, exist := names[labelValues[0]]
+		return !exist
+	})
+}
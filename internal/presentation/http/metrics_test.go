@@ -0,0 +1,92 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kukwuka/queue/internal/domain"
+	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+func (s *handlerTestSuite) TestMetricsHandler_QueuesAndRequests() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(context.Background(), queueName).
+		Return(domain.Message{Body: []byte("message")}, nil)
+	queuesInstance.
+		EXPECT().
+		ListQueues().
+		Return([]domain.QueueInfo{{
+			Name:       queueName,
+			QueueStats: domain.QueueStats{Depth: 1, Waiting: 2, Enqueued: 7, Dequeued: 6},
+		}})
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, err = http.NewRequest(http.MethodGet, "/metrics", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	body := response.Body.String()
+	s.Contains(body, "http_requests_total{handler=\"GET /queue/{queue}\",code=\"200\"} 1\n")
+	s.Contains(body, "http_request_duration_seconds_count{handler=\"GET /queue/{queue}\"} 1\n")
+	s.Contains(body, "queue_wait_seconds_count{queue=\"test\"} 1\n")
+	s.Contains(body, "queue_depth{queue=\"test\"} 1\n")
+	s.Contains(body, "queue_waiting_consumers{queue=\"test\"} 2\n")
+	s.Contains(body, "queue_enqueued_total{queue=\"test\"} 7\n")
+	s.Contains(body, "queue_dequeued_total{queue=\"test\"} 6\n")
+	s.Zero(buffer.String())
+}
+
+// Серия ожидания появляется только у найденной очереди и пропадает после ее удаления или вытеснения janitor.
+func (s *handlerTestSuite) TestMetricsHandler_WaitSeriesBounded() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(context.Background(), "overflow").
+		Return(domain.Message{}, domain.ErrMaxCountQueuesCount)
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(context.Background(), mock.Anything).
+		Return(domain.Message{}, domain.ErrMessageWaitTimeOut)
+	queuesInstance.
+		EXPECT().
+		DeleteQueue("deleted").
+		Return(nil)
+	queuesInstance.
+		EXPECT().
+		ListQueues().
+		Return([]domain.QueueInfo{{Name: "kept"}})
+	logger := slog.New(slog.NewJSONHandler(bytes.NewBuffer(nil), nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	serve := func(method string, target string) string {
+		req, err := http.NewRequest(method, target, bytes.NewBuffer(nil))
+		s.Require().NoError(err)
+		response := httptest.NewRecorder()
+		mux.ServeHTTP(response, req)
+		return response.Body.String()
+	}
+
+	for _, name := range []string{"overflow", "kept", "deleted", "evicted"} {
+		serve(http.MethodGet, "/queue/"+name)
+	}
+	serve(http.MethodDelete, "/queue/deleted")
+	body := serve(http.MethodGet, "/metrics")
+	s.Contains(body, "queue_wait_seconds_count{queue=\"kept\"} 1\n")
+	s.NotContains(body, "queue_wait_seconds_count{queue=\"overflow\"}")
+	s.NotContains(body, "queue_wait_seconds_count{queue=\"deleted\"}")
+	s.NotContains(body, "queue_wait_seconds_count{queue=\"evicted\"}")
+}
@@ -3,15 +3,26 @@ package http
 import (
 	"context"
 	"net/http"
+	"path"
 	"time"
 )
 
 type Middleware func(next http.Handler) http.Handler
 
+// NewTimeoutMiddleware Поток и WebSocket живут, пока их держит клиент, им таймаут не ставим.
 func NewTimeoutMiddleware(next http.Handler, timeout time.Duration) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isLongLived(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		ctx, cancel := context.WithTimeout(r.Context(), timeout)
 		defer cancel()
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func isLongLived(r *http.Request) bool {
+	stream, _ := path.Match("/queue/*/stream", r.URL.Path)
+	return r.Method == http.MethodGet && (stream || r.URL.Path == "/ws")
+}
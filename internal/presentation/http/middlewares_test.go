@@ -33,6 +33,22 @@ func (s *handlerTestSuite) TestTimeOutMiddleware() {
 	appHTTP.NewTimeoutMiddleware(handler, 100*time.Millisecond).ServeHTTP(response, req)
 }
 
+func (s *handlerTestSuite) TestTimeOutMiddleware_SkipsStream() {
+	var handler http.HandlerFunc = func(_ http.ResponseWriter, request *http.Request) {
+		_, hasDeadline := request.Context().Deadline()
+		s.False(hasDeadline)
+	}
+	req, err := http.NewRequestWithContext(
+		context.Background(),
+		http.MethodGet,
+		"/queue/"+queueName+"/stream",
+		bytes.NewBuffer(nil),
+	)
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+	appHTTP.NewTimeoutMiddleware(handler, 100*time.Millisecond).ServeHTTP(response, req)
+}
+
 func TestMiddlewares(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, new(middlewaresTestSuite))
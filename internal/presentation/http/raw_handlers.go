@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Сообщение с типом содержимого не JSON (protobuf, картинка) кладется как есть, без обертки в JSON,
+// и GET отдает его так же, метаданные при этом ходят в заголовках. С ?raw так отдается любое сообщение.
+const (
+	contentTypeHeader = "Content-Type"
+	jsonContentType   = "application/json"
+	// octetStreamContentType Тип тела в ?raw, если у сообщения его нет.
+	octetStreamContentType = "application/octet-stream"
+	// textContentType Его по умолчанию ставит fetch в браузере, см. isEnvelope.
+	textContentType = "text/plain"
+	// formContentType Его ставит curl -d, такие тела по-прежнему разбираем как JSON.
+	formContentType = "application/x-www-form-urlencoded"
+	// messageHeaderPrefix Message-Header-Trace: 1 - заголовок сообщения Trace со значением 1.
+	messageHeaderPrefix     = "Message-Header-"
+	messageIDHeader         = "Message-Id"
+	enqueuedAtHeader        = "Message-Enqueued-At"
+	deliveryCountHeader     = "Message-Delivery-Count"
+	receiptHeader           = "Lease-Receipt"
+	visibilityTimeoutHeader = "Lease-Visibility-Timeout"
+)
+
+// isRaw Тело PUT без типа содержимого и в JSON разбираем как раньше, как JSON с полем message.
+func isRaw(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+	return mediaType != jsonContentType && mediaType != formContentType
+}
+
+// decodePut Тело не в JSON кладем как есть и без настроек доставки, они задаются только в JSON.
+func decodePut(r *http.Request) (domain.Message, domain.PutOptions, error) {
+	contentType := r.Header.Get(contentTypeHeader)
+	if !isRaw(contentType) {
+		return decodeEnvelope(r.Body)
+	}
+	message, err := rawMessage(r)
+	if err != nil {
+		return domain.Message{}, domain.PutOptions{}, err
+	}
+	if isEnvelope(contentType, message.Body) {
+		return decodeEnvelope(bytes.NewReader(message.Body))
+	}
+	message.Raw = true
+	return message, domain.PutOptions{}, nil
+}
+
+func decodeEnvelope(body io.Reader) (domain.Message, domain.PutOptions, error) {
+	var schema messageSchemas
+	err := json.NewDecoder(body).Decode(&schema)
+	if err != nil {
+		return domain.Message{}, domain.PutOptions{}, err //nolint:wrapcheck
+	}
+	options, err := schema.putOptions()
+	return schema.message(), options, err
+}
+
+// isEnvelope Совместимость: text/plain и тип, который не разобрать, раньше разбирались как JSON.
+// Если тело с таким типом - JSON-объект с полем message, это по-прежнему конверт, иначе тело кладется как есть.
+func isEnvelope(contentType string, body []byte) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && mediaType != textContentType {
+		return false
+	}
+	var envelope struct {
+		Message *string `json:"message"`
+	}
+	return json.Unmarshal(body, &envelope) == nil && envelope.Message != nil
+}
+
+// rawMessage Заголовки запроса Message-Header-* становятся заголовками сообщения.
+func rawMessage(r *http.Request) (domain.Message, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("read body: %w", err)
+	}
+	var headers map[string]string
+	for key, values := range r.Header {
+		name, found := strings.CutPrefix(key, messageHeaderPrefix)
+		if !found || name == "" {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[name] = values[0]
+	}
+	return domain.Message{Body: body, Headers: headers, ContentType: r.Header.Get(contentTypeHeader)}, nil
+}
+
+// writeRaw Тело байт в байт с тем же типом содержимого, с которым его положили, без него - octet-stream.
+func writeRaw(w http.ResponseWriter, message domain.Message) {
+	header := w.Header()
+	header.Set(contentTypeHeader, cmp.Or(message.ContentType, octetStreamContentType))
+	header.Set(messageIDHeader, message.ID)
+	header.Set(enqueuedAtHeader, message.EnqueuedAt.Format(time.RFC3339Nano))
+	header.Set(deliveryCountHeader, strconv.Itoa(message.DeliveryCount))
+	for name, value := range message.Headers {
+		header.Set(messageHeaderPrefix+name, value)
+	}
+	_, _ = w.Write(message.Body)
+}
+
+func writeRawLease(w http.ResponseWriter, lease domain.Lease[domain.Message], visibilitySecond int) {
+	w.Header().Set(receiptHeader, lease.Receipt)
+	w.Header().Set(visibilityTimeoutHeader, strconv.Itoa(visibilitySecond))
+	writeRaw(w, lease.Message)
+}
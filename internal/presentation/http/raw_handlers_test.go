@@ -0,0 +1,354 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/infrastructure/websocket"
+	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+// payload Не UTF-8, в JSON такое тело исказилось бы.
+var payload = []byte{0x0a, 0x03, 0xff, 0x00, 0xfe}
+
+func (s *handlerTestSuite) TestPutToQueueHandler_Raw() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(payload))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Message-Header-Trace", "1")
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	message := domain.Message{
+		Body:        payload,
+		Headers:     map[string]string{"Trace": "1"},
+		ContentType: "application/x-protobuf",
+		Raw:         true,
+	}
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, message, domain.PutOptions{}).
+		Return("id", nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"id": "id"}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+// Curl -d ставит application/x-www-form-urlencoded, такое тело по-прежнему JSON.
+func (s *handlerTestSuite) TestPutToQueueHandler_FormIsJSON() {
+	ctx := context.Background()
+	body := []byte(`{"message": "message"}`)
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(ctx, queueName, domain.Message{Body: []byte("message")}, domain.PutOptions{}).
+		Return("id", nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+}
+
+// Совместимость: text/plain раньше разбирался как JSON, JSON-объект с message по-прежнему конверт.
+func (s *handlerTestSuite) TestPutToQueueHandler_TextPlainEnvelope() {
+	s.putWithContentType("text/plain;charset=UTF-8", `{"message": "message", "priority": 1}`,
+		domain.Message{Body: []byte("message")}, domain.PutOptions{Priority: 1})
+}
+
+// Остальной текст кладется как есть вместе с типом содержимого.
+func (s *handlerTestSuite) TestPutToQueueHandler_TextPlainRaw() {
+	s.putWithContentType("text/plain", `{"text": "message"}`,
+		domain.Message{Body: []byte(`{"text": "message"}`), ContentType: "text/plain", Raw: true}, domain.PutOptions{})
+}
+
+func (s *handlerTestSuite) putWithContentType(
+	contentType string,
+	body string,
+	expected domain.Message,
+	options domain.PutOptions,
+) {
+	req, err := http.NewRequest(http.MethodPut, "/queue/"+queueName, strings.NewReader(body))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", contentType)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(mock.Anything, queueName, expected, options).
+		Return("id", nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+}
+
+// Положенное как есть сообщение и отдается как есть, без ?raw.
+func (s *handlerTestSuite) TestGetFromQueueHandler_Raw() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName, bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	message := domain.Message{
+		ID:            "id",
+		Body:          payload,
+		Headers:       map[string]string{"Trace": "1"},
+		ContentType:   "application/x-protobuf",
+		Raw:           true,
+		EnqueuedAt:    enqueuedAt,
+		DeliveryCount: 2,
+	}
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(ctx, queueName).
+		Return(message, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal(payload, response.Body.Bytes())
+	s.Equal("application/x-protobuf", response.Header().Get("Content-Type"))
+	s.Equal("id", response.Header().Get("Message-Id"))
+	s.Equal("2024-05-01T10:00:00Z", response.Header().Get("Message-Enqueued-At"))
+	s.Equal("2", response.Header().Get("Message-Delivery-Count"))
+	s.Equal("1", response.Header().Get("Message-Header-Trace"))
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_RawLease() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"?visibility=30", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	lease := domain.Lease[domain.Message]{
+		Message: domain.Message{
+			ID:            "id",
+			Body:          payload,
+			ContentType:   "application/octet-stream",
+			Raw:           true,
+			DeliveryCount: 1,
+		},
+		Receipt: "receipt",
+	}
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(ctx, queueName, 30*time.Second).
+		Return(lease, nil)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal(payload, response.Body.Bytes())
+	s.Equal("application/octet-stream", response.Header().Get("Content-Type"))
+	s.Equal("receipt", response.Header().Get("Lease-Receipt"))
+	s.Equal("30", response.Header().Get("Lease-Visibility-Timeout"))
+	s.Zero(buffer.String())
+}
+
+// Положенное в JSON сообщение без ?raw приходит в JSON, даже с типом содержимого, а тело не UTF-8 - в payload.
+func (s *handlerTestSuite) TestGetFromQueueHandler_EnvelopeKept() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(mock.Anything, queueName).
+		Return(domain.Message{ID: "id", Body: payload, ContentType: "application/x-protobuf"}, nil)
+	response := s.serveBinary(queuesInstance, "/queue/"+queueName)
+
+	var message binarySchemas
+	s.Require().NoError(json.Unmarshal(response.Body.Bytes(), &message))
+	s.binaryEqual(payload, message)
+}
+
+// Положенное в JSON сообщение без типа содержимого в ?raw отдается как application/octet-stream.
+func (s *handlerTestSuite) TestGetFromQueueHandler_RawWithoutContentType() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessageFromQueue(mock.Anything, queueName).
+		Return(domain.Message{ID: "id", Body: []byte("message")}, nil)
+	response := s.serveBinary(queuesInstance, "/queue/"+queueName+"?raw")
+	s.Equal("message", response.Body.String())
+	s.Equal("application/octet-stream", response.Header().Get("Content-Type"))
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_ErrRawWithMax() {
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"?raw&max=2", nil)
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Equal("raw is supported only for a single message\n", response.Body.String())
+	s.Zero(buffer.String())
+}
+
+// binarySchemas Тело не UTF-8 в JSON ответах: base64 в payload и encoding: base64, без message.
+type binarySchemas struct {
+	Message  *string `json:"message"`
+	Payload  []byte  `json:"payload"`
+	Encoding string  `json:"encoding"`
+}
+
+func (s *handlerTestSuite) binaryEqual(expected []byte, actual binarySchemas) {
+	s.Nil(actual.Message)
+	s.Equal("base64", actual.Encoding)
+	s.Equal(expected, actual.Payload)
+}
+
+func (s *handlerTestSuite) serveBinary(queuesInstance domain.Queues, target string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Zero(buffer.String())
+	return response
+}
+
+func (s *handlerTestSuite) TestGetFromQueueHandler_BatchBinary() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		GetMessagesFromQueue(mock.Anything, queueName, 2).
+		Return([]domain.Message{{ID: "1", Body: payload}, {ID: "2", Body: []byte("text")}}, nil)
+	response := s.serveBinary(queuesInstance, "/queue/"+queueName+"?max=2")
+
+	var batch struct {
+		Messages []binarySchemas `json:"messages"`
+	}
+	s.Require().NoError(json.Unmarshal(response.Body.Bytes(), &batch))
+	s.Require().Len(batch.Messages, 2)
+	s.binaryEqual(payload, batch.Messages[0])
+	s.Require().NotNil(batch.Messages[1].Message)
+	s.Equal("text", *batch.Messages[1].Message)
+	s.Zero(batch.Messages[1].Encoding)
+}
+
+func (s *handlerTestSuite) TestPeekQueueHandler_Binary() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PeekQueue(queueName, 1).
+		Return([]domain.Message{{ID: "1", Body: payload}}, nil)
+	response := s.serveBinary(queuesInstance, "/queue/"+queueName+"/peek?limit=1")
+
+	var peek struct {
+		Messages []binarySchemas `json:"messages"`
+	}
+	s.Require().NoError(json.Unmarshal(response.Body.Bytes(), &peek))
+	s.Require().Len(peek.Messages, 1)
+	s.binaryEqual(payload, peek.Messages[0])
+}
+
+func (s *handlerTestSuite) TestReadGroupHandler_Binary() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		ReadGroupFromQueue(mock.Anything, queueName, "billing", 1).
+		Return([]domain.LogRecord[domain.Message]{{Offset: 4, Message: domain.Message{ID: "a", Body: payload}}}, nil)
+	response := s.serveBinary(queuesInstance, "/queue/"+queueName+"?group=billing")
+
+	var read struct {
+		Records []binarySchemas `json:"records"`
+	}
+	s.Require().NoError(json.Unmarshal(response.Body.Bytes(), &read))
+	s.Require().Len(read.Records, 1)
+	s.binaryEqual(payload, read.Records[0])
+}
+
+func (s *handlerTestSuite) TestStreamHandler_Binary() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(mock.Anything, queueName, mock.Anything).
+		Return(domain.Lease[domain.Message]{Message: domain.Message{ID: "id", Body: payload}, Receipt: "receipt"}, nil).
+		Once()
+	queuesInstance.
+		EXPECT().
+		AckMessage(queueName, "receipt").
+		Return(nil).
+		Once()
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(mock.Anything, queueName, mock.Anything).
+		Return(domain.Lease[domain.Message]{}, domain.ErrShuttingDown).
+		Once()
+	response := s.serveBinary(queuesInstance, "/queue/"+queueName+"/stream")
+
+	event, _, found := strings.Cut(response.Body.String(), "\n\n")
+	s.Require().True(found)
+	data, found := strings.CutPrefix(event, "event: binary\nid: id\ndata: ")
+	s.Require().True(found)
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	s.Require().NoError(err)
+	s.Equal(payload, decoded)
+}
+
+func (s *handlerTestSuite) TestWebSocket_Binary() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(mock.Anything, queueName, mock.Anything).
+		Return(domain.Lease[domain.Message]{Message: domain.Message{ID: "id", Body: payload}, Receipt: "receipt"}, nil)
+	queuesInstance.
+		EXPECT().
+		AckMessage(queueName, "receipt").
+		Return(nil)
+	conn, closeServer := s.dialWebSocket(queuesInstance)
+	defer closeServer()
+	defer conn.Close(websocket.CloseNormal, "")
+
+	reply := s.wsRoundTrip(conn, `{"id": "1", "op": "get", "queue": "test"}`)
+	s.NotContains(reply, "message")
+	s.Equal("base64", reply["encoding"])
+	encoded, _ := reply["payload"].(string)
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	s.Require().NoError(err)
+	s.Equal(payload, decoded)
+	s.Equal(
+		map[string]any{"id": "2", "op": "ok"},
+		s.wsRoundTrip(conn, `{"id": "2", "op": "ack", "queue": "test", "receipt": "receipt"}`),
+	)
+}
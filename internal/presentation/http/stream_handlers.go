@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Поток сообщений по Server-Sent Events: одно соединение вместо запроса на каждое сообщение.
+// Поток ждет сообщения в общей очереди ожидания наравне с обычными GET.
+
+// streamVisibility Сколько сообщение может отправляться клиенту, потом его получит кто-то другой.
+const streamVisibility = 30 * time.Second
+
+// Следующее сообщение берем, только когда предыдущее ушло клиенту, поэтому медленный клиент
+// держит не больше одного сообщения. Пока сообщение отправляется, оно в аренде,
+// если отправить не удалось - возвращается в очередь.
+func newStreamHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stream := &eventStream{w: w, controller: http.NewResponseController(w)}
+		queueName := r.PathValue("queue")
+		for {
+			err := streamMessage(r.Context(), stream, queues, queueName)
+			if err != nil {
+				stream.fail(err, logger)
+				return
+			}
+		}
+	}
+}
+
+func streamMessage(ctx context.Context, stream *eventStream, queues domain.Queues, queueName string) error {
+	lease, err := queues.LeaseMessageFromQueue(ctx, queueName, streamVisibility)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	event, data := messageEvent(lease.Message.Body)
+	err = stream.send(event, lease.Message.ID, data)
+	if err != nil {
+		return errors.Join(errStreamBroken, err, queues.NackMessage(queueName, lease.Receipt))
+	}
+	err = queues.AckMessage(queueName, lease.Receipt)
+	// Аренда успела истечь, сообщение уже вернулось в очередь и придет еще раз, это at-least-once.
+	if errors.Is(err, domain.ErrReceiptNotFound) {
+		return nil
+	}
+	return err //nolint:wrapcheck
+}
+
+// messageEvent Тело-текст приходит событием message, остальное - событием binary с телом в base64.
+func messageEvent(body []byte) (string, string) {
+	if !utf8.Valid(body) {
+		return "binary", base64.StdEncoding.EncodeToString(body)
+	}
+	return "message", string(body)
+}
+
+// errStreamBroken Клиенту уже ничего не отправить, например он отключился.
+var errStreamBroken = errors.New("stream is broken")
+
+// eventStream Заголовки отправляем вместе с первым событием, до этого ошибку можно отдать обычным статусом.
+type eventStream struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	started    bool
+}
+
+// send Многострочные данные разбиваем на несколько data, как того требует формат.
+// Id сообщения уходит в поле id, пустой не отправляем.
+func (stream *eventStream) send(event string, id string, data string) error {
+	if !stream.started {
+		stream.w.Header().Set("Content-Type", "text/event-stream")
+		stream.w.Header().Set("Cache-Control", "no-cache")
+		stream.w.WriteHeader(http.StatusOK)
+		stream.started = true
+	}
+	var payload strings.Builder
+	payload.WriteString("event: " + event + "\n")
+	if id != "" {
+		payload.WriteString("id: " + id + "\n")
+	}
+	for _, line := range strings.Split(data, "\n") {
+		payload.WriteString("data: " + line + "\n")
+	}
+	payload.WriteString("\n")
+	_, err := stream.w.Write([]byte(payload.String()))
+	if err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return stream.controller.Flush() //nolint:wrapcheck
+}
+
+// fail Клиент ушел - молча заканчиваем, иначе сообщаем причину: статусом или, если поток уже идет, событием error.
+func (stream *eventStream) fail(err error, logger *slog.Logger) {
+	if errors.Is(err, errStreamBroken) || errors.Is(err, domain.ErrMessageWaitTimeOut) {
+		return
+	}
+	if !stream.started {
+		writeGetError(stream.w, "stream handler", err, logger)
+		return
+	}
+	_, known := getErrorStatus(err)
+	if !known {
+		logger.Error(fmt.Errorf("stream handler: %w", err).Error())
+		return
+	}
+	_ = stream.send("error", "", err.Error())
+}
@@ -0,0 +1,77 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kukwuka/queue/internal/domain"
+	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+// Сообщение подтверждается после отправки, при остановке поток заканчивается событием error.
+func (s *handlerTestSuite) TestStreamHandler_SendsUntilShutdown() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"/stream", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(ctx, queueName, mock.Anything).
+		Return(domain.Lease[domain.Message]{
+			Message: domain.Message{ID: "id", Body: []byte("first\nline")},
+			Receipt: "receipt",
+		}, nil).
+		Once()
+	queuesInstance.
+		EXPECT().
+		AckMessage(queueName, "receipt").
+		Return(nil).
+		Once()
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(ctx, queueName, mock.Anything).
+		Return(domain.Lease[domain.Message]{}, domain.ErrShuttingDown).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("text/event-stream", response.Header().Get("Content-Type"))
+	s.Equal(
+		"event: message\nid: id\ndata: first\ndata: line\n\nevent: error\ndata: shutting down\n\n",
+		response.Body.String(),
+	)
+	s.Zero(buffer.String())
+}
+
+// До первого сообщения ошибку отдаем обычным статусом.
+func (s *handlerTestSuite) TestStreamHandler_ErrQueueNotFound() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodGet, "/queue/"+queueName+"/stream", bytes.NewBuffer(nil))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(ctx, queueName, mock.Anything).
+		Return(domain.Lease[domain.Message]{}, domain.ErrQueueNotFound)
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusNotFound, response.Code)
+	s.Equal("queue not found\n", response.Body.String())
+	s.Zero(buffer.String())
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kukwuka/queue/internal/domain"
+)
+
+// Топики: сообщение топика копируется в каждую подписанную очередь, читают его из очередей как обычно.
+
+type subscriptionSchemas struct {
+	Queue string `json:"queue"`
+}
+
+type subscriptionsSchemas struct {
+	Queues []string `json:"queues"`
+}
+
+// publishSchemas Error заполнен, если положить удалось не во все очереди.
+type publishSchemas struct {
+	ID        string `json:"id"`
+	Delivered int    `json:"delivered"`
+	Error     string `json:"error,omitempty"`
+}
+
+func newSubscribeHandler(topics domain.Topics, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var schema subscriptionSchemas
+		err := json.NewDecoder(r.Body).Decode(&schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if schema.Queue == "" {
+			http.Error(w, "queue must not be empty", http.StatusBadRequest)
+			return
+		}
+		err = topics.Subscribe(r.PathValue("topic"), schema.Queue)
+		if err != nil {
+			logger.Error(fmt.Errorf("subscribe handler: %w", err).Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func newUnsubscribeHandler(topics domain.Topics, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := topics.Unsubscribe(r.PathValue("topic"), r.PathValue("queue"))
+		switch {
+		case errors.Is(err, domain.ErrSubscriptionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err != nil:
+			logger.Error(fmt.Errorf("unsubscribe handler: %w", err).Error())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+func newSubscriptionsHandler(topics domain.Topics, _ *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, subscriptionsSchemas{Queues: topics.Subscriptions(r.PathValue("topic"))})
+	}
+}
+
+// Тело и Idempotency-Key как у PUT в очередь. Не положили никуда - статус как у PUT в очередь,
+// положили не во все очереди - 207 с причиной, повтор без ключа продублирует сообщение в тех, куда уже положили.
+func newPublishHandler(topics domain.Topics, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var schema messageSchemas
+		err := json.NewDecoder(r.Body).Decode(&schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		options, err := schema.putOptions()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		options.IdempotencyKey = r.Header.Get(idempotencyKeyHeader)
+		published, err := topics.Publish(r.Context(), r.PathValue("topic"), schema.message(), options)
+		writePublishResult(w, "publish handler", published, err, logger)
+	}
+}
+
+func writePublishResult(
+	w http.ResponseWriter,
+	name string,
+	published domain.Published,
+	err error,
+	logger *slog.Logger,
+) {
+	if err != nil && published.Delivered == 0 {
+		writePutError(w, name, err, logger)
+		return
+	}
+	response := publishSchemas{ID: published.ID, Delivered: published.Delivered}
+	if err != nil {
+		response.Error = err.Error()
+		w.WriteHeader(http.StatusMultiStatus)
+	}
+	writeJSON(w, response)
+}
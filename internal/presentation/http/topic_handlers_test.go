@@ -0,0 +1,156 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/kukwuka/queue/internal/domain"
+	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+const topicName = "topic"
+
+func (s *handlerTestSuite) TestSubscribeHandler_Success() {
+	req, err := http.NewRequest(http.MethodPost, "/topic/"+topicName+"/subscriptions",
+		bytes.NewBufferString(`{"queue": "`+queueName+`"}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	topicsInstance := mocks.NewTopics(s.T())
+	topicsInstance.
+		EXPECT().
+		Subscribe(topicName, queueName).
+		Return(nil).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), topicsInstance, mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusCreated, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestSubscribeHandler_EmptyQueue() {
+	req, err := http.NewRequest(http.MethodPost, "/topic/"+topicName+"/subscriptions", bytes.NewBufferString(`{}`))
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.Equal("queue must not be empty\n", response.Body.String())
+}
+
+func (s *handlerTestSuite) TestUnsubscribeHandler_NotFound() {
+	req, err := http.NewRequest(http.MethodDelete, "/topic/"+topicName+"/subscriptions/"+queueName, nil)
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	topicsInstance := mocks.NewTopics(s.T())
+	topicsInstance.
+		EXPECT().
+		Unsubscribe(topicName, queueName).
+		Return(domain.ErrSubscriptionNotFound).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), topicsInstance, mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusNotFound, response.Code)
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestSubscriptionsHandler_Success() {
+	req, err := http.NewRequest(http.MethodGet, "/topic/"+topicName+"/subscriptions", nil)
+	s.Require().NoError(err)
+	response := httptest.NewRecorder()
+
+	topicsInstance := mocks.NewTopics(s.T())
+	topicsInstance.
+		EXPECT().
+		Subscriptions(topicName).
+		Return([]string{"first", "second"}).
+		Once()
+	logger := slog.New(slog.NewJSONHandler(bytes.NewBuffer(nil), nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), topicsInstance, mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"queues": ["first", "second"]}`, response.Body.String())
+}
+
+func (s *handlerTestSuite) TestPublishHandler_Success() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodPut, "/topic/"+topicName, bytes.NewBufferString(`{"message": "message"}`))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	req.Header.Set("Idempotency-Key", "key")
+	response := httptest.NewRecorder()
+
+	topicsInstance := mocks.NewTopics(s.T())
+	topicsInstance.
+		EXPECT().
+		Publish(ctx, topicName, domain.Message{Body: []byte("message")}, domain.PutOptions{IdempotencyKey: "key"}).
+		Return(domain.Published{ID: "id", Delivered: 2}, nil).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), topicsInstance, mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"id": "id", "delivered": 2}`, response.Body.String())
+	s.Zero(buffer.String())
+}
+
+func (s *handlerTestSuite) TestPublishHandler_PartialFailure() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodPut, "/topic/"+topicName, bytes.NewBufferString(`{"message": "message"}`))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	topicsInstance := mocks.NewTopics(s.T())
+	topicsInstance.
+		EXPECT().
+		Publish(ctx, topicName, domain.Message{Body: []byte("message")}, domain.PutOptions{}).
+		Return(
+			domain.Published{ID: "id", Delivered: 1},
+			fmt.Errorf("publish to topic %s: queue full: %w", topicName, domain.ErrQueueFull),
+		).
+		Once()
+	logger := slog.New(slog.NewJSONHandler(bytes.NewBuffer(nil), nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), topicsInstance, mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusMultiStatus, response.Code)
+	s.JSONEq(
+		`{"id": "id", "delivered": 1, "error": "publish to topic topic: queue full: queue is full"}`,
+		response.Body.String(),
+	)
+}
+
+func (s *handlerTestSuite) TestPublishHandler_ErrQueueFull() {
+	ctx := context.Background()
+	req, err := http.NewRequest(http.MethodPut, "/topic/"+topicName, bytes.NewBufferString(`{"message": "message"}`))
+	s.Require().NoError(err)
+	req = req.WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	topicsInstance := mocks.NewTopics(s.T())
+	topicsInstance.
+		EXPECT().
+		Publish(ctx, topicName, domain.Message{Body: []byte("message")}, domain.PutOptions{}).
+		Return(domain.Published{ID: "id"}, domain.ErrQueueFull).
+		Once()
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	mux := appHTTP.NewRouter(mocks.NewQueues(s.T()), topicsInstance, mocks.NewExchanges(s.T()), logger)
+	mux.ServeHTTP(response, req)
+	s.Equal(http.StatusInsufficientStorage, response.Code)
+	s.Zero(buffer.String())
+}
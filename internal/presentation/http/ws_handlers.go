@@ -0,0 +1,365 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/infrastructure/websocket"
+)
+
+// WebSocket: одно соединение на все очереди, команды и ответы - JSON сообщения.
+// Сообщения выдаются в аренду, подтверждаются командой ack, неподтвержденные к закрытию соединения
+// возвращаются в очередь.
+
+const (
+	wsPingInterval = 30 * time.Second
+	wsWriteTimeout = 10 * time.Second
+	// wsMaxCommands Сколько команд соединения выполняется одновременно, остальные ждут в сокете.
+	wsMaxCommands       = 16
+	wsDefaultVisibility = 30 * time.Second
+)
+
+// wsCommand Id задает клиент, ответ и сообщения подписки приходят с тем же id.
+// Timeout и visibility в секундах: сколько ждать сообщение в get и на сколько его арендовать.
+// Prefetch Сколько неподтвержденных сообщений подписки может быть у клиента, по умолчанию одно.
+type wsCommand struct {
+	ID          string            `json:"id"`
+	Op          string            `json:"op"`
+	Queue       string            `json:"queue"`
+	Message     string            `json:"message"`
+	Headers     map[string]string `json:"headers"`
+	ContentType string            `json:"contentType"`
+	Receipt     string            `json:"receipt"`
+	Timeout     int               `json:"timeout"`
+	Visibility  int               `json:"visibility"`
+	Prefetch    int               `json:"prefetch"`
+	// IdempotencyKey Как заголовок Idempotency-Key у PUT.
+	IdempotencyKey string `json:"idempotencyKey"`
+	putOptionsSchemas
+}
+
+// wsReply Op: ok - команда выполнена, message - сообщение для get или подписки, error - ошибка,
+// status в ней тот же, что ответила бы HTTP ручка.
+type wsReply struct {
+	ID            string            `json:"id,omitempty"`
+	Op            string            `json:"op"`
+	Queue         string            `json:"queue,omitempty"`
+	MessageID     string            `json:"messageId,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	ContentType   string            `json:"contentType,omitempty"`
+	DeliveryCount int               `json:"deliveryCount,omitempty"`
+	Receipt       string            `json:"receipt,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	Status        int               `json:"status,omitempty"`
+	bodySchemas
+}
+
+func newWebSocketHandler(queues domain.Queues, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r, websocket.Options{
+			ReadTimeout:  2 * wsPingInterval,
+			WriteTimeout: wsWriteTimeout,
+		})
+		if err != nil {
+			if errors.Is(err, websocket.ErrBadHandshake) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			logger.Error(fmt.Errorf("websocket handler: %w", err).Error())
+			return
+		}
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		session := &wsSession{
+			conn:     conn,
+			queues:   queues,
+			logger:   logger,
+			commands: make(chan struct{}, wsMaxCommands),
+			leases:   newWSLeases(),
+			wg:       &sync.WaitGroup{},
+		}
+		session.run(ctx, cancel)
+	}
+}
+
+type wsSession struct {
+	conn   *websocket.Conn
+	queues domain.Queues
+	logger *slog.Logger
+	// Commands Семафор на одновременные команды, пока он занят, следующую команду не читаем.
+	commands chan struct{}
+	leases   *wsLeases
+	wg       *sync.WaitGroup
+}
+
+// run Читает команды, пока соединение живо, потом дожидается начатых и возвращает неподтвержденное.
+func (session *wsSession) run(ctx context.Context, cancel func()) {
+	go session.keepAlive(ctx)
+	closeCode, reason := session.readCommands(ctx)
+	cancel()
+	session.wg.Wait()
+	session.leases.returnAll(session.queues)
+	_ = session.conn.Close(closeCode, reason)
+}
+
+func (session *wsSession) readCommands(ctx context.Context) (websocket.CloseCode, string) {
+	for {
+		_, payload, err := session.conn.ReadMessage()
+		if err != nil {
+			return closeCodeOf(err), err.Error()
+		}
+		var command wsCommand
+		err = json.Unmarshal(payload, &command)
+		if err != nil {
+			session.reply(wsReply{Op: "error", Error: err.Error(), Status: http.StatusBadRequest})
+			continue
+		}
+		select {
+		case session.commands <- struct{}{}:
+		case <-ctx.Done():
+			return websocket.CloseGoingAway, "shutting down"
+		}
+		session.wg.Add(1)
+		go func() {
+			defer session.wg.Done()
+			defer func() { <-session.commands }()
+			session.execute(ctx, command)
+		}()
+	}
+}
+
+func closeCodeOf(err error) websocket.CloseCode {
+	switch {
+	case errors.Is(err, websocket.ErrMessageTooLarge):
+		return websocket.CloseMessageTooLarge
+	case errors.Is(err, websocket.ErrProtocol):
+		return websocket.CloseProtocolError
+	default:
+		return websocket.CloseNormal
+	}
+}
+
+// keepAlive Клиент отвечает на Ping сам, а без ответа соединение закроется по таймауту чтения.
+func (session *wsSession) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = session.conn.Ping()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (session *wsSession) execute(ctx context.Context, command wsCommand) {
+	var err error
+	switch command.Op {
+	case "put":
+		err = session.put(ctx, command)
+	case "get":
+		err = session.get(ctx, command)
+	case "subscribe":
+		session.subscribe(ctx, command)
+	case "ack":
+		err = session.ack(command)
+	default:
+		session.reply(wsReply{ID: command.ID, Op: "error", Error: "unknown op", Status: http.StatusBadRequest})
+		return
+	}
+	if err != nil {
+		session.replyError(ctx, command.ID, err)
+	}
+}
+
+func (session *wsSession) put(ctx context.Context, command wsCommand) error {
+	options, err := command.putOptions()
+	if err != nil {
+		return fmt.Errorf("%w: %w", errBadCommand, err)
+	}
+	options.IdempotencyKey = command.IdempotencyKey
+	messageID, err := session.queues.PutMessageToQueue(ctx, command.Queue, command.message(), options)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	session.reply(wsReply{ID: command.ID, Op: "ok", MessageID: messageID})
+	return nil
+}
+
+// get Как GET с visibility: без timeout ждет, пока не придет сообщение или не закроется соединение.
+func (session *wsSession) get(ctx context.Context, command wsCommand) error {
+	if command.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Second*time.Duration(command.Timeout))
+		defer cancel()
+	}
+	lease, err := session.queues.LeaseMessageFromQueue(ctx, command.Queue, command.visibility())
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	session.leases.add(command.Queue, lease, noop)
+	session.deliver(command, lease)
+	return nil
+}
+
+// subscribe Следующее сообщение берем, только когда у клиента меньше prefetch неподтвержденных,
+// место освобождает ack или истечение аренды.
+func (session *wsSession) subscribe(ctx context.Context, command wsCommand) {
+	credits := make(chan struct{}, max(command.Prefetch, 1))
+	session.reply(wsReply{ID: command.ID, Op: "ok"})
+	session.wg.Add(1)
+	go func() {
+		defer session.wg.Done()
+		for {
+			select {
+			case credits <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			lease, err := session.queues.LeaseMessageFromQueue(ctx, command.Queue, command.visibility())
+			if err != nil {
+				session.replyError(ctx, command.ID, err)
+				return
+			}
+			session.leases.add(command.Queue, lease, func() { <-credits })
+			session.deliver(command, lease)
+		}
+	}()
+}
+
+func (session *wsSession) ack(command wsCommand) error {
+	err := session.queues.AckMessage(command.Queue, command.Receipt)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	// Сначала ответ, потом место для следующего сообщения подписки, так клиент видит их по порядку.
+	session.reply(wsReply{ID: command.ID, Op: "ok"})
+	session.leases.remove(command.Receipt)
+	return nil
+}
+
+func (session *wsSession) deliver(command wsCommand, lease domain.Lease[domain.Message]) {
+	session.reply(wsReply{
+		ID:            command.ID,
+		Op:            "message",
+		Queue:         command.Queue,
+		MessageID:     lease.Message.ID,
+		bodySchemas:   toBodySchemas(lease.Message.Body),
+		Headers:       lease.Message.Headers,
+		ContentType:   lease.Message.ContentType,
+		DeliveryCount: lease.Message.DeliveryCount,
+		Receipt:       lease.Receipt,
+	})
+}
+
+func (command wsCommand) message() domain.Message {
+	return domain.Message{Body: []byte(command.Message), Headers: command.Headers, ContentType: command.ContentType}
+}
+
+func (command wsCommand) visibility() time.Duration {
+	if command.Visibility <= 0 {
+		return wsDefaultVisibility
+	}
+	return time.Second * time.Duration(command.Visibility)
+}
+
+// errBadCommand Ошибка в самой команде, отвечаем 400.
+var errBadCommand = errors.New("bad command")
+
+// replyError Статус тот же, что у HTTP ручек, неизвестные ошибки логируем и отвечаем 500.
+// Если клиент ушел, отвечать уже некому.
+func (session *wsSession) replyError(ctx context.Context, id string, err error) {
+	if ctx.Err() != nil && errors.Is(err, domain.ErrMessageWaitTimeOut) {
+		return
+	}
+	status, known := wsErrorStatus(err)
+	if !known {
+		session.logger.Error(fmt.Errorf("websocket handler: %w", err).Error())
+		status = http.StatusInternalServerError
+	}
+	session.reply(wsReply{ID: id, Op: "error", Error: err.Error(), Status: status})
+}
+
+func wsErrorStatus(err error) (int, bool) {
+	status, known := matchErrorStatus(
+		err,
+		errorStatus{errBadCommand, http.StatusBadRequest},
+		errorStatus{domain.ErrReceiptNotFound, http.StatusNotFound},
+	)
+	if known {
+		return status, true
+	}
+	status, known = getErrorStatus(err)
+	if known {
+		return status, true
+	}
+	return putErrorStatus(err)
+}
+
+// reply Не смогли отправить - клиент не читает или ушел, тогда чтение тоже скоро упадет и сессия закончится.
+func (session *wsSession) reply(reply wsReply) {
+	payload, err := json.Marshal(reply)
+	if err != nil {
+		session.logger.Error(fmt.Errorf("websocket handler: %w", err).Error())
+		return
+	}
+	_ = session.conn.WriteMessage(websocket.OpText, payload)
+}
+
+// wsLeases Выданные через соединение сообщения, которые еще не подтвердили.
+type wsLeases struct {
+	byReceipt map[string]*wsLease
+	mu        *sync.Mutex
+}
+
+type wsLease struct {
+	queue   string
+	release func()
+	expired *time.Timer
+}
+
+func newWSLeases() *wsLeases {
+	return &wsLeases{byReceipt: make(map[string]*wsLease), mu: &sync.Mutex{}}
+}
+
+// add Release вызывается один раз: при ack, истечении аренды или закрытии соединения.
+func (leases *wsLeases) add(queueName string, lease domain.Lease[domain.Message], release func()) {
+	leases.mu.Lock()
+	defer leases.mu.Unlock()
+	leases.byReceipt[lease.Receipt] = &wsLease{
+		queue:   queueName,
+		release: release,
+		expired: time.AfterFunc(time.Until(lease.Deadline), func() { leases.remove(lease.Receipt) }),
+	}
+}
+
+func (leases *wsLeases) remove(receipt string) {
+	leases.mu.Lock()
+	lease, exist := leases.byReceipt[receipt]
+	delete(leases.byReceipt, receipt)
+	leases.mu.Unlock()
+	if exist {
+		lease.expired.Stop()
+		lease.release()
+	}
+}
+
+// returnAll Неподтвержденное возвращаем сразу, а не ждем истечения аренды.
+func (leases *wsLeases) returnAll(queues domain.Queues) {
+	leases.mu.Lock()
+	byReceipt := leases.byReceipt
+	leases.byReceipt = make(map[string]*wsLease)
+	leases.mu.Unlock()
+	for receipt, lease := range byReceipt {
+		lease.expired.Stop()
+		_ = queues.NackMessage(lease.queue, receipt)
+	}
+}
@@ -0,0 +1,146 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kukwuka/queue/internal/domain"
+	"github.com/kukwuka/queue/internal/infrastructure/websocket"
+	appHTTP "github.com/kukwuka/queue/internal/presentation/http"
+	mocks "github.com/kukwuka/queue/mocks/domain"
+)
+
+func (s *handlerTestSuite) dialWebSocket(queuesInstance domain.Queues) (*websocket.Conn, func()) {
+	logger := slog.New(slog.NewJSONHandler(bytes.NewBuffer(nil), nil))
+	mux := appHTTP.NewRouter(queuesInstance, mocks.NewTopics(s.T()), mocks.NewExchanges(s.T()), logger)
+	server := httptest.NewServer(mux)
+	conn, err := websocket.Dial(
+		context.Background(),
+		"ws"+strings.TrimPrefix(server.URL, "http")+"/ws",
+		websocket.Options{ReadTimeout: time.Second},
+	)
+	s.Require().NoError(err)
+	return conn, server.Close
+}
+
+func (s *handlerTestSuite) wsRoundTrip(conn *websocket.Conn, command string) map[string]any {
+	s.Require().NoError(conn.WriteMessage(websocket.OpText, []byte(command)))
+	return s.wsRead(conn)
+}
+
+func (s *handlerTestSuite) wsRead(conn *websocket.Conn) map[string]any {
+	_, payload, err := conn.ReadMessage()
+	s.Require().NoError(err)
+	var reply map[string]any
+	s.Require().NoError(json.Unmarshal(payload, &reply))
+	return reply
+}
+
+func (s *handlerTestSuite) TestWebSocket_PutGetAck() {
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		PutMessageToQueue(mock.Anything, queueName, domain.Message{Body: []byte("hello")}, domain.PutOptions{Priority: 2}).
+		Return("id", nil)
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(mock.Anything, queueName, 5*time.Second).
+		Return(domain.Lease[domain.Message]{
+			Message:  domain.Message{ID: "id", Body: []byte("hello"), ContentType: "text/plain", DeliveryCount: 1},
+			Receipt:  "receipt",
+			Deadline: time.Now().Add(time.Minute),
+		}, nil)
+	queuesInstance.
+		EXPECT().
+		AckMessage(queueName, "receipt").
+		Return(nil)
+	queuesInstance.
+		EXPECT().
+		AckMessage(queueName, "unknown").
+		Return(domain.ErrReceiptNotFound)
+	conn, closeServer := s.dialWebSocket(queuesInstance)
+	defer closeServer()
+	defer conn.Close(websocket.CloseNormal, "")
+
+	s.Equal(
+		map[string]any{"id": "1", "op": "ok", "messageId": "id"},
+		s.wsRoundTrip(conn, `{"id": "1", "op": "put", "queue": "test", "message": "hello", "priority": 2}`),
+	)
+	s.Equal(
+		map[string]any{
+			"id": "2", "op": "message", "queue": "test", "messageId": "id", "message": "hello",
+			"contentType": "text/plain", "deliveryCount": float64(1), "receipt": "receipt",
+		},
+		s.wsRoundTrip(conn, `{"id": "2", "op": "get", "queue": "test", "visibility": 5}`),
+	)
+	s.Equal(
+		map[string]any{"id": "3", "op": "ok"},
+		s.wsRoundTrip(conn, `{"id": "3", "op": "ack", "queue": "test", "receipt": "receipt"}`),
+	)
+	s.Equal(
+		map[string]any{"id": "4", "op": "error", "error": "receipt not found", "status": float64(404)},
+		s.wsRoundTrip(conn, `{"id": "4", "op": "ack", "queue": "test", "receipt": "unknown"}`),
+	)
+	s.Equal(
+		map[string]any{"id": "5", "op": "error", "error": "unknown op", "status": float64(400)},
+		s.wsRoundTrip(conn, `{"id": "5", "op": "pop"}`),
+	)
+}
+
+// С prefetch 1 следующее сообщение приходит только после ack, неподтвержденное возвращается при закрытии.
+func (s *handlerTestSuite) TestWebSocket_SubscribeBackpressure() {
+	deadline := time.Now().Add(time.Minute)
+	first, second := []byte("first"), []byte("second")
+	queuesInstance := mocks.NewQueues(s.T())
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(mock.Anything, queueName, mock.Anything).
+		Return(domain.Lease[domain.Message]{Message: domain.Message{Body: first}, Receipt: "r1", Deadline: deadline}, nil).
+		Once()
+	queuesInstance.
+		EXPECT().
+		LeaseMessageFromQueue(mock.Anything, queueName, mock.Anything).
+		Return(domain.Lease[domain.Message]{Message: domain.Message{Body: second}, Receipt: "r2", Deadline: deadline}, nil).
+		Once()
+	queuesInstance.
+		EXPECT().
+		AckMessage(queueName, "r1").
+		Return(nil)
+	returned := make(chan struct{})
+	queuesInstance.
+		EXPECT().
+		NackMessage(queueName, "r2").
+		RunAndReturn(func(string, string) error {
+			close(returned)
+			return nil
+		})
+	conn, closeServer := s.dialWebSocket(queuesInstance)
+	defer closeServer()
+
+	s.Equal(
+		map[string]any{"id": "sub", "op": "ok"},
+		s.wsRoundTrip(conn, `{"id": "sub", "op": "subscribe", "queue": "test", "prefetch": 1}`),
+	)
+	s.Equal("first", s.wsRead(conn)["message"])
+	// Пока первое не подтверждено, второе не берем: следующим ответом будет ack.
+	time.Sleep(50 * time.Millisecond)
+	s.Equal(
+		map[string]any{"id": "ack", "op": "ok"},
+		s.wsRoundTrip(conn, `{"id": "ack", "op": "ack", "queue": "test", "receipt": "r1"}`),
+	)
+	s.Equal("second", s.wsRead(conn)["message"])
+
+	s.Require().NoError(conn.Close(websocket.CloseNormal, ""))
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		s.Fail("unacked message is not returned")
+	}
+}
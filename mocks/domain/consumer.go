@@ -0,0 +1,152 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Consumer is an autogenerated mock type for the Consumer type
+type Consumer struct {
+	mock.Mock
+}
+
+type Consumer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Consumer) EXPECT() *Consumer_Expecter {
+	return &Consumer_Expecter{mock: &_m.Mock}
+}
+
+// GetMessage provides a mock function with given fields: ctx
+func (_m *Consumer) GetMessage(ctx context.Context) (domain.Message, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessage")
+	}
+
+	var r0 domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (domain.Message, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) domain.Message); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(domain.Message)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Consumer_GetMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessage'
+type Consumer_GetMessage_Call struct {
+	*mock.Call
+}
+
+// GetMessage is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Consumer_Expecter) GetMessage(ctx interface{}) *Consumer_GetMessage_Call {
+	return &Consumer_GetMessage_Call{Call: _e.mock.On("GetMessage", ctx)}
+}
+
+func (_c *Consumer_GetMessage_Call) Run(run func(ctx context.Context)) *Consumer_GetMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Consumer_GetMessage_Call) Return(_a0 domain.Message, _a1 error) *Consumer_GetMessage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Consumer_GetMessage_Call) RunAndReturn(run func(context.Context) (domain.Message, error)) *Consumer_GetMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessages provides a mock function with given fields: ctx, limit
+func (_m *Consumer) GetMessages(ctx context.Context, limit int) ([]domain.Message, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessages")
+	}
+
+	var r0 []domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Message, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Message); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Consumer_GetMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessages'
+type Consumer_GetMessages_Call struct {
+	*mock.Call
+}
+
+// GetMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *Consumer_Expecter) GetMessages(ctx interface{}, limit interface{}) *Consumer_GetMessages_Call {
+	return &Consumer_GetMessages_Call{Call: _e.mock.On("GetMessages", ctx, limit)}
+}
+
+func (_c *Consumer_GetMessages_Call) Run(run func(ctx context.Context, limit int)) *Consumer_GetMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Consumer_GetMessages_Call) Return(_a0 []domain.Message, _a1 error) *Consumer_GetMessages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Consumer_GetMessages_Call) RunAndReturn(run func(context.Context, int) ([]domain.Message, error)) *Consumer_GetMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewConsumer creates a new instance of Consumer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewConsumer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Consumer {
+	mock := &Consumer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeadLetter is an autogenerated mock type for the DeadLetter type
+type DeadLetter struct {
+	mock.Mock
+}
+
+type DeadLetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeadLetter) EXPECT() *DeadLetter_Expecter {
+	return &DeadLetter_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: message
+func (_m *DeadLetter) Execute(message domain.Message) {
+	_m.Called(message)
+}
+
+// DeadLetter_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type DeadLetter_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - message domain.Message
+func (_e *DeadLetter_Expecter) Execute(message interface{}) *DeadLetter_Execute_Call {
+	return &DeadLetter_Execute_Call{Call: _e.mock.On("Execute", message)}
+}
+
+func (_c *DeadLetter_Execute_Call) Run(run func(message domain.Message)) *DeadLetter_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(domain.Message))
+	})
+	return _c
+}
+
+func (_c *DeadLetter_Execute_Call) Return() *DeadLetter_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *DeadLetter_Execute_Call) RunAndReturn(run func(domain.Message)) *DeadLetter_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeadLetter creates a new instance of DeadLetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetter {
+	mock := &DeadLetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
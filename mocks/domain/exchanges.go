@@ -0,0 +1,239 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Exchanges is an autogenerated mock type for the Exchanges type
+type Exchanges struct {
+	mock.Mock
+}
+
+type Exchanges_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Exchanges) EXPECT() *Exchanges_Expecter {
+	return &Exchanges_Expecter{mock: &_m.Mock}
+}
+
+// Bind provides a mock function with given fields: exchange, binding
+func (_m *Exchanges) Bind(exchange string, binding domain.Binding) error {
+	ret := _m.Called(exchange, binding)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bind")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.Binding) error); ok {
+		r0 = rf(exchange, binding)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Exchanges_Bind_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Bind'
+type Exchanges_Bind_Call struct {
+	*mock.Call
+}
+
+// Bind is a helper method to define mock.On call
+//   - exchange string
+//   - binding domain.Binding
+func (_e *Exchanges_Expecter) Bind(exchange interface{}, binding interface{}) *Exchanges_Bind_Call {
+	return &Exchanges_Bind_Call{Call: _e.mock.On("Bind", exchange, binding)}
+}
+
+func (_c *Exchanges_Bind_Call) Run(run func(exchange string, binding domain.Binding)) *Exchanges_Bind_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(domain.Binding))
+	})
+	return _c
+}
+
+func (_c *Exchanges_Bind_Call) Return(_a0 error) *Exchanges_Bind_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Exchanges_Bind_Call) RunAndReturn(run func(string, domain.Binding) error) *Exchanges_Bind_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Bindings provides a mock function with given fields: exchange
+func (_m *Exchanges) Bindings(exchange string) []domain.Binding {
+	ret := _m.Called(exchange)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bindings")
+	}
+
+	var r0 []domain.Binding
+	if rf, ok := ret.Get(0).(func(string) []domain.Binding); ok {
+		r0 = rf(exchange)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Binding)
+		}
+	}
+
+	return r0
+}
+
+// Exchanges_Bindings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Bindings'
+type Exchanges_Bindings_Call struct {
+	*mock.Call
+}
+
+// Bindings is a helper method to define mock.On call
+//   - exchange string
+func (_e *Exchanges_Expecter) Bindings(exchange interface{}) *Exchanges_Bindings_Call {
+	return &Exchanges_Bindings_Call{Call: _e.mock.On("Bindings", exchange)}
+}
+
+func (_c *Exchanges_Bindings_Call) Run(run func(exchange string)) *Exchanges_Bindings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Exchanges_Bindings_Call) Return(_a0 []domain.Binding) *Exchanges_Bindings_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Exchanges_Bindings_Call) RunAndReturn(run func(string) []domain.Binding) *Exchanges_Bindings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Publish provides a mock function with given fields: ctx, exchange, routingKey, message, options
+func (_m *Exchanges) Publish(ctx context.Context, exchange string, routingKey string, message domain.Message, options domain.PutOptions) (domain.Published, error) {
+	ret := _m.Called(ctx, exchange, routingKey, message, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 domain.Published
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.Message, domain.PutOptions) (domain.Published, error)); ok {
+		return rf(ctx, exchange, routingKey, message, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.Message, domain.PutOptions) domain.Published); ok {
+		r0 = rf(ctx, exchange, routingKey, message, options)
+	} else {
+		r0 = ret.Get(0).(domain.Published)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.Message, domain.PutOptions) error); ok {
+		r1 = rf(ctx, exchange, routingKey, message, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Exchanges_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type Exchanges_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - exchange string
+//   - routingKey string
+//   - message domain.Message
+//   - options domain.PutOptions
+func (_e *Exchanges_Expecter) Publish(ctx interface{}, exchange interface{}, routingKey interface{}, message interface{}, options interface{}) *Exchanges_Publish_Call {
+	return &Exchanges_Publish_Call{Call: _e.mock.On("Publish", ctx, exchange, routingKey, message, options)}
+}
+
+func (_c *Exchanges_Publish_Call) Run(run func(ctx context.Context, exchange string, routingKey string, message domain.Message, options domain.PutOptions)) *Exchanges_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(domain.Message), args[4].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *Exchanges_Publish_Call) Return(_a0 domain.Published, _a1 error) *Exchanges_Publish_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Exchanges_Publish_Call) RunAndReturn(run func(context.Context, string, string, domain.Message, domain.PutOptions) (domain.Published, error)) *Exchanges_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unbind provides a mock function with given fields: exchange, binding
+func (_m *Exchanges) Unbind(exchange string, binding domain.Binding) error {
+	ret := _m.Called(exchange, binding)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unbind")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.Binding) error); ok {
+		r0 = rf(exchange, binding)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Exchanges_Unbind_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unbind'
+type Exchanges_Unbind_Call struct {
+	*mock.Call
+}
+
+// Unbind is a helper method to define mock.On call
+//   - exchange string
+//   - binding domain.Binding
+func (_e *Exchanges_Expecter) Unbind(exchange interface{}, binding interface{}) *Exchanges_Unbind_Call {
+	return &Exchanges_Unbind_Call{Call: _e.mock.On("Unbind", exchange, binding)}
+}
+
+func (_c *Exchanges_Unbind_Call) Run(run func(exchange string, binding domain.Binding)) *Exchanges_Unbind_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(domain.Binding))
+	})
+	return _c
+}
+
+func (_c *Exchanges_Unbind_Call) Return(_a0 error) *Exchanges_Unbind_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Exchanges_Unbind_Call) RunAndReturn(run func(string, domain.Binding) error) *Exchanges_Unbind_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExchanges creates a new instance of Exchanges. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExchanges(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Exchanges {
+	mock := &Exchanges{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,191 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GroupReader is an autogenerated mock type for the GroupReader type
+type GroupReader struct {
+	mock.Mock
+}
+
+type GroupReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GroupReader) EXPECT() *GroupReader_Expecter {
+	return &GroupReader_Expecter{mock: &_m.Mock}
+}
+
+// CommitOffset provides a mock function with given fields: group, offset
+func (_m *GroupReader) CommitOffset(group string, offset int64) error {
+	ret := _m.Called(group, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommitOffset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64) error); ok {
+		r0 = rf(group, offset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GroupReader_CommitOffset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CommitOffset'
+type GroupReader_CommitOffset_Call struct {
+	*mock.Call
+}
+
+// CommitOffset is a helper method to define mock.On call
+//   - group string
+//   - offset int64
+func (_e *GroupReader_Expecter) CommitOffset(group interface{}, offset interface{}) *GroupReader_CommitOffset_Call {
+	return &GroupReader_CommitOffset_Call{Call: _e.mock.On("CommitOffset", group, offset)}
+}
+
+func (_c *GroupReader_CommitOffset_Call) Run(run func(group string, offset int64)) *GroupReader_CommitOffset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *GroupReader_CommitOffset_Call) Return(_a0 error) *GroupReader_CommitOffset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GroupReader_CommitOffset_Call) RunAndReturn(run func(string, int64) error) *GroupReader_CommitOffset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReadGroup provides a mock function with given fields: ctx, group, limit
+func (_m *GroupReader) ReadGroup(ctx context.Context, group string, limit int) ([]domain.LogRecord[domain.Message], error) {
+	ret := _m.Called(ctx, group, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadGroup")
+	}
+
+	var r0 []domain.LogRecord[domain.Message]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]domain.LogRecord[domain.Message], error)); ok {
+		return rf(ctx, group, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.LogRecord[domain.Message]); ok {
+		r0 = rf(ctx, group, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LogRecord[domain.Message])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, group, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GroupReader_ReadGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadGroup'
+type GroupReader_ReadGroup_Call struct {
+	*mock.Call
+}
+
+// ReadGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - group string
+//   - limit int
+func (_e *GroupReader_Expecter) ReadGroup(ctx interface{}, group interface{}, limit interface{}) *GroupReader_ReadGroup_Call {
+	return &GroupReader_ReadGroup_Call{Call: _e.mock.On("ReadGroup", ctx, group, limit)}
+}
+
+func (_c *GroupReader_ReadGroup_Call) Run(run func(ctx context.Context, group string, limit int)) *GroupReader_ReadGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *GroupReader_ReadGroup_Call) Return(_a0 []domain.LogRecord[domain.Message], _a1 error) *GroupReader_ReadGroup_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GroupReader_ReadGroup_Call) RunAndReturn(run func(context.Context, string, int) ([]domain.LogRecord[domain.Message], error)) *GroupReader_ReadGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetOffset provides a mock function with given fields: group, position
+func (_m *GroupReader) ResetOffset(group string, position domain.OffsetPosition) error {
+	ret := _m.Called(group, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetOffset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.OffsetPosition) error); ok {
+		r0 = rf(group, position)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GroupReader_ResetOffset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetOffset'
+type GroupReader_ResetOffset_Call struct {
+	*mock.Call
+}
+
+// ResetOffset is a helper method to define mock.On call
+//   - group string
+//   - position domain.OffsetPosition
+func (_e *GroupReader_Expecter) ResetOffset(group interface{}, position interface{}) *GroupReader_ResetOffset_Call {
+	return &GroupReader_ResetOffset_Call{Call: _e.mock.On("ResetOffset", group, position)}
+}
+
+func (_c *GroupReader_ResetOffset_Call) Run(run func(group string, position domain.OffsetPosition)) *GroupReader_ResetOffset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(domain.OffsetPosition))
+	})
+	return _c
+}
+
+func (_c *GroupReader_ResetOffset_Call) Return(_a0 error) *GroupReader_ResetOffset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GroupReader_ResetOffset_Call) RunAndReturn(run func(string, domain.OffsetPosition) error) *GroupReader_ResetOffset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGroupReader creates a new instance of GroupReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGroupReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GroupReader {
+	mock := &GroupReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,188 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Leaser is an autogenerated mock type for the Leaser type
+type Leaser struct {
+	mock.Mock
+}
+
+type Leaser_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Leaser) EXPECT() *Leaser_Expecter {
+	return &Leaser_Expecter{mock: &_m.Mock}
+}
+
+// Ack provides a mock function with given fields: receipt
+func (_m *Leaser) Ack(receipt string) error {
+	ret := _m.Called(receipt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ack")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Leaser_Ack_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ack'
+type Leaser_Ack_Call struct {
+	*mock.Call
+}
+
+// Ack is a helper method to define mock.On call
+//   - receipt string
+func (_e *Leaser_Expecter) Ack(receipt interface{}) *Leaser_Ack_Call {
+	return &Leaser_Ack_Call{Call: _e.mock.On("Ack", receipt)}
+}
+
+func (_c *Leaser_Ack_Call) Run(run func(receipt string)) *Leaser_Ack_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Leaser_Ack_Call) Return(_a0 error) *Leaser_Ack_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Leaser_Ack_Call) RunAndReturn(run func(string) error) *Leaser_Ack_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseMessage provides a mock function with given fields: ctx, visibilityTimeout
+func (_m *Leaser) LeaseMessage(ctx context.Context, visibilityTimeout time.Duration) (domain.Lease[domain.Message], error) {
+	ret := _m.Called(ctx, visibilityTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseMessage")
+	}
+
+	var r0 domain.Lease[domain.Message]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (domain.Lease[domain.Message], error)); ok {
+		return rf(ctx, visibilityTimeout)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) domain.Lease[domain.Message]); ok {
+		r0 = rf(ctx, visibilityTimeout)
+	} else {
+		r0 = ret.Get(0).(domain.Lease[domain.Message])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, visibilityTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Leaser_LeaseMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseMessage'
+type Leaser_LeaseMessage_Call struct {
+	*mock.Call
+}
+
+// LeaseMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - visibilityTimeout time.Duration
+func (_e *Leaser_Expecter) LeaseMessage(ctx interface{}, visibilityTimeout interface{}) *Leaser_LeaseMessage_Call {
+	return &Leaser_LeaseMessage_Call{Call: _e.mock.On("LeaseMessage", ctx, visibilityTimeout)}
+}
+
+func (_c *Leaser_LeaseMessage_Call) Run(run func(ctx context.Context, visibilityTimeout time.Duration)) *Leaser_LeaseMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Leaser_LeaseMessage_Call) Return(_a0 domain.Lease[domain.Message], _a1 error) *Leaser_LeaseMessage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Leaser_LeaseMessage_Call) RunAndReturn(run func(context.Context, time.Duration) (domain.Lease[domain.Message], error)) *Leaser_LeaseMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Nack provides a mock function with given fields: receipt
+func (_m *Leaser) Nack(receipt string) error {
+	ret := _m.Called(receipt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Nack")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Leaser_Nack_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Nack'
+type Leaser_Nack_Call struct {
+	*mock.Call
+}
+
+// Nack is a helper method to define mock.On call
+//   - receipt string
+func (_e *Leaser_Expecter) Nack(receipt interface{}) *Leaser_Nack_Call {
+	return &Leaser_Nack_Call{Call: _e.mock.On("Nack", receipt)}
+}
+
+func (_c *Leaser_Nack_Call) Run(run func(receipt string)) *Leaser_Nack_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Leaser_Nack_Call) Return(_a0 error) *Leaser_Nack_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Leaser_Nack_Call) RunAndReturn(run func(string) error) *Leaser_Nack_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLeaser creates a new instance of Leaser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLeaser(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Leaser {
+	mock := &Leaser{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
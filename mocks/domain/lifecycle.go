@@ -0,0 +1,96 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Lifecycle is an autogenerated mock type for the Lifecycle type
+type Lifecycle struct {
+	mock.Mock
+}
+
+type Lifecycle_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Lifecycle) EXPECT() *Lifecycle_Expecter {
+	return &Lifecycle_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function with given fields:
+func (_m *Lifecycle) Close() {
+	_m.Called()
+}
+
+// Lifecycle_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type Lifecycle_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *Lifecycle_Expecter) Close() *Lifecycle_Close_Call {
+	return &Lifecycle_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *Lifecycle_Close_Call) Run(run func()) *Lifecycle_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Lifecycle_Close_Call) Return() *Lifecycle_Close_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Lifecycle_Close_Call) RunAndReturn(run func()) *Lifecycle_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Drain provides a mock function with given fields:
+func (_m *Lifecycle) Drain() {
+	_m.Called()
+}
+
+// Lifecycle_Drain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Drain'
+type Lifecycle_Drain_Call struct {
+	*mock.Call
+}
+
+// Drain is a helper method to define mock.On call
+func (_e *Lifecycle_Expecter) Drain() *Lifecycle_Drain_Call {
+	return &Lifecycle_Drain_Call{Call: _e.mock.On("Drain")}
+}
+
+func (_c *Lifecycle_Drain_Call) Run(run func()) *Lifecycle_Drain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Lifecycle_Drain_Call) Return() *Lifecycle_Drain_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Lifecycle_Drain_Call) RunAndReturn(run func()) *Lifecycle_Drain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLifecycle creates a new instance of Lifecycle. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLifecycle(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Lifecycle {
+	mock := &Lifecycle{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,173 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Managed is an autogenerated mock type for the Managed type
+type Managed struct {
+	mock.Mock
+}
+
+type Managed_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Managed) EXPECT() *Managed_Expecter {
+	return &Managed_Expecter{mock: &_m.Mock}
+}
+
+// Peek provides a mock function with given fields: limit
+func (_m *Managed) Peek(limit int) []domain.Message {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Peek")
+	}
+
+	var r0 []domain.Message
+	if rf, ok := ret.Get(0).(func(int) []domain.Message); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	return r0
+}
+
+// Managed_Peek_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Peek'
+type Managed_Peek_Call struct {
+	*mock.Call
+}
+
+// Peek is a helper method to define mock.On call
+//   - limit int
+func (_e *Managed_Expecter) Peek(limit interface{}) *Managed_Peek_Call {
+	return &Managed_Peek_Call{Call: _e.mock.On("Peek", limit)}
+}
+
+func (_c *Managed_Peek_Call) Run(run func(limit int)) *Managed_Peek_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *Managed_Peek_Call) Return(_a0 []domain.Message) *Managed_Peek_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Managed_Peek_Call) RunAndReturn(run func(int) []domain.Message) *Managed_Peek_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Purge provides a mock function with given fields:
+func (_m *Managed) Purge() int {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// Managed_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type Managed_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+func (_e *Managed_Expecter) Purge() *Managed_Purge_Call {
+	return &Managed_Purge_Call{Call: _e.mock.On("Purge")}
+}
+
+func (_c *Managed_Purge_Call) Run(run func()) *Managed_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Managed_Purge_Call) Return(_a0 int) *Managed_Purge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Managed_Purge_Call) RunAndReturn(run func() int) *Managed_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields:
+func (_m *Managed) Stats() domain.QueueStats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 domain.QueueStats
+	if rf, ok := ret.Get(0).(func() domain.QueueStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(domain.QueueStats)
+	}
+
+	return r0
+}
+
+// Managed_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type Managed_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+func (_e *Managed_Expecter) Stats() *Managed_Stats_Call {
+	return &Managed_Stats_Call{Call: _e.mock.On("Stats")}
+}
+
+func (_c *Managed_Stats_Call) Run(run func()) *Managed_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Managed_Stats_Call) Return(_a0 domain.QueueStats) *Managed_Stats_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Managed_Stats_Call) RunAndReturn(run func() domain.QueueStats) *Managed_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewManaged creates a new instance of Managed. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewManaged(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Managed {
+	mock := &Managed{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,133 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Producer is an autogenerated mock type for the Producer type
+type Producer struct {
+	mock.Mock
+}
+
+type Producer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Producer) EXPECT() *Producer_Expecter {
+	return &Producer_Expecter{mock: &_m.Mock}
+}
+
+// PutMessage provides a mock function with given fields: ctx, message, options
+func (_m *Producer) PutMessage(ctx context.Context, message domain.Message, options domain.PutOptions) error {
+	ret := _m.Called(ctx, message, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Message, domain.PutOptions) error); ok {
+		r0 = rf(ctx, message, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Producer_PutMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMessage'
+type Producer_PutMessage_Call struct {
+	*mock.Call
+}
+
+// PutMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message domain.Message
+//   - options domain.PutOptions
+func (_e *Producer_Expecter) PutMessage(ctx interface{}, message interface{}, options interface{}) *Producer_PutMessage_Call {
+	return &Producer_PutMessage_Call{Call: _e.mock.On("PutMessage", ctx, message, options)}
+}
+
+func (_c *Producer_PutMessage_Call) Run(run func(ctx context.Context, message domain.Message, options domain.PutOptions)) *Producer_PutMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Message), args[2].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *Producer_PutMessage_Call) Return(_a0 error) *Producer_PutMessage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Producer_PutMessage_Call) RunAndReturn(run func(context.Context, domain.Message, domain.PutOptions) error) *Producer_PutMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutMessages provides a mock function with given fields: ctx, messages, options
+func (_m *Producer) PutMessages(ctx context.Context, messages []domain.Message, options domain.PutOptions) error {
+	ret := _m.Called(ctx, messages, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutMessages")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Message, domain.PutOptions) error); ok {
+		r0 = rf(ctx, messages, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Producer_PutMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMessages'
+type Producer_PutMessages_Call struct {
+	*mock.Call
+}
+
+// PutMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messages []domain.Message
+//   - options domain.PutOptions
+func (_e *Producer_Expecter) PutMessages(ctx interface{}, messages interface{}, options interface{}) *Producer_PutMessages_Call {
+	return &Producer_PutMessages_Call{Call: _e.mock.On("PutMessages", ctx, messages, options)}
+}
+
+func (_c *Producer_PutMessages_Call) Run(run func(ctx context.Context, messages []domain.Message, options domain.PutOptions)) *Producer_PutMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]domain.Message), args[2].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *Producer_PutMessages_Call) Return(_a0 error) *Producer_PutMessages_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Producer_PutMessages_Call) RunAndReturn(run func(context.Context, []domain.Message, domain.PutOptions) error) *Producer_PutMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewProducer creates a new instance of Producer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProducer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Producer {
+	mock := &Producer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
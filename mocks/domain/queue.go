@@ -5,7 +5,10 @@ package mocks
 import (
 	context "context"
 
+	domain "github.com/kukwuka/queue/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Queue is an autogenerated mock type for the Queue type
@@ -21,6 +24,52 @@ func (_m *Queue) EXPECT() *Queue_Expecter {
 	return &Queue_Expecter{mock: &_m.Mock}
 }
 
+// Ack provides a mock function with given fields: receipt
+func (_m *Queue) Ack(receipt string) error {
+	ret := _m.Called(receipt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ack")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queue_Ack_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ack'
+type Queue_Ack_Call struct {
+	*mock.Call
+}
+
+// Ack is a helper method to define mock.On call
+//   - receipt string
+func (_e *Queue_Expecter) Ack(receipt interface{}) *Queue_Ack_Call {
+	return &Queue_Ack_Call{Call: _e.mock.On("Ack", receipt)}
+}
+
+func (_c *Queue_Ack_Call) Run(run func(receipt string)) *Queue_Ack_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Queue_Ack_Call) Return(_a0 error) *Queue_Ack_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queue_Ack_Call) RunAndReturn(run func(string) error) *Queue_Ack_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function with given fields:
 func (_m *Queue) Close() {
 	_m.Called()
@@ -53,23 +102,55 @@ func (_c *Queue_Close_Call) RunAndReturn(run func()) *Queue_Close_Call {
 	return _c
 }
 
+// Drain provides a mock function with given fields:
+func (_m *Queue) Drain() {
+	_m.Called()
+}
+
+// Queue_Drain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Drain'
+type Queue_Drain_Call struct {
+	*mock.Call
+}
+
+// Drain is a helper method to define mock.On call
+func (_e *Queue_Expecter) Drain() *Queue_Drain_Call {
+	return &Queue_Drain_Call{Call: _e.mock.On("Drain")}
+}
+
+func (_c *Queue_Drain_Call) Run(run func()) *Queue_Drain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Queue_Drain_Call) Return() *Queue_Drain_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Queue_Drain_Call) RunAndReturn(run func()) *Queue_Drain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMessage provides a mock function with given fields: ctx
-func (_m *Queue) GetMessage(ctx context.Context) (string, error) {
+func (_m *Queue) GetMessage(ctx context.Context) (domain.Message, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMessage")
 	}
 
-	var r0 string
+	var r0 domain.Message
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) (domain.Message, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) domain.Message); ok {
 		r0 = rf(ctx)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(domain.Message)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
@@ -99,27 +180,282 @@ func (_c *Queue_GetMessage_Call) Run(run func(ctx context.Context)) *Queue_GetMe
 	return _c
 }
 
-func (_c *Queue_GetMessage_Call) Return(_a0 string, _a1 error) *Queue_GetMessage_Call {
+func (_c *Queue_GetMessage_Call) Return(_a0 domain.Message, _a1 error) *Queue_GetMessage_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Queue_GetMessage_Call) RunAndReturn(run func(context.Context) (string, error)) *Queue_GetMessage_Call {
+func (_c *Queue_GetMessage_Call) RunAndReturn(run func(context.Context) (domain.Message, error)) *Queue_GetMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessages provides a mock function with given fields: ctx, limit
+func (_m *Queue) GetMessages(ctx context.Context, limit int) ([]domain.Message, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessages")
+	}
+
+	var r0 []domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Message, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Message); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queue_GetMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessages'
+type Queue_GetMessages_Call struct {
+	*mock.Call
+}
+
+// GetMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *Queue_Expecter) GetMessages(ctx interface{}, limit interface{}) *Queue_GetMessages_Call {
+	return &Queue_GetMessages_Call{Call: _e.mock.On("GetMessages", ctx, limit)}
+}
+
+func (_c *Queue_GetMessages_Call) Run(run func(ctx context.Context, limit int)) *Queue_GetMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Queue_GetMessages_Call) Return(_a0 []domain.Message, _a1 error) *Queue_GetMessages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queue_GetMessages_Call) RunAndReturn(run func(context.Context, int) ([]domain.Message, error)) *Queue_GetMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseMessage provides a mock function with given fields: ctx, visibilityTimeout
+func (_m *Queue) LeaseMessage(ctx context.Context, visibilityTimeout time.Duration) (domain.Lease[domain.Message], error) {
+	ret := _m.Called(ctx, visibilityTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseMessage")
+	}
+
+	var r0 domain.Lease[domain.Message]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (domain.Lease[domain.Message], error)); ok {
+		return rf(ctx, visibilityTimeout)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) domain.Lease[domain.Message]); ok {
+		r0 = rf(ctx, visibilityTimeout)
+	} else {
+		r0 = ret.Get(0).(domain.Lease[domain.Message])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, visibilityTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queue_LeaseMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseMessage'
+type Queue_LeaseMessage_Call struct {
+	*mock.Call
+}
+
+// LeaseMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - visibilityTimeout time.Duration
+func (_e *Queue_Expecter) LeaseMessage(ctx interface{}, visibilityTimeout interface{}) *Queue_LeaseMessage_Call {
+	return &Queue_LeaseMessage_Call{Call: _e.mock.On("LeaseMessage", ctx, visibilityTimeout)}
+}
+
+func (_c *Queue_LeaseMessage_Call) Run(run func(ctx context.Context, visibilityTimeout time.Duration)) *Queue_LeaseMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Queue_LeaseMessage_Call) Return(_a0 domain.Lease[domain.Message], _a1 error) *Queue_LeaseMessage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queue_LeaseMessage_Call) RunAndReturn(run func(context.Context, time.Duration) (domain.Lease[domain.Message], error)) *Queue_LeaseMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Nack provides a mock function with given fields: receipt
+func (_m *Queue) Nack(receipt string) error {
+	ret := _m.Called(receipt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Nack")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queue_Nack_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Nack'
+type Queue_Nack_Call struct {
+	*mock.Call
+}
+
+// Nack is a helper method to define mock.On call
+//   - receipt string
+func (_e *Queue_Expecter) Nack(receipt interface{}) *Queue_Nack_Call {
+	return &Queue_Nack_Call{Call: _e.mock.On("Nack", receipt)}
+}
+
+func (_c *Queue_Nack_Call) Run(run func(receipt string)) *Queue_Nack_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Queue_Nack_Call) Return(_a0 error) *Queue_Nack_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queue_Nack_Call) RunAndReturn(run func(string) error) *Queue_Nack_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Peek provides a mock function with given fields: limit
+func (_m *Queue) Peek(limit int) []domain.Message {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Peek")
+	}
+
+	var r0 []domain.Message
+	if rf, ok := ret.Get(0).(func(int) []domain.Message); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	return r0
+}
+
+// Queue_Peek_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Peek'
+type Queue_Peek_Call struct {
+	*mock.Call
+}
+
+// Peek is a helper method to define mock.On call
+//   - limit int
+func (_e *Queue_Expecter) Peek(limit interface{}) *Queue_Peek_Call {
+	return &Queue_Peek_Call{Call: _e.mock.On("Peek", limit)}
+}
+
+func (_c *Queue_Peek_Call) Run(run func(limit int)) *Queue_Peek_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *Queue_Peek_Call) Return(_a0 []domain.Message) *Queue_Peek_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queue_Peek_Call) RunAndReturn(run func(int) []domain.Message) *Queue_Peek_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PutMessage provides a mock function with given fields: ctx, message
-func (_m *Queue) PutMessage(ctx context.Context, message string) error {
-	ret := _m.Called(ctx, message)
+// Purge provides a mock function with given fields:
+func (_m *Queue) Purge() int {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// Queue_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type Queue_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+func (_e *Queue_Expecter) Purge() *Queue_Purge_Call {
+	return &Queue_Purge_Call{Call: _e.mock.On("Purge")}
+}
+
+func (_c *Queue_Purge_Call) Run(run func()) *Queue_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Queue_Purge_Call) Return(_a0 int) *Queue_Purge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queue_Purge_Call) RunAndReturn(run func() int) *Queue_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutMessage provides a mock function with given fields: ctx, message, options
+func (_m *Queue) PutMessage(ctx context.Context, message domain.Message, options domain.PutOptions) error {
+	ret := _m.Called(ctx, message, options)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PutMessage")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, message)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Message, domain.PutOptions) error); ok {
+		r0 = rf(ctx, message, options)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -134,14 +470,15 @@ type Queue_PutMessage_Call struct {
 
 // PutMessage is a helper method to define mock.On call
 //   - ctx context.Context
-//   - message string
-func (_e *Queue_Expecter) PutMessage(ctx interface{}, message interface{}) *Queue_PutMessage_Call {
-	return &Queue_PutMessage_Call{Call: _e.mock.On("PutMessage", ctx, message)}
+//   - message domain.Message
+//   - options domain.PutOptions
+func (_e *Queue_Expecter) PutMessage(ctx interface{}, message interface{}, options interface{}) *Queue_PutMessage_Call {
+	return &Queue_PutMessage_Call{Call: _e.mock.On("PutMessage", ctx, message, options)}
 }
 
-func (_c *Queue_PutMessage_Call) Run(run func(ctx context.Context, message string)) *Queue_PutMessage_Call {
+func (_c *Queue_PutMessage_Call) Run(run func(ctx context.Context, message domain.Message, options domain.PutOptions)) *Queue_PutMessage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(domain.Message), args[2].(domain.PutOptions))
 	})
 	return _c
 }
@@ -151,7 +488,100 @@ func (_c *Queue_PutMessage_Call) Return(_a0 error) *Queue_PutMessage_Call {
 	return _c
 }
 
-func (_c *Queue_PutMessage_Call) RunAndReturn(run func(context.Context, string) error) *Queue_PutMessage_Call {
+func (_c *Queue_PutMessage_Call) RunAndReturn(run func(context.Context, domain.Message, domain.PutOptions) error) *Queue_PutMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutMessages provides a mock function with given fields: ctx, messages, options
+func (_m *Queue) PutMessages(ctx context.Context, messages []domain.Message, options domain.PutOptions) error {
+	ret := _m.Called(ctx, messages, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutMessages")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Message, domain.PutOptions) error); ok {
+		r0 = rf(ctx, messages, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queue_PutMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMessages'
+type Queue_PutMessages_Call struct {
+	*mock.Call
+}
+
+// PutMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messages []domain.Message
+//   - options domain.PutOptions
+func (_e *Queue_Expecter) PutMessages(ctx interface{}, messages interface{}, options interface{}) *Queue_PutMessages_Call {
+	return &Queue_PutMessages_Call{Call: _e.mock.On("PutMessages", ctx, messages, options)}
+}
+
+func (_c *Queue_PutMessages_Call) Run(run func(ctx context.Context, messages []domain.Message, options domain.PutOptions)) *Queue_PutMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]domain.Message), args[2].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *Queue_PutMessages_Call) Return(_a0 error) *Queue_PutMessages_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queue_PutMessages_Call) RunAndReturn(run func(context.Context, []domain.Message, domain.PutOptions) error) *Queue_PutMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields:
+func (_m *Queue) Stats() domain.QueueStats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 domain.QueueStats
+	if rf, ok := ret.Get(0).(func() domain.QueueStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(domain.QueueStats)
+	}
+
+	return r0
+}
+
+// Queue_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type Queue_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+func (_e *Queue_Expecter) Stats() *Queue_Stats_Call {
+	return &Queue_Stats_Call{Call: _e.mock.On("Stats")}
+}
+
+func (_c *Queue_Stats_Call) Run(run func()) *Queue_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Queue_Stats_Call) Return(_a0 domain.QueueStats) *Queue_Stats_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queue_Stats_Call) RunAndReturn(run func() domain.QueueStats) *Queue_Stats_Call {
 	_c.Call.Return(run)
 	return _c
 }
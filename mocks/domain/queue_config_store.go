@@ -0,0 +1,128 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// QueueConfigStore is an autogenerated mock type for the QueueConfigStore type
+type QueueConfigStore struct {
+	mock.Mock
+}
+
+type QueueConfigStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *QueueConfigStore) EXPECT() *QueueConfigStore_Expecter {
+	return &QueueConfigStore_Expecter{mock: &_m.Mock}
+}
+
+// DeleteQueueConfig provides a mock function with given fields: name
+func (_m *QueueConfigStore) DeleteQueueConfig(name string) error {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteQueueConfig")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueueConfigStore_DeleteQueueConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteQueueConfig'
+type QueueConfigStore_DeleteQueueConfig_Call struct {
+	*mock.Call
+}
+
+// DeleteQueueConfig is a helper method to define mock.On call
+//   - name string
+func (_e *QueueConfigStore_Expecter) DeleteQueueConfig(name interface{}) *QueueConfigStore_DeleteQueueConfig_Call {
+	return &QueueConfigStore_DeleteQueueConfig_Call{Call: _e.mock.On("DeleteQueueConfig", name)}
+}
+
+func (_c *QueueConfigStore_DeleteQueueConfig_Call) Run(run func(name string)) *QueueConfigStore_DeleteQueueConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *QueueConfigStore_DeleteQueueConfig_Call) Return(_a0 error) *QueueConfigStore_DeleteQueueConfig_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueueConfigStore_DeleteQueueConfig_Call) RunAndReturn(run func(string) error) *QueueConfigStore_DeleteQueueConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveQueueConfig provides a mock function with given fields: name, config
+func (_m *QueueConfigStore) SaveQueueConfig(name string, config domain.QueueConfig) error {
+	ret := _m.Called(name, config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveQueueConfig")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.QueueConfig) error); ok {
+		r0 = rf(name, config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueueConfigStore_SaveQueueConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveQueueConfig'
+type QueueConfigStore_SaveQueueConfig_Call struct {
+	*mock.Call
+}
+
+// SaveQueueConfig is a helper method to define mock.On call
+//   - name string
+//   - config domain.QueueConfig
+func (_e *QueueConfigStore_Expecter) SaveQueueConfig(name interface{}, config interface{}) *QueueConfigStore_SaveQueueConfig_Call {
+	return &QueueConfigStore_SaveQueueConfig_Call{Call: _e.mock.On("SaveQueueConfig", name, config)}
+}
+
+func (_c *QueueConfigStore_SaveQueueConfig_Call) Run(run func(name string, config domain.QueueConfig)) *QueueConfigStore_SaveQueueConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(domain.QueueConfig))
+	})
+	return _c
+}
+
+func (_c *QueueConfigStore_SaveQueueConfig_Call) Return(_a0 error) *QueueConfigStore_SaveQueueConfig_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueueConfigStore_SaveQueueConfig_Call) RunAndReturn(run func(string, domain.QueueConfig) error) *QueueConfigStore_SaveQueueConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueueConfigStore creates a new instance of QueueConfigStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueueConfigStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QueueConfigStore {
+	mock := &QueueConfigStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
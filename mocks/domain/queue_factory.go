@@ -20,17 +20,17 @@ func (_m *QueueFactory) EXPECT() *QueueFactory_Expecter {
 	return &QueueFactory_Expecter{mock: &_m.Mock}
 }
 
-// Execute provides a mock function with given fields: maxLen
-func (_m *QueueFactory) Execute(maxLen int) domain.Queue {
-	ret := _m.Called(maxLen)
+// Execute provides a mock function with given fields: name, config, deadLetter
+func (_m *QueueFactory) Execute(name string, config domain.QueueConfig, deadLetter domain.DeadLetter) domain.Queue {
+	ret := _m.Called(name, config, deadLetter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Execute")
 	}
 
 	var r0 domain.Queue
-	if rf, ok := ret.Get(0).(func(int) domain.Queue); ok {
-		r0 = rf(maxLen)
+	if rf, ok := ret.Get(0).(func(string, domain.QueueConfig, domain.DeadLetter) domain.Queue); ok {
+		r0 = rf(name, config, deadLetter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Queue)
@@ -46,14 +46,16 @@ type QueueFactory_Execute_Call struct {
 }
 
 // Execute is a helper method to define mock.On call
-//   - maxLen int
-func (_e *QueueFactory_Expecter) Execute(maxLen interface{}) *QueueFactory_Execute_Call {
-	return &QueueFactory_Execute_Call{Call: _e.mock.On("Execute", maxLen)}
+//   - name string
+//   - config domain.QueueConfig
+//   - deadLetter domain.DeadLetter
+func (_e *QueueFactory_Expecter) Execute(name interface{}, config interface{}, deadLetter interface{}) *QueueFactory_Execute_Call {
+	return &QueueFactory_Execute_Call{Call: _e.mock.On("Execute", name, config, deadLetter)}
 }
 
-func (_c *QueueFactory_Execute_Call) Run(run func(maxLen int)) *QueueFactory_Execute_Call {
+func (_c *QueueFactory_Execute_Call) Run(run func(name string, config domain.QueueConfig, deadLetter domain.DeadLetter)) *QueueFactory_Execute_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int))
+		run(args[0].(string), args[1].(domain.QueueConfig), args[2].(domain.DeadLetter))
 	})
 	return _c
 }
@@ -63,7 +65,7 @@ func (_c *QueueFactory_Execute_Call) Return(_a0 domain.Queue) *QueueFactory_Exec
 	return _c
 }
 
-func (_c *QueueFactory_Execute_Call) RunAndReturn(run func(int) domain.Queue) *QueueFactory_Execute_Call {
+func (_c *QueueFactory_Execute_Call) RunAndReturn(run func(string, domain.QueueConfig, domain.DeadLetter) domain.Queue) *QueueFactory_Execute_Call {
 	_c.Call.Return(run)
 	return _c
 }
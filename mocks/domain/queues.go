@@ -5,7 +5,10 @@ package mocks
 import (
 	context "context"
 
+	domain "github.com/kukwuka/queue/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Queues is an autogenerated mock type for the Queues type
@@ -21,6 +24,53 @@ func (_m *Queues) EXPECT() *Queues_Expecter {
 	return &Queues_Expecter{mock: &_m.Mock}
 }
 
+// AckMessage provides a mock function with given fields: queueName, receipt
+func (_m *Queues) AckMessage(queueName string, receipt string) error {
+	ret := _m.Called(queueName, receipt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AckMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(queueName, receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queues_AckMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AckMessage'
+type Queues_AckMessage_Call struct {
+	*mock.Call
+}
+
+// AckMessage is a helper method to define mock.On call
+//   - queueName string
+//   - receipt string
+func (_e *Queues_Expecter) AckMessage(queueName interface{}, receipt interface{}) *Queues_AckMessage_Call {
+	return &Queues_AckMessage_Call{Call: _e.mock.On("AckMessage", queueName, receipt)}
+}
+
+func (_c *Queues_AckMessage_Call) Run(run func(queueName string, receipt string)) *Queues_AckMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Queues_AckMessage_Call) Return(_a0 error) *Queues_AckMessage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queues_AckMessage_Call) RunAndReturn(run func(string, string) error) *Queues_AckMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function with given fields:
 func (_m *Queues) Close() {
 	_m.Called()
@@ -53,23 +103,196 @@ func (_c *Queues_Close_Call) RunAndReturn(run func()) *Queues_Close_Call {
 	return _c
 }
 
+// CommitGroupOffset provides a mock function with given fields: queueName, group, offset
+func (_m *Queues) CommitGroupOffset(queueName string, group string, offset int64) error {
+	ret := _m.Called(queueName, group, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommitGroupOffset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int64) error); ok {
+		r0 = rf(queueName, group, offset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queues_CommitGroupOffset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CommitGroupOffset'
+type Queues_CommitGroupOffset_Call struct {
+	*mock.Call
+}
+
+// CommitGroupOffset is a helper method to define mock.On call
+//   - queueName string
+//   - group string
+//   - offset int64
+func (_e *Queues_Expecter) CommitGroupOffset(queueName interface{}, group interface{}, offset interface{}) *Queues_CommitGroupOffset_Call {
+	return &Queues_CommitGroupOffset_Call{Call: _e.mock.On("CommitGroupOffset", queueName, group, offset)}
+}
+
+func (_c *Queues_CommitGroupOffset_Call) Run(run func(queueName string, group string, offset int64)) *Queues_CommitGroupOffset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Queues_CommitGroupOffset_Call) Return(_a0 error) *Queues_CommitGroupOffset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queues_CommitGroupOffset_Call) RunAndReturn(run func(string, string, int64) error) *Queues_CommitGroupOffset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateQueue provides a mock function with given fields: queueName, config
+func (_m *Queues) CreateQueue(queueName string, config domain.QueueConfig) error {
+	ret := _m.Called(queueName, config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateQueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.QueueConfig) error); ok {
+		r0 = rf(queueName, config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queues_CreateQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateQueue'
+type Queues_CreateQueue_Call struct {
+	*mock.Call
+}
+
+// CreateQueue is a helper method to define mock.On call
+//   - queueName string
+//   - config domain.QueueConfig
+func (_e *Queues_Expecter) CreateQueue(queueName interface{}, config interface{}) *Queues_CreateQueue_Call {
+	return &Queues_CreateQueue_Call{Call: _e.mock.On("CreateQueue", queueName, config)}
+}
+
+func (_c *Queues_CreateQueue_Call) Run(run func(queueName string, config domain.QueueConfig)) *Queues_CreateQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(domain.QueueConfig))
+	})
+	return _c
+}
+
+func (_c *Queues_CreateQueue_Call) Return(_a0 error) *Queues_CreateQueue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queues_CreateQueue_Call) RunAndReturn(run func(string, domain.QueueConfig) error) *Queues_CreateQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteQueue provides a mock function with given fields: queueName
+func (_m *Queues) DeleteQueue(queueName string) error {
+	ret := _m.Called(queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteQueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(queueName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queues_DeleteQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteQueue'
+type Queues_DeleteQueue_Call struct {
+	*mock.Call
+}
+
+// DeleteQueue is a helper method to define mock.On call
+//   - queueName string
+func (_e *Queues_Expecter) DeleteQueue(queueName interface{}) *Queues_DeleteQueue_Call {
+	return &Queues_DeleteQueue_Call{Call: _e.mock.On("DeleteQueue", queueName)}
+}
+
+func (_c *Queues_DeleteQueue_Call) Run(run func(queueName string)) *Queues_DeleteQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Queues_DeleteQueue_Call) Return(_a0 error) *Queues_DeleteQueue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queues_DeleteQueue_Call) RunAndReturn(run func(string) error) *Queues_DeleteQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Drain provides a mock function with given fields:
+func (_m *Queues) Drain() {
+	_m.Called()
+}
+
+// Queues_Drain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Drain'
+type Queues_Drain_Call struct {
+	*mock.Call
+}
+
+// Drain is a helper method to define mock.On call
+func (_e *Queues_Expecter) Drain() *Queues_Drain_Call {
+	return &Queues_Drain_Call{Call: _e.mock.On("Drain")}
+}
+
+func (_c *Queues_Drain_Call) Run(run func()) *Queues_Drain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Queues_Drain_Call) Return() *Queues_Drain_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Queues_Drain_Call) RunAndReturn(run func()) *Queues_Drain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMessageFromQueue provides a mock function with given fields: ctx, queueName
-func (_m *Queues) GetMessageFromQueue(ctx context.Context, queueName string) (string, error) {
+func (_m *Queues) GetMessageFromQueue(ctx context.Context, queueName string) (domain.Message, error) {
 	ret := _m.Called(ctx, queueName)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMessageFromQueue")
 	}
 
-	var r0 string
+	var r0 domain.Message
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Message, error)); ok {
 		return rf(ctx, queueName)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Message); ok {
 		r0 = rf(ctx, queueName)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(domain.Message)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
@@ -100,27 +323,192 @@ func (_c *Queues_GetMessageFromQueue_Call) Run(run func(ctx context.Context, que
 	return _c
 }
 
-func (_c *Queues_GetMessageFromQueue_Call) Return(_a0 string, _a1 error) *Queues_GetMessageFromQueue_Call {
+func (_c *Queues_GetMessageFromQueue_Call) Return(_a0 domain.Message, _a1 error) *Queues_GetMessageFromQueue_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Queues_GetMessageFromQueue_Call) RunAndReturn(run func(context.Context, string) (string, error)) *Queues_GetMessageFromQueue_Call {
+func (_c *Queues_GetMessageFromQueue_Call) RunAndReturn(run func(context.Context, string) (domain.Message, error)) *Queues_GetMessageFromQueue_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PutMessageToQueue provides a mock function with given fields: ctx, queueName, message
-func (_m *Queues) PutMessageToQueue(ctx context.Context, queueName string, message string) error {
-	ret := _m.Called(ctx, queueName, message)
+// GetMessagesFromQueue provides a mock function with given fields: ctx, queueName, limit
+func (_m *Queues) GetMessagesFromQueue(ctx context.Context, queueName string, limit int) ([]domain.Message, error) {
+	ret := _m.Called(ctx, queueName, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PutMessageToQueue")
+		panic("no return value specified for GetMessagesFromQueue")
+	}
+
+	var r0 []domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]domain.Message, error)); ok {
+		return rf(ctx, queueName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.Message); ok {
+		r0 = rf(ctx, queueName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, queueName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_GetMessagesFromQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesFromQueue'
+type Queues_GetMessagesFromQueue_Call struct {
+	*mock.Call
+}
+
+// GetMessagesFromQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - limit int
+func (_e *Queues_Expecter) GetMessagesFromQueue(ctx interface{}, queueName interface{}, limit interface{}) *Queues_GetMessagesFromQueue_Call {
+	return &Queues_GetMessagesFromQueue_Call{Call: _e.mock.On("GetMessagesFromQueue", ctx, queueName, limit)}
+}
+
+func (_c *Queues_GetMessagesFromQueue_Call) Run(run func(ctx context.Context, queueName string, limit int)) *Queues_GetMessagesFromQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Queues_GetMessagesFromQueue_Call) Return(_a0 []domain.Message, _a1 error) *Queues_GetMessagesFromQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_GetMessagesFromQueue_Call) RunAndReturn(run func(context.Context, string, int) ([]domain.Message, error)) *Queues_GetMessagesFromQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseMessageFromQueue provides a mock function with given fields: ctx, queueName, visibilityTimeout
+func (_m *Queues) LeaseMessageFromQueue(ctx context.Context, queueName string, visibilityTimeout time.Duration) (domain.Lease[domain.Message], error) {
+	ret := _m.Called(ctx, queueName, visibilityTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseMessageFromQueue")
+	}
+
+	var r0 domain.Lease[domain.Message]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (domain.Lease[domain.Message], error)); ok {
+		return rf(ctx, queueName, visibilityTimeout)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) domain.Lease[domain.Message]); ok {
+		r0 = rf(ctx, queueName, visibilityTimeout)
+	} else {
+		r0 = ret.Get(0).(domain.Lease[domain.Message])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, queueName, visibilityTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_LeaseMessageFromQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseMessageFromQueue'
+type Queues_LeaseMessageFromQueue_Call struct {
+	*mock.Call
+}
+
+// LeaseMessageFromQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - visibilityTimeout time.Duration
+func (_e *Queues_Expecter) LeaseMessageFromQueue(ctx interface{}, queueName interface{}, visibilityTimeout interface{}) *Queues_LeaseMessageFromQueue_Call {
+	return &Queues_LeaseMessageFromQueue_Call{Call: _e.mock.On("LeaseMessageFromQueue", ctx, queueName, visibilityTimeout)}
+}
+
+func (_c *Queues_LeaseMessageFromQueue_Call) Run(run func(ctx context.Context, queueName string, visibilityTimeout time.Duration)) *Queues_LeaseMessageFromQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Queues_LeaseMessageFromQueue_Call) Return(_a0 domain.Lease[domain.Message], _a1 error) *Queues_LeaseMessageFromQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_LeaseMessageFromQueue_Call) RunAndReturn(run func(context.Context, string, time.Duration) (domain.Lease[domain.Message], error)) *Queues_LeaseMessageFromQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListQueues provides a mock function with given fields:
+func (_m *Queues) ListQueues() []domain.QueueInfo {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQueues")
+	}
+
+	var r0 []domain.QueueInfo
+	if rf, ok := ret.Get(0).(func() []domain.QueueInfo); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.QueueInfo)
+		}
+	}
+
+	return r0
+}
+
+// Queues_ListQueues_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQueues'
+type Queues_ListQueues_Call struct {
+	*mock.Call
+}
+
+// ListQueues is a helper method to define mock.On call
+func (_e *Queues_Expecter) ListQueues() *Queues_ListQueues_Call {
+	return &Queues_ListQueues_Call{Call: _e.mock.On("ListQueues")}
+}
+
+func (_c *Queues_ListQueues_Call) Run(run func()) *Queues_ListQueues_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Queues_ListQueues_Call) Return(_a0 []domain.QueueInfo) *Queues_ListQueues_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queues_ListQueues_Call) RunAndReturn(run func() []domain.QueueInfo) *Queues_ListQueues_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NackMessage provides a mock function with given fields: queueName, receipt
+func (_m *Queues) NackMessage(queueName string, receipt string) error {
+	ret := _m.Called(queueName, receipt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NackMessage")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, queueName, message)
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(queueName, receipt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -128,32 +516,488 @@ func (_m *Queues) PutMessageToQueue(ctx context.Context, queueName string, messa
 	return r0
 }
 
-// Queues_PutMessageToQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMessageToQueue'
-type Queues_PutMessageToQueue_Call struct {
+// Queues_NackMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NackMessage'
+type Queues_NackMessage_Call struct {
 	*mock.Call
 }
 
-// PutMessageToQueue is a helper method to define mock.On call
-//   - ctx context.Context
+// NackMessage is a helper method to define mock.On call
+//   - queueName string
+//   - receipt string
+func (_e *Queues_Expecter) NackMessage(queueName interface{}, receipt interface{}) *Queues_NackMessage_Call {
+	return &Queues_NackMessage_Call{Call: _e.mock.On("NackMessage", queueName, receipt)}
+}
+
+func (_c *Queues_NackMessage_Call) Run(run func(queueName string, receipt string)) *Queues_NackMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Queues_NackMessage_Call) Return(_a0 error) *Queues_NackMessage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queues_NackMessage_Call) RunAndReturn(run func(string, string) error) *Queues_NackMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PeekQueue provides a mock function with given fields: queueName, limit
+func (_m *Queues) PeekQueue(queueName string, limit int) ([]domain.Message, error) {
+	ret := _m.Called(queueName, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PeekQueue")
+	}
+
+	var r0 []domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]domain.Message, error)); ok {
+		return rf(queueName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []domain.Message); ok {
+		r0 = rf(queueName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(queueName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_PeekQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PeekQueue'
+type Queues_PeekQueue_Call struct {
+	*mock.Call
+}
+
+// PeekQueue is a helper method to define mock.On call
+//   - queueName string
+//   - limit int
+func (_e *Queues_Expecter) PeekQueue(queueName interface{}, limit interface{}) *Queues_PeekQueue_Call {
+	return &Queues_PeekQueue_Call{Call: _e.mock.On("PeekQueue", queueName, limit)}
+}
+
+func (_c *Queues_PeekQueue_Call) Run(run func(queueName string, limit int)) *Queues_PeekQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Queues_PeekQueue_Call) Return(_a0 []domain.Message, _a1 error) *Queues_PeekQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_PeekQueue_Call) RunAndReturn(run func(string, int) ([]domain.Message, error)) *Queues_PeekQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeQueue provides a mock function with given fields: queueName
+func (_m *Queues) PurgeQueue(queueName string) (int, error) {
+	ret := _m.Called(queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeQueue")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return rf(queueName)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(queueName)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(queueName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_PurgeQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeQueue'
+type Queues_PurgeQueue_Call struct {
+	*mock.Call
+}
+
+// PurgeQueue is a helper method to define mock.On call
+//   - queueName string
+func (_e *Queues_Expecter) PurgeQueue(queueName interface{}) *Queues_PurgeQueue_Call {
+	return &Queues_PurgeQueue_Call{Call: _e.mock.On("PurgeQueue", queueName)}
+}
+
+func (_c *Queues_PurgeQueue_Call) Run(run func(queueName string)) *Queues_PurgeQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Queues_PurgeQueue_Call) Return(_a0 int, _a1 error) *Queues_PurgeQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_PurgeQueue_Call) RunAndReturn(run func(string) (int, error)) *Queues_PurgeQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutMessageToQueue provides a mock function with given fields: ctx, queueName, message, options
+func (_m *Queues) PutMessageToQueue(ctx context.Context, queueName string, message domain.Message, options domain.PutOptions) (string, error) {
+	ret := _m.Called(ctx, queueName, message, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutMessageToQueue")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Message, domain.PutOptions) (string, error)); ok {
+		return rf(ctx, queueName, message, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Message, domain.PutOptions) string); ok {
+		r0 = rf(ctx, queueName, message, options)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Message, domain.PutOptions) error); ok {
+		r1 = rf(ctx, queueName, message, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_PutMessageToQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMessageToQueue'
+type Queues_PutMessageToQueue_Call struct {
+	*mock.Call
+}
+
+// PutMessageToQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - message domain.Message
+//   - options domain.PutOptions
+func (_e *Queues_Expecter) PutMessageToQueue(ctx interface{}, queueName interface{}, message interface{}, options interface{}) *Queues_PutMessageToQueue_Call {
+	return &Queues_PutMessageToQueue_Call{Call: _e.mock.On("PutMessageToQueue", ctx, queueName, message, options)}
+}
+
+func (_c *Queues_PutMessageToQueue_Call) Run(run func(ctx context.Context, queueName string, message domain.Message, options domain.PutOptions)) *Queues_PutMessageToQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.Message), args[3].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *Queues_PutMessageToQueue_Call) Return(_a0 string, _a1 error) *Queues_PutMessageToQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_PutMessageToQueue_Call) RunAndReturn(run func(context.Context, string, domain.Message, domain.PutOptions) (string, error)) *Queues_PutMessageToQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutMessagesToQueue provides a mock function with given fields: ctx, queueName, messages, options
+func (_m *Queues) PutMessagesToQueue(ctx context.Context, queueName string, messages []domain.Message, options domain.PutOptions) ([]string, error) {
+	ret := _m.Called(ctx, queueName, messages, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutMessagesToQueue")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.Message, domain.PutOptions) ([]string, error)); ok {
+		return rf(ctx, queueName, messages, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.Message, domain.PutOptions) []string); ok {
+		r0 = rf(ctx, queueName, messages, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []domain.Message, domain.PutOptions) error); ok {
+		r1 = rf(ctx, queueName, messages, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_PutMessagesToQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMessagesToQueue'
+type Queues_PutMessagesToQueue_Call struct {
+	*mock.Call
+}
+
+// PutMessagesToQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - messages []domain.Message
+//   - options domain.PutOptions
+func (_e *Queues_Expecter) PutMessagesToQueue(ctx interface{}, queueName interface{}, messages interface{}, options interface{}) *Queues_PutMessagesToQueue_Call {
+	return &Queues_PutMessagesToQueue_Call{Call: _e.mock.On("PutMessagesToQueue", ctx, queueName, messages, options)}
+}
+
+func (_c *Queues_PutMessagesToQueue_Call) Run(run func(ctx context.Context, queueName string, messages []domain.Message, options domain.PutOptions)) *Queues_PutMessagesToQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]domain.Message), args[3].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *Queues_PutMessagesToQueue_Call) Return(_a0 []string, _a1 error) *Queues_PutMessagesToQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_PutMessagesToQueue_Call) RunAndReturn(run func(context.Context, string, []domain.Message, domain.PutOptions) ([]string, error)) *Queues_PutMessagesToQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueueInfo provides a mock function with given fields: queueName
+func (_m *Queues) QueueInfo(queueName string) (domain.QueueInfo, error) {
+	ret := _m.Called(queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueueInfo")
+	}
+
+	var r0 domain.QueueInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.QueueInfo, error)); ok {
+		return rf(queueName)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.QueueInfo); ok {
+		r0 = rf(queueName)
+	} else {
+		r0 = ret.Get(0).(domain.QueueInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(queueName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_QueueInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueueInfo'
+type Queues_QueueInfo_Call struct {
+	*mock.Call
+}
+
+// QueueInfo is a helper method to define mock.On call
+//   - queueName string
+func (_e *Queues_Expecter) QueueInfo(queueName interface{}) *Queues_QueueInfo_Call {
+	return &Queues_QueueInfo_Call{Call: _e.mock.On("QueueInfo", queueName)}
+}
+
+func (_c *Queues_QueueInfo_Call) Run(run func(queueName string)) *Queues_QueueInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Queues_QueueInfo_Call) Return(_a0 domain.QueueInfo, _a1 error) *Queues_QueueInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_QueueInfo_Call) RunAndReturn(run func(string) (domain.QueueInfo, error)) *Queues_QueueInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReadGroupFromQueue provides a mock function with given fields: ctx, queueName, group, limit
+func (_m *Queues) ReadGroupFromQueue(ctx context.Context, queueName string, group string, limit int) ([]domain.LogRecord[domain.Message], error) {
+	ret := _m.Called(ctx, queueName, group, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadGroupFromQueue")
+	}
+
+	var r0 []domain.LogRecord[domain.Message]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]domain.LogRecord[domain.Message], error)); ok {
+		return rf(ctx, queueName, group, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []domain.LogRecord[domain.Message]); ok {
+		r0 = rf(ctx, queueName, group, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LogRecord[domain.Message])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, queueName, group, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_ReadGroupFromQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadGroupFromQueue'
+type Queues_ReadGroupFromQueue_Call struct {
+	*mock.Call
+}
+
+// ReadGroupFromQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - group string
+//   - limit int
+func (_e *Queues_Expecter) ReadGroupFromQueue(ctx interface{}, queueName interface{}, group interface{}, limit interface{}) *Queues_ReadGroupFromQueue_Call {
+	return &Queues_ReadGroupFromQueue_Call{Call: _e.mock.On("ReadGroupFromQueue", ctx, queueName, group, limit)}
+}
+
+func (_c *Queues_ReadGroupFromQueue_Call) Run(run func(ctx context.Context, queueName string, group string, limit int)) *Queues_ReadGroupFromQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *Queues_ReadGroupFromQueue_Call) Return(_a0 []domain.LogRecord[domain.Message], _a1 error) *Queues_ReadGroupFromQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_ReadGroupFromQueue_Call) RunAndReturn(run func(context.Context, string, string, int) ([]domain.LogRecord[domain.Message], error)) *Queues_ReadGroupFromQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedriveQueue provides a mock function with given fields: ctx, queueName
+func (_m *Queues) RedriveQueue(ctx context.Context, queueName string) (int, error) {
+	ret := _m.Called(ctx, queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedriveQueue")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, queueName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, queueName)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, queueName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queues_RedriveQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedriveQueue'
+type Queues_RedriveQueue_Call struct {
+	*mock.Call
+}
+
+// RedriveQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+func (_e *Queues_Expecter) RedriveQueue(ctx interface{}, queueName interface{}) *Queues_RedriveQueue_Call {
+	return &Queues_RedriveQueue_Call{Call: _e.mock.On("RedriveQueue", ctx, queueName)}
+}
+
+func (_c *Queues_RedriveQueue_Call) Run(run func(ctx context.Context, queueName string)) *Queues_RedriveQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Queues_RedriveQueue_Call) Return(_a0 int, _a1 error) *Queues_RedriveQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queues_RedriveQueue_Call) RunAndReturn(run func(context.Context, string) (int, error)) *Queues_RedriveQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetGroupOffset provides a mock function with given fields: queueName, group, position
+func (_m *Queues) ResetGroupOffset(queueName string, group string, position domain.OffsetPosition) error {
+	ret := _m.Called(queueName, group, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetGroupOffset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, domain.OffsetPosition) error); ok {
+		r0 = rf(queueName, group, position)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queues_ResetGroupOffset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetGroupOffset'
+type Queues_ResetGroupOffset_Call struct {
+	*mock.Call
+}
+
+// ResetGroupOffset is a helper method to define mock.On call
 //   - queueName string
-//   - message string
-func (_e *Queues_Expecter) PutMessageToQueue(ctx interface{}, queueName interface{}, message interface{}) *Queues_PutMessageToQueue_Call {
-	return &Queues_PutMessageToQueue_Call{Call: _e.mock.On("PutMessageToQueue", ctx, queueName, message)}
+//   - group string
+//   - position domain.OffsetPosition
+func (_e *Queues_Expecter) ResetGroupOffset(queueName interface{}, group interface{}, position interface{}) *Queues_ResetGroupOffset_Call {
+	return &Queues_ResetGroupOffset_Call{Call: _e.mock.On("ResetGroupOffset", queueName, group, position)}
 }
 
-func (_c *Queues_PutMessageToQueue_Call) Run(run func(ctx context.Context, queueName string, message string)) *Queues_PutMessageToQueue_Call {
+func (_c *Queues_ResetGroupOffset_Call) Run(run func(queueName string, group string, position domain.OffsetPosition)) *Queues_ResetGroupOffset_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(string), args[1].(string), args[2].(domain.OffsetPosition))
 	})
 	return _c
 }
 
-func (_c *Queues_PutMessageToQueue_Call) Return(_a0 error) *Queues_PutMessageToQueue_Call {
+func (_c *Queues_ResetGroupOffset_Call) Return(_a0 error) *Queues_ResetGroupOffset_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Queues_PutMessageToQueue_Call) RunAndReturn(run func(context.Context, string, string) error) *Queues_PutMessageToQueue_Call {
+func (_c *Queues_ResetGroupOffset_Call) RunAndReturn(run func(string, string, domain.OffsetPosition) error) *Queues_ResetGroupOffset_Call {
 	_c.Call.Return(run)
 	return _c
 }
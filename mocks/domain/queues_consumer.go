@@ -0,0 +1,311 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// QueuesConsumer is an autogenerated mock type for the QueuesConsumer type
+type QueuesConsumer struct {
+	mock.Mock
+}
+
+type QueuesConsumer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *QueuesConsumer) EXPECT() *QueuesConsumer_Expecter {
+	return &QueuesConsumer_Expecter{mock: &_m.Mock}
+}
+
+// CommitGroupOffset provides a mock function with given fields: queueName, group, offset
+func (_m *QueuesConsumer) CommitGroupOffset(queueName string, group string, offset int64) error {
+	ret := _m.Called(queueName, group, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommitGroupOffset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int64) error); ok {
+		r0 = rf(queueName, group, offset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueuesConsumer_CommitGroupOffset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CommitGroupOffset'
+type QueuesConsumer_CommitGroupOffset_Call struct {
+	*mock.Call
+}
+
+// CommitGroupOffset is a helper method to define mock.On call
+//   - queueName string
+//   - group string
+//   - offset int64
+func (_e *QueuesConsumer_Expecter) CommitGroupOffset(queueName interface{}, group interface{}, offset interface{}) *QueuesConsumer_CommitGroupOffset_Call {
+	return &QueuesConsumer_CommitGroupOffset_Call{Call: _e.mock.On("CommitGroupOffset", queueName, group, offset)}
+}
+
+func (_c *QueuesConsumer_CommitGroupOffset_Call) Run(run func(queueName string, group string, offset int64)) *QueuesConsumer_CommitGroupOffset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *QueuesConsumer_CommitGroupOffset_Call) Return(_a0 error) *QueuesConsumer_CommitGroupOffset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesConsumer_CommitGroupOffset_Call) RunAndReturn(run func(string, string, int64) error) *QueuesConsumer_CommitGroupOffset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessageFromQueue provides a mock function with given fields: ctx, queueName
+func (_m *QueuesConsumer) GetMessageFromQueue(ctx context.Context, queueName string) (domain.Message, error) {
+	ret := _m.Called(ctx, queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessageFromQueue")
+	}
+
+	var r0 domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Message, error)); ok {
+		return rf(ctx, queueName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Message); ok {
+		r0 = rf(ctx, queueName)
+	} else {
+		r0 = ret.Get(0).(domain.Message)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, queueName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesConsumer_GetMessageFromQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessageFromQueue'
+type QueuesConsumer_GetMessageFromQueue_Call struct {
+	*mock.Call
+}
+
+// GetMessageFromQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+func (_e *QueuesConsumer_Expecter) GetMessageFromQueue(ctx interface{}, queueName interface{}) *QueuesConsumer_GetMessageFromQueue_Call {
+	return &QueuesConsumer_GetMessageFromQueue_Call{Call: _e.mock.On("GetMessageFromQueue", ctx, queueName)}
+}
+
+func (_c *QueuesConsumer_GetMessageFromQueue_Call) Run(run func(ctx context.Context, queueName string)) *QueuesConsumer_GetMessageFromQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *QueuesConsumer_GetMessageFromQueue_Call) Return(_a0 domain.Message, _a1 error) *QueuesConsumer_GetMessageFromQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesConsumer_GetMessageFromQueue_Call) RunAndReturn(run func(context.Context, string) (domain.Message, error)) *QueuesConsumer_GetMessageFromQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessagesFromQueue provides a mock function with given fields: ctx, queueName, limit
+func (_m *QueuesConsumer) GetMessagesFromQueue(ctx context.Context, queueName string, limit int) ([]domain.Message, error) {
+	ret := _m.Called(ctx, queueName, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessagesFromQueue")
+	}
+
+	var r0 []domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]domain.Message, error)); ok {
+		return rf(ctx, queueName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.Message); ok {
+		r0 = rf(ctx, queueName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, queueName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesConsumer_GetMessagesFromQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesFromQueue'
+type QueuesConsumer_GetMessagesFromQueue_Call struct {
+	*mock.Call
+}
+
+// GetMessagesFromQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - limit int
+func (_e *QueuesConsumer_Expecter) GetMessagesFromQueue(ctx interface{}, queueName interface{}, limit interface{}) *QueuesConsumer_GetMessagesFromQueue_Call {
+	return &QueuesConsumer_GetMessagesFromQueue_Call{Call: _e.mock.On("GetMessagesFromQueue", ctx, queueName, limit)}
+}
+
+func (_c *QueuesConsumer_GetMessagesFromQueue_Call) Run(run func(ctx context.Context, queueName string, limit int)) *QueuesConsumer_GetMessagesFromQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *QueuesConsumer_GetMessagesFromQueue_Call) Return(_a0 []domain.Message, _a1 error) *QueuesConsumer_GetMessagesFromQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesConsumer_GetMessagesFromQueue_Call) RunAndReturn(run func(context.Context, string, int) ([]domain.Message, error)) *QueuesConsumer_GetMessagesFromQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReadGroupFromQueue provides a mock function with given fields: ctx, queueName, group, limit
+func (_m *QueuesConsumer) ReadGroupFromQueue(ctx context.Context, queueName string, group string, limit int) ([]domain.LogRecord[domain.Message], error) {
+	ret := _m.Called(ctx, queueName, group, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadGroupFromQueue")
+	}
+
+	var r0 []domain.LogRecord[domain.Message]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]domain.LogRecord[domain.Message], error)); ok {
+		return rf(ctx, queueName, group, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []domain.LogRecord[domain.Message]); ok {
+		r0 = rf(ctx, queueName, group, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LogRecord[domain.Message])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, queueName, group, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesConsumer_ReadGroupFromQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadGroupFromQueue'
+type QueuesConsumer_ReadGroupFromQueue_Call struct {
+	*mock.Call
+}
+
+// ReadGroupFromQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - group string
+//   - limit int
+func (_e *QueuesConsumer_Expecter) ReadGroupFromQueue(ctx interface{}, queueName interface{}, group interface{}, limit interface{}) *QueuesConsumer_ReadGroupFromQueue_Call {
+	return &QueuesConsumer_ReadGroupFromQueue_Call{Call: _e.mock.On("ReadGroupFromQueue", ctx, queueName, group, limit)}
+}
+
+func (_c *QueuesConsumer_ReadGroupFromQueue_Call) Run(run func(ctx context.Context, queueName string, group string, limit int)) *QueuesConsumer_ReadGroupFromQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *QueuesConsumer_ReadGroupFromQueue_Call) Return(_a0 []domain.LogRecord[domain.Message], _a1 error) *QueuesConsumer_ReadGroupFromQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesConsumer_ReadGroupFromQueue_Call) RunAndReturn(run func(context.Context, string, string, int) ([]domain.LogRecord[domain.Message], error)) *QueuesConsumer_ReadGroupFromQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetGroupOffset provides a mock function with given fields: queueName, group, position
+func (_m *QueuesConsumer) ResetGroupOffset(queueName string, group string, position domain.OffsetPosition) error {
+	ret := _m.Called(queueName, group, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetGroupOffset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, domain.OffsetPosition) error); ok {
+		r0 = rf(queueName, group, position)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueuesConsumer_ResetGroupOffset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetGroupOffset'
+type QueuesConsumer_ResetGroupOffset_Call struct {
+	*mock.Call
+}
+
+// ResetGroupOffset is a helper method to define mock.On call
+//   - queueName string
+//   - group string
+//   - position domain.OffsetPosition
+func (_e *QueuesConsumer_Expecter) ResetGroupOffset(queueName interface{}, group interface{}, position interface{}) *QueuesConsumer_ResetGroupOffset_Call {
+	return &QueuesConsumer_ResetGroupOffset_Call{Call: _e.mock.On("ResetGroupOffset", queueName, group, position)}
+}
+
+func (_c *QueuesConsumer_ResetGroupOffset_Call) Run(run func(queueName string, group string, position domain.OffsetPosition)) *QueuesConsumer_ResetGroupOffset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(domain.OffsetPosition))
+	})
+	return _c
+}
+
+func (_c *QueuesConsumer_ResetGroupOffset_Call) Return(_a0 error) *QueuesConsumer_ResetGroupOffset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesConsumer_ResetGroupOffset_Call) RunAndReturn(run func(string, string, domain.OffsetPosition) error) *QueuesConsumer_ResetGroupOffset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueuesConsumer creates a new instance of QueuesConsumer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueuesConsumer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QueuesConsumer {
+	mock := &QueuesConsumer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
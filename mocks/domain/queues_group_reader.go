@@ -0,0 +1,194 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// QueuesGroupReader is an autogenerated mock type for the QueuesGroupReader type
+type QueuesGroupReader struct {
+	mock.Mock
+}
+
+type QueuesGroupReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *QueuesGroupReader) EXPECT() *QueuesGroupReader_Expecter {
+	return &QueuesGroupReader_Expecter{mock: &_m.Mock}
+}
+
+// CommitGroupOffset provides a mock function with given fields: queueName, group, offset
+func (_m *QueuesGroupReader) CommitGroupOffset(queueName string, group string, offset int64) error {
+	ret := _m.Called(queueName, group, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommitGroupOffset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int64) error); ok {
+		r0 = rf(queueName, group, offset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueuesGroupReader_CommitGroupOffset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CommitGroupOffset'
+type QueuesGroupReader_CommitGroupOffset_Call struct {
+	*mock.Call
+}
+
+// CommitGroupOffset is a helper method to define mock.On call
+//   - queueName string
+//   - group string
+//   - offset int64
+func (_e *QueuesGroupReader_Expecter) CommitGroupOffset(queueName interface{}, group interface{}, offset interface{}) *QueuesGroupReader_CommitGroupOffset_Call {
+	return &QueuesGroupReader_CommitGroupOffset_Call{Call: _e.mock.On("CommitGroupOffset", queueName, group, offset)}
+}
+
+func (_c *QueuesGroupReader_CommitGroupOffset_Call) Run(run func(queueName string, group string, offset int64)) *QueuesGroupReader_CommitGroupOffset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *QueuesGroupReader_CommitGroupOffset_Call) Return(_a0 error) *QueuesGroupReader_CommitGroupOffset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesGroupReader_CommitGroupOffset_Call) RunAndReturn(run func(string, string, int64) error) *QueuesGroupReader_CommitGroupOffset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReadGroupFromQueue provides a mock function with given fields: ctx, queueName, group, limit
+func (_m *QueuesGroupReader) ReadGroupFromQueue(ctx context.Context, queueName string, group string, limit int) ([]domain.LogRecord[domain.Message], error) {
+	ret := _m.Called(ctx, queueName, group, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadGroupFromQueue")
+	}
+
+	var r0 []domain.LogRecord[domain.Message]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]domain.LogRecord[domain.Message], error)); ok {
+		return rf(ctx, queueName, group, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []domain.LogRecord[domain.Message]); ok {
+		r0 = rf(ctx, queueName, group, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LogRecord[domain.Message])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, queueName, group, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesGroupReader_ReadGroupFromQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadGroupFromQueue'
+type QueuesGroupReader_ReadGroupFromQueue_Call struct {
+	*mock.Call
+}
+
+// ReadGroupFromQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - group string
+//   - limit int
+func (_e *QueuesGroupReader_Expecter) ReadGroupFromQueue(ctx interface{}, queueName interface{}, group interface{}, limit interface{}) *QueuesGroupReader_ReadGroupFromQueue_Call {
+	return &QueuesGroupReader_ReadGroupFromQueue_Call{Call: _e.mock.On("ReadGroupFromQueue", ctx, queueName, group, limit)}
+}
+
+func (_c *QueuesGroupReader_ReadGroupFromQueue_Call) Run(run func(ctx context.Context, queueName string, group string, limit int)) *QueuesGroupReader_ReadGroupFromQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *QueuesGroupReader_ReadGroupFromQueue_Call) Return(_a0 []domain.LogRecord[domain.Message], _a1 error) *QueuesGroupReader_ReadGroupFromQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesGroupReader_ReadGroupFromQueue_Call) RunAndReturn(run func(context.Context, string, string, int) ([]domain.LogRecord[domain.Message], error)) *QueuesGroupReader_ReadGroupFromQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetGroupOffset provides a mock function with given fields: queueName, group, position
+func (_m *QueuesGroupReader) ResetGroupOffset(queueName string, group string, position domain.OffsetPosition) error {
+	ret := _m.Called(queueName, group, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetGroupOffset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, domain.OffsetPosition) error); ok {
+		r0 = rf(queueName, group, position)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueuesGroupReader_ResetGroupOffset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetGroupOffset'
+type QueuesGroupReader_ResetGroupOffset_Call struct {
+	*mock.Call
+}
+
+// ResetGroupOffset is a helper method to define mock.On call
+//   - queueName string
+//   - group string
+//   - position domain.OffsetPosition
+func (_e *QueuesGroupReader_Expecter) ResetGroupOffset(queueName interface{}, group interface{}, position interface{}) *QueuesGroupReader_ResetGroupOffset_Call {
+	return &QueuesGroupReader_ResetGroupOffset_Call{Call: _e.mock.On("ResetGroupOffset", queueName, group, position)}
+}
+
+func (_c *QueuesGroupReader_ResetGroupOffset_Call) Run(run func(queueName string, group string, position domain.OffsetPosition)) *QueuesGroupReader_ResetGroupOffset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(domain.OffsetPosition))
+	})
+	return _c
+}
+
+func (_c *QueuesGroupReader_ResetGroupOffset_Call) Return(_a0 error) *QueuesGroupReader_ResetGroupOffset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesGroupReader_ResetGroupOffset_Call) RunAndReturn(run func(string, string, domain.OffsetPosition) error) *QueuesGroupReader_ResetGroupOffset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueuesGroupReader creates a new instance of QueuesGroupReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueuesGroupReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QueuesGroupReader {
+	mock := &QueuesGroupReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,197 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// QueuesInspector is an autogenerated mock type for the QueuesInspector type
+type QueuesInspector struct {
+	mock.Mock
+}
+
+type QueuesInspector_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *QueuesInspector) EXPECT() *QueuesInspector_Expecter {
+	return &QueuesInspector_Expecter{mock: &_m.Mock}
+}
+
+// ListQueues provides a mock function with given fields:
+func (_m *QueuesInspector) ListQueues() []domain.QueueInfo {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQueues")
+	}
+
+	var r0 []domain.QueueInfo
+	if rf, ok := ret.Get(0).(func() []domain.QueueInfo); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.QueueInfo)
+		}
+	}
+
+	return r0
+}
+
+// QueuesInspector_ListQueues_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQueues'
+type QueuesInspector_ListQueues_Call struct {
+	*mock.Call
+}
+
+// ListQueues is a helper method to define mock.On call
+func (_e *QueuesInspector_Expecter) ListQueues() *QueuesInspector_ListQueues_Call {
+	return &QueuesInspector_ListQueues_Call{Call: _e.mock.On("ListQueues")}
+}
+
+func (_c *QueuesInspector_ListQueues_Call) Run(run func()) *QueuesInspector_ListQueues_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *QueuesInspector_ListQueues_Call) Return(_a0 []domain.QueueInfo) *QueuesInspector_ListQueues_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesInspector_ListQueues_Call) RunAndReturn(run func() []domain.QueueInfo) *QueuesInspector_ListQueues_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PeekQueue provides a mock function with given fields: queueName, limit
+func (_m *QueuesInspector) PeekQueue(queueName string, limit int) ([]domain.Message, error) {
+	ret := _m.Called(queueName, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PeekQueue")
+	}
+
+	var r0 []domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]domain.Message, error)); ok {
+		return rf(queueName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []domain.Message); ok {
+		r0 = rf(queueName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(queueName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesInspector_PeekQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PeekQueue'
+type QueuesInspector_PeekQueue_Call struct {
+	*mock.Call
+}
+
+// PeekQueue is a helper method to define mock.On call
+//   - queueName string
+//   - limit int
+func (_e *QueuesInspector_Expecter) PeekQueue(queueName interface{}, limit interface{}) *QueuesInspector_PeekQueue_Call {
+	return &QueuesInspector_PeekQueue_Call{Call: _e.mock.On("PeekQueue", queueName, limit)}
+}
+
+func (_c *QueuesInspector_PeekQueue_Call) Run(run func(queueName string, limit int)) *QueuesInspector_PeekQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *QueuesInspector_PeekQueue_Call) Return(_a0 []domain.Message, _a1 error) *QueuesInspector_PeekQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesInspector_PeekQueue_Call) RunAndReturn(run func(string, int) ([]domain.Message, error)) *QueuesInspector_PeekQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueueInfo provides a mock function with given fields: queueName
+func (_m *QueuesInspector) QueueInfo(queueName string) (domain.QueueInfo, error) {
+	ret := _m.Called(queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueueInfo")
+	}
+
+	var r0 domain.QueueInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.QueueInfo, error)); ok {
+		return rf(queueName)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.QueueInfo); ok {
+		r0 = rf(queueName)
+	} else {
+		r0 = ret.Get(0).(domain.QueueInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(queueName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesInspector_QueueInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueueInfo'
+type QueuesInspector_QueueInfo_Call struct {
+	*mock.Call
+}
+
+// QueueInfo is a helper method to define mock.On call
+//   - queueName string
+func (_e *QueuesInspector_Expecter) QueueInfo(queueName interface{}) *QueuesInspector_QueueInfo_Call {
+	return &QueuesInspector_QueueInfo_Call{Call: _e.mock.On("QueueInfo", queueName)}
+}
+
+func (_c *QueuesInspector_QueueInfo_Call) Run(run func(queueName string)) *QueuesInspector_QueueInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *QueuesInspector_QueueInfo_Call) Return(_a0 domain.QueueInfo, _a1 error) *QueuesInspector_QueueInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesInspector_QueueInfo_Call) RunAndReturn(run func(string) (domain.QueueInfo, error)) *QueuesInspector_QueueInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueuesInspector creates a new instance of QueuesInspector. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueuesInspector(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QueuesInspector {
+	mock := &QueuesInspector{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
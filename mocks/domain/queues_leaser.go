@@ -0,0 +1,191 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// QueuesLeaser is an autogenerated mock type for the QueuesLeaser type
+type QueuesLeaser struct {
+	mock.Mock
+}
+
+type QueuesLeaser_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *QueuesLeaser) EXPECT() *QueuesLeaser_Expecter {
+	return &QueuesLeaser_Expecter{mock: &_m.Mock}
+}
+
+// AckMessage provides a mock function with given fields: queueName, receipt
+func (_m *QueuesLeaser) AckMessage(queueName string, receipt string) error {
+	ret := _m.Called(queueName, receipt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AckMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(queueName, receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueuesLeaser_AckMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AckMessage'
+type QueuesLeaser_AckMessage_Call struct {
+	*mock.Call
+}
+
+// AckMessage is a helper method to define mock.On call
+//   - queueName string
+//   - receipt string
+func (_e *QueuesLeaser_Expecter) AckMessage(queueName interface{}, receipt interface{}) *QueuesLeaser_AckMessage_Call {
+	return &QueuesLeaser_AckMessage_Call{Call: _e.mock.On("AckMessage", queueName, receipt)}
+}
+
+func (_c *QueuesLeaser_AckMessage_Call) Run(run func(queueName string, receipt string)) *QueuesLeaser_AckMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *QueuesLeaser_AckMessage_Call) Return(_a0 error) *QueuesLeaser_AckMessage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesLeaser_AckMessage_Call) RunAndReturn(run func(string, string) error) *QueuesLeaser_AckMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseMessageFromQueue provides a mock function with given fields: ctx, queueName, visibilityTimeout
+func (_m *QueuesLeaser) LeaseMessageFromQueue(ctx context.Context, queueName string, visibilityTimeout time.Duration) (domain.Lease[domain.Message], error) {
+	ret := _m.Called(ctx, queueName, visibilityTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseMessageFromQueue")
+	}
+
+	var r0 domain.Lease[domain.Message]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (domain.Lease[domain.Message], error)); ok {
+		return rf(ctx, queueName, visibilityTimeout)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) domain.Lease[domain.Message]); ok {
+		r0 = rf(ctx, queueName, visibilityTimeout)
+	} else {
+		r0 = ret.Get(0).(domain.Lease[domain.Message])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, queueName, visibilityTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesLeaser_LeaseMessageFromQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseMessageFromQueue'
+type QueuesLeaser_LeaseMessageFromQueue_Call struct {
+	*mock.Call
+}
+
+// LeaseMessageFromQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - visibilityTimeout time.Duration
+func (_e *QueuesLeaser_Expecter) LeaseMessageFromQueue(ctx interface{}, queueName interface{}, visibilityTimeout interface{}) *QueuesLeaser_LeaseMessageFromQueue_Call {
+	return &QueuesLeaser_LeaseMessageFromQueue_Call{Call: _e.mock.On("LeaseMessageFromQueue", ctx, queueName, visibilityTimeout)}
+}
+
+func (_c *QueuesLeaser_LeaseMessageFromQueue_Call) Run(run func(ctx context.Context, queueName string, visibilityTimeout time.Duration)) *QueuesLeaser_LeaseMessageFromQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *QueuesLeaser_LeaseMessageFromQueue_Call) Return(_a0 domain.Lease[domain.Message], _a1 error) *QueuesLeaser_LeaseMessageFromQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesLeaser_LeaseMessageFromQueue_Call) RunAndReturn(run func(context.Context, string, time.Duration) (domain.Lease[domain.Message], error)) *QueuesLeaser_LeaseMessageFromQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NackMessage provides a mock function with given fields: queueName, receipt
+func (_m *QueuesLeaser) NackMessage(queueName string, receipt string) error {
+	ret := _m.Called(queueName, receipt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NackMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(queueName, receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueuesLeaser_NackMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NackMessage'
+type QueuesLeaser_NackMessage_Call struct {
+	*mock.Call
+}
+
+// NackMessage is a helper method to define mock.On call
+//   - queueName string
+//   - receipt string
+func (_e *QueuesLeaser_Expecter) NackMessage(queueName interface{}, receipt interface{}) *QueuesLeaser_NackMessage_Call {
+	return &QueuesLeaser_NackMessage_Call{Call: _e.mock.On("NackMessage", queueName, receipt)}
+}
+
+func (_c *QueuesLeaser_NackMessage_Call) Run(run func(queueName string, receipt string)) *QueuesLeaser_NackMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *QueuesLeaser_NackMessage_Call) Return(_a0 error) *QueuesLeaser_NackMessage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesLeaser_NackMessage_Call) RunAndReturn(run func(string, string) error) *QueuesLeaser_NackMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueuesLeaser creates a new instance of QueuesLeaser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueuesLeaser(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QueuesLeaser {
+	mock := &QueuesLeaser{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
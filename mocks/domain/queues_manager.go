@@ -0,0 +1,405 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// QueuesManager is an autogenerated mock type for the QueuesManager type
+type QueuesManager struct {
+	mock.Mock
+}
+
+type QueuesManager_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *QueuesManager) EXPECT() *QueuesManager_Expecter {
+	return &QueuesManager_Expecter{mock: &_m.Mock}
+}
+
+// CreateQueue provides a mock function with given fields: queueName, config
+func (_m *QueuesManager) CreateQueue(queueName string, config domain.QueueConfig) error {
+	ret := _m.Called(queueName, config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateQueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.QueueConfig) error); ok {
+		r0 = rf(queueName, config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueuesManager_CreateQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateQueue'
+type QueuesManager_CreateQueue_Call struct {
+	*mock.Call
+}
+
+// CreateQueue is a helper method to define mock.On call
+//   - queueName string
+//   - config domain.QueueConfig
+func (_e *QueuesManager_Expecter) CreateQueue(queueName interface{}, config interface{}) *QueuesManager_CreateQueue_Call {
+	return &QueuesManager_CreateQueue_Call{Call: _e.mock.On("CreateQueue", queueName, config)}
+}
+
+func (_c *QueuesManager_CreateQueue_Call) Run(run func(queueName string, config domain.QueueConfig)) *QueuesManager_CreateQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(domain.QueueConfig))
+	})
+	return _c
+}
+
+func (_c *QueuesManager_CreateQueue_Call) Return(_a0 error) *QueuesManager_CreateQueue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesManager_CreateQueue_Call) RunAndReturn(run func(string, domain.QueueConfig) error) *QueuesManager_CreateQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteQueue provides a mock function with given fields: queueName
+func (_m *QueuesManager) DeleteQueue(queueName string) error {
+	ret := _m.Called(queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteQueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(queueName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueuesManager_DeleteQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteQueue'
+type QueuesManager_DeleteQueue_Call struct {
+	*mock.Call
+}
+
+// DeleteQueue is a helper method to define mock.On call
+//   - queueName string
+func (_e *QueuesManager_Expecter) DeleteQueue(queueName interface{}) *QueuesManager_DeleteQueue_Call {
+	return &QueuesManager_DeleteQueue_Call{Call: _e.mock.On("DeleteQueue", queueName)}
+}
+
+func (_c *QueuesManager_DeleteQueue_Call) Run(run func(queueName string)) *QueuesManager_DeleteQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *QueuesManager_DeleteQueue_Call) Return(_a0 error) *QueuesManager_DeleteQueue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesManager_DeleteQueue_Call) RunAndReturn(run func(string) error) *QueuesManager_DeleteQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListQueues provides a mock function with given fields:
+func (_m *QueuesManager) ListQueues() []domain.QueueInfo {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQueues")
+	}
+
+	var r0 []domain.QueueInfo
+	if rf, ok := ret.Get(0).(func() []domain.QueueInfo); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.QueueInfo)
+		}
+	}
+
+	return r0
+}
+
+// QueuesManager_ListQueues_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQueues'
+type QueuesManager_ListQueues_Call struct {
+	*mock.Call
+}
+
+// ListQueues is a helper method to define mock.On call
+func (_e *QueuesManager_Expecter) ListQueues() *QueuesManager_ListQueues_Call {
+	return &QueuesManager_ListQueues_Call{Call: _e.mock.On("ListQueues")}
+}
+
+func (_c *QueuesManager_ListQueues_Call) Run(run func()) *QueuesManager_ListQueues_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *QueuesManager_ListQueues_Call) Return(_a0 []domain.QueueInfo) *QueuesManager_ListQueues_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *QueuesManager_ListQueues_Call) RunAndReturn(run func() []domain.QueueInfo) *QueuesManager_ListQueues_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PeekQueue provides a mock function with given fields: queueName, limit
+func (_m *QueuesManager) PeekQueue(queueName string, limit int) ([]domain.Message, error) {
+	ret := _m.Called(queueName, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PeekQueue")
+	}
+
+	var r0 []domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]domain.Message, error)); ok {
+		return rf(queueName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []domain.Message); ok {
+		r0 = rf(queueName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(queueName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesManager_PeekQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PeekQueue'
+type QueuesManager_PeekQueue_Call struct {
+	*mock.Call
+}
+
+// PeekQueue is a helper method to define mock.On call
+//   - queueName string
+//   - limit int
+func (_e *QueuesManager_Expecter) PeekQueue(queueName interface{}, limit interface{}) *QueuesManager_PeekQueue_Call {
+	return &QueuesManager_PeekQueue_Call{Call: _e.mock.On("PeekQueue", queueName, limit)}
+}
+
+func (_c *QueuesManager_PeekQueue_Call) Run(run func(queueName string, limit int)) *QueuesManager_PeekQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *QueuesManager_PeekQueue_Call) Return(_a0 []domain.Message, _a1 error) *QueuesManager_PeekQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesManager_PeekQueue_Call) RunAndReturn(run func(string, int) ([]domain.Message, error)) *QueuesManager_PeekQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeQueue provides a mock function with given fields: queueName
+func (_m *QueuesManager) PurgeQueue(queueName string) (int, error) {
+	ret := _m.Called(queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeQueue")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return rf(queueName)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(queueName)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(queueName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesManager_PurgeQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeQueue'
+type QueuesManager_PurgeQueue_Call struct {
+	*mock.Call
+}
+
+// PurgeQueue is a helper method to define mock.On call
+//   - queueName string
+func (_e *QueuesManager_Expecter) PurgeQueue(queueName interface{}) *QueuesManager_PurgeQueue_Call {
+	return &QueuesManager_PurgeQueue_Call{Call: _e.mock.On("PurgeQueue", queueName)}
+}
+
+func (_c *QueuesManager_PurgeQueue_Call) Run(run func(queueName string)) *QueuesManager_PurgeQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *QueuesManager_PurgeQueue_Call) Return(_a0 int, _a1 error) *QueuesManager_PurgeQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesManager_PurgeQueue_Call) RunAndReturn(run func(string) (int, error)) *QueuesManager_PurgeQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueueInfo provides a mock function with given fields: queueName
+func (_m *QueuesManager) QueueInfo(queueName string) (domain.QueueInfo, error) {
+	ret := _m.Called(queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueueInfo")
+	}
+
+	var r0 domain.QueueInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.QueueInfo, error)); ok {
+		return rf(queueName)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.QueueInfo); ok {
+		r0 = rf(queueName)
+	} else {
+		r0 = ret.Get(0).(domain.QueueInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(queueName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesManager_QueueInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueueInfo'
+type QueuesManager_QueueInfo_Call struct {
+	*mock.Call
+}
+
+// QueueInfo is a helper method to define mock.On call
+//   - queueName string
+func (_e *QueuesManager_Expecter) QueueInfo(queueName interface{}) *QueuesManager_QueueInfo_Call {
+	return &QueuesManager_QueueInfo_Call{Call: _e.mock.On("QueueInfo", queueName)}
+}
+
+func (_c *QueuesManager_QueueInfo_Call) Run(run func(queueName string)) *QueuesManager_QueueInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *QueuesManager_QueueInfo_Call) Return(_a0 domain.QueueInfo, _a1 error) *QueuesManager_QueueInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesManager_QueueInfo_Call) RunAndReturn(run func(string) (domain.QueueInfo, error)) *QueuesManager_QueueInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedriveQueue provides a mock function with given fields: ctx, queueName
+func (_m *QueuesManager) RedriveQueue(ctx context.Context, queueName string) (int, error) {
+	ret := _m.Called(ctx, queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedriveQueue")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, queueName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, queueName)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, queueName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesManager_RedriveQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedriveQueue'
+type QueuesManager_RedriveQueue_Call struct {
+	*mock.Call
+}
+
+// RedriveQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+func (_e *QueuesManager_Expecter) RedriveQueue(ctx interface{}, queueName interface{}) *QueuesManager_RedriveQueue_Call {
+	return &QueuesManager_RedriveQueue_Call{Call: _e.mock.On("RedriveQueue", ctx, queueName)}
+}
+
+func (_c *QueuesManager_RedriveQueue_Call) Run(run func(ctx context.Context, queueName string)) *QueuesManager_RedriveQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *QueuesManager_RedriveQueue_Call) Return(_a0 int, _a1 error) *QueuesManager_RedriveQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesManager_RedriveQueue_Call) RunAndReturn(run func(context.Context, string) (int, error)) *QueuesManager_RedriveQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueuesManager creates a new instance of QueuesManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueuesManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QueuesManager {
+	mock := &QueuesManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// QueuesProducer is an autogenerated mock type for the QueuesProducer type
+type QueuesProducer struct {
+	mock.Mock
+}
+
+type QueuesProducer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *QueuesProducer) EXPECT() *QueuesProducer_Expecter {
+	return &QueuesProducer_Expecter{mock: &_m.Mock}
+}
+
+// PutMessageToQueue provides a mock function with given fields: ctx, queueName, message, options
+func (_m *QueuesProducer) PutMessageToQueue(ctx context.Context, queueName string, message domain.Message, options domain.PutOptions) (string, error) {
+	ret := _m.Called(ctx, queueName, message, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutMessageToQueue")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Message, domain.PutOptions) (string, error)); ok {
+		return rf(ctx, queueName, message, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Message, domain.PutOptions) string); ok {
+		r0 = rf(ctx, queueName, message, options)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Message, domain.PutOptions) error); ok {
+		r1 = rf(ctx, queueName, message, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesProducer_PutMessageToQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMessageToQueue'
+type QueuesProducer_PutMessageToQueue_Call struct {
+	*mock.Call
+}
+
+// PutMessageToQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - message domain.Message
+//   - options domain.PutOptions
+func (_e *QueuesProducer_Expecter) PutMessageToQueue(ctx interface{}, queueName interface{}, message interface{}, options interface{}) *QueuesProducer_PutMessageToQueue_Call {
+	return &QueuesProducer_PutMessageToQueue_Call{Call: _e.mock.On("PutMessageToQueue", ctx, queueName, message, options)}
+}
+
+func (_c *QueuesProducer_PutMessageToQueue_Call) Run(run func(ctx context.Context, queueName string, message domain.Message, options domain.PutOptions)) *QueuesProducer_PutMessageToQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.Message), args[3].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *QueuesProducer_PutMessageToQueue_Call) Return(_a0 string, _a1 error) *QueuesProducer_PutMessageToQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesProducer_PutMessageToQueue_Call) RunAndReturn(run func(context.Context, string, domain.Message, domain.PutOptions) (string, error)) *QueuesProducer_PutMessageToQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutMessagesToQueue provides a mock function with given fields: ctx, queueName, messages, options
+func (_m *QueuesProducer) PutMessagesToQueue(ctx context.Context, queueName string, messages []domain.Message, options domain.PutOptions) ([]string, error) {
+	ret := _m.Called(ctx, queueName, messages, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutMessagesToQueue")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.Message, domain.PutOptions) ([]string, error)); ok {
+		return rf(ctx, queueName, messages, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.Message, domain.PutOptions) []string); ok {
+		r0 = rf(ctx, queueName, messages, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []domain.Message, domain.PutOptions) error); ok {
+		r1 = rf(ctx, queueName, messages, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueuesProducer_PutMessagesToQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMessagesToQueue'
+type QueuesProducer_PutMessagesToQueue_Call struct {
+	*mock.Call
+}
+
+// PutMessagesToQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueName string
+//   - messages []domain.Message
+//   - options domain.PutOptions
+func (_e *QueuesProducer_Expecter) PutMessagesToQueue(ctx interface{}, queueName interface{}, messages interface{}, options interface{}) *QueuesProducer_PutMessagesToQueue_Call {
+	return &QueuesProducer_PutMessagesToQueue_Call{Call: _e.mock.On("PutMessagesToQueue", ctx, queueName, messages, options)}
+}
+
+func (_c *QueuesProducer_PutMessagesToQueue_Call) Run(run func(ctx context.Context, queueName string, messages []domain.Message, options domain.PutOptions)) *QueuesProducer_PutMessagesToQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]domain.Message), args[3].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *QueuesProducer_PutMessagesToQueue_Call) Return(_a0 []string, _a1 error) *QueuesProducer_PutMessagesToQueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QueuesProducer_PutMessagesToQueue_Call) RunAndReturn(run func(context.Context, string, []domain.Message, domain.PutOptions) ([]string, error)) *QueuesProducer_PutMessagesToQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueuesProducer creates a new instance of QueuesProducer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueuesProducer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QueuesProducer {
+	mock := &QueuesProducer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,238 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Topics is an autogenerated mock type for the Topics type
+type Topics struct {
+	mock.Mock
+}
+
+type Topics_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Topics) EXPECT() *Topics_Expecter {
+	return &Topics_Expecter{mock: &_m.Mock}
+}
+
+// Publish provides a mock function with given fields: ctx, topic, message, options
+func (_m *Topics) Publish(ctx context.Context, topic string, message domain.Message, options domain.PutOptions) (domain.Published, error) {
+	ret := _m.Called(ctx, topic, message, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 domain.Published
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Message, domain.PutOptions) (domain.Published, error)); ok {
+		return rf(ctx, topic, message, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Message, domain.PutOptions) domain.Published); ok {
+		r0 = rf(ctx, topic, message, options)
+	} else {
+		r0 = ret.Get(0).(domain.Published)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Message, domain.PutOptions) error); ok {
+		r1 = rf(ctx, topic, message, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Topics_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type Topics_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - topic string
+//   - message domain.Message
+//   - options domain.PutOptions
+func (_e *Topics_Expecter) Publish(ctx interface{}, topic interface{}, message interface{}, options interface{}) *Topics_Publish_Call {
+	return &Topics_Publish_Call{Call: _e.mock.On("Publish", ctx, topic, message, options)}
+}
+
+func (_c *Topics_Publish_Call) Run(run func(ctx context.Context, topic string, message domain.Message, options domain.PutOptions)) *Topics_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.Message), args[3].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *Topics_Publish_Call) Return(_a0 domain.Published, _a1 error) *Topics_Publish_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Topics_Publish_Call) RunAndReturn(run func(context.Context, string, domain.Message, domain.PutOptions) (domain.Published, error)) *Topics_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Subscribe provides a mock function with given fields: topic, queueName
+func (_m *Topics) Subscribe(topic string, queueName string) error {
+	ret := _m.Called(topic, queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(topic, queueName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Topics_Subscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribe'
+type Topics_Subscribe_Call struct {
+	*mock.Call
+}
+
+// Subscribe is a helper method to define mock.On call
+//   - topic string
+//   - queueName string
+func (_e *Topics_Expecter) Subscribe(topic interface{}, queueName interface{}) *Topics_Subscribe_Call {
+	return &Topics_Subscribe_Call{Call: _e.mock.On("Subscribe", topic, queueName)}
+}
+
+func (_c *Topics_Subscribe_Call) Run(run func(topic string, queueName string)) *Topics_Subscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Topics_Subscribe_Call) Return(_a0 error) *Topics_Subscribe_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Topics_Subscribe_Call) RunAndReturn(run func(string, string) error) *Topics_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Subscriptions provides a mock function with given fields: topic
+func (_m *Topics) Subscriptions(topic string) []string {
+	ret := _m.Called(topic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscriptions")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(topic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// Topics_Subscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscriptions'
+type Topics_Subscriptions_Call struct {
+	*mock.Call
+}
+
+// Subscriptions is a helper method to define mock.On call
+//   - topic string
+func (_e *Topics_Expecter) Subscriptions(topic interface{}) *Topics_Subscriptions_Call {
+	return &Topics_Subscriptions_Call{Call: _e.mock.On("Subscriptions", topic)}
+}
+
+func (_c *Topics_Subscriptions_Call) Run(run func(topic string)) *Topics_Subscriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Topics_Subscriptions_Call) Return(_a0 []string) *Topics_Subscriptions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Topics_Subscriptions_Call) RunAndReturn(run func(string) []string) *Topics_Subscriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unsubscribe provides a mock function with given fields: topic, queueName
+func (_m *Topics) Unsubscribe(topic string, queueName string) error {
+	ret := _m.Called(topic, queueName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unsubscribe")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(topic, queueName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Topics_Unsubscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unsubscribe'
+type Topics_Unsubscribe_Call struct {
+	*mock.Call
+}
+
+// Unsubscribe is a helper method to define mock.On call
+//   - topic string
+//   - queueName string
+func (_e *Topics_Expecter) Unsubscribe(topic interface{}, queueName interface{}) *Topics_Unsubscribe_Call {
+	return &Topics_Unsubscribe_Call{Call: _e.mock.On("Unsubscribe", topic, queueName)}
+}
+
+func (_c *Topics_Unsubscribe_Call) Run(run func(topic string, queueName string)) *Topics_Unsubscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Topics_Unsubscribe_Call) Return(_a0 error) *Topics_Unsubscribe_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Topics_Unsubscribe_Call) RunAndReturn(run func(string, string) error) *Topics_Unsubscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTopics creates a new instance of Topics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTopics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Topics {
+	mock := &Topics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
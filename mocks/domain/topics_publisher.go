@@ -0,0 +1,96 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/kukwuka/queue/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TopicsPublisher is an autogenerated mock type for the TopicsPublisher type
+type TopicsPublisher struct {
+	mock.Mock
+}
+
+type TopicsPublisher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TopicsPublisher) EXPECT() *TopicsPublisher_Expecter {
+	return &TopicsPublisher_Expecter{mock: &_m.Mock}
+}
+
+// Publish provides a mock function with given fields: ctx, topic, message, options
+func (_m *TopicsPublisher) Publish(ctx context.Context, topic string, message domain.Message, options domain.PutOptions) (domain.Published, error) {
+	ret := _m.Called(ctx, topic, message, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 domain.Published
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Message, domain.PutOptions) (domain.Published, error)); ok {
+		return rf(ctx, topic, message, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Message, domain.PutOptions) domain.Published); ok {
+		r0 = rf(ctx, topic, message, options)
+	} else {
+		r0 = ret.Get(0).(domain.Published)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Message, domain.PutOptions) error); ok {
+		r1 = rf(ctx, topic, message, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TopicsPublisher_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type TopicsPublisher_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - topic string
+//   - message domain.Message
+//   - options domain.PutOptions
+func (_e *TopicsPublisher_Expecter) Publish(ctx interface{}, topic interface{}, message interface{}, options interface{}) *TopicsPublisher_Publish_Call {
+	return &TopicsPublisher_Publish_Call{Call: _e.mock.On("Publish", ctx, topic, message, options)}
+}
+
+func (_c *TopicsPublisher_Publish_Call) Run(run func(ctx context.Context, topic string, message domain.Message, options domain.PutOptions)) *TopicsPublisher_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.Message), args[3].(domain.PutOptions))
+	})
+	return _c
+}
+
+func (_c *TopicsPublisher_Publish_Call) Return(_a0 domain.Published, _a1 error) *TopicsPublisher_Publish_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TopicsPublisher_Publish_Call) RunAndReturn(run func(context.Context, string, domain.Message, domain.PutOptions) (domain.Published, error)) *TopicsPublisher_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTopicsPublisher creates a new instance of TopicsPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTopicsPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TopicsPublisher {
+	mock := &TopicsPublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}